@@ -0,0 +1,230 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/thrift"
+	"io"
+	"math"
+	"math/bits"
+	"unsafe"
+)
+
+// Split block Bloom filters as specified by parquet-format: the bitset is
+// made of blocks of 8 32 bits words, a value sets one bit in each word of
+// the block its hash selects.
+
+const (
+	BYTES_PER_BLOCK = 32
+	MINIMUM_BYTES   = BYTES_PER_BLOCK
+	MAXIMUM_BYTES   = 128 * 1024 * 1024
+	// Enough for the thrift BloomFilterHeader
+	HEADER_SIZE_GUESS = 32
+)
+
+var salt = [8]uint32{0x47b6137b, 0x44974d91, 0x8824ad5b, 0xa2b7289d,
+	0x705495c7, 0x2df1424b, 0x9efc4947, 0x5c6bfb31}
+
+type BlockSplitBloomFilter struct {
+	bitset []uint32
+}
+
+// numBytes is rounded to a power of 2 between MINIMUM_BYTES and
+// MAXIMUM_BYTES
+func NewBlockSplitBloomFilter(numBytes int) *BlockSplitBloomFilter {
+	if numBytes < MINIMUM_BYTES {
+		numBytes = MINIMUM_BYTES
+	}
+	if numBytes > MAXIMUM_BYTES {
+		numBytes = MAXIMUM_BYTES
+	}
+	if numBytes&(numBytes-1) != 0 {
+		numBytes = 1 << uint(64-bits.LeadingZeros64(uint64(numBytes)))
+	}
+	return &BlockSplitBloomFilter{bitset: make([]uint32, numBytes/4)}
+}
+
+// The number of bytes of a filter holding ndv distinct values with a false
+// positive probability of fpp
+func OptimalNumBytes(ndv int64, fpp float64) int {
+	if fpp <= 0 || fpp >= 1 {
+		panic(fmt.Errorf("Bloom filter false positive probability must be in (0, 1): %v", fpp))
+	}
+	if ndv < 1 {
+		ndv = 1
+	}
+	numBits := -8 * float64(ndv) / math.Log(1-math.Pow(fpp, 1.0/8))
+	if numBits > MAXIMUM_BYTES*8 {
+		return MAXIMUM_BYTES
+	}
+	numBytes := int(numBits / 8)
+	if numBytes < MINIMUM_BYTES {
+		return MINIMUM_BYTES
+	}
+	return numBytes
+}
+
+func (f *BlockSplitBloomFilter) NumBytes() int {
+	return len(f.bitset) * 4
+}
+
+func (f *BlockSplitBloomFilter) block(hash uint64) ([]uint32, [8]uint32) {
+	numBlocks := uint64(len(f.bitset) / 8)
+	index := ((hash >> 32) * numBlocks) >> 32
+	key := uint32(hash)
+	var mask [8]uint32
+	for i := range mask {
+		mask[i] = 1 << ((key * salt[i]) >> 27)
+	}
+	return f.bitset[index*8 : index*8+8], mask
+}
+
+func (f *BlockSplitBloomFilter) InsertHash(hash uint64) {
+	block, mask := f.block(hash)
+	for i := range block {
+		block[i] |= mask[i]
+	}
+}
+
+// Whether a value of the hash may have been inserted, false positives are
+// possible but not false negatives
+func (f *BlockSplitBloomFilter) FindHash(hash uint64) bool {
+	block, mask := f.block(hash)
+	for i := range block {
+		if block[i]&mask[i] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BlockSplitBloomFilter) Insert(value interface{}) {
+	f.InsertHash(Hash(value))
+}
+
+func (f *BlockSplitBloomFilter) Find(value interface{}) bool {
+	return f.FindHash(Hash(value))
+}
+
+// Insert the values of a typed slice of a physical type
+func (f *BlockSplitBloomFilter) InsertValues(values interface{}) {
+	HashValues(values, f.InsertHash)
+}
+
+// Call fn with the hash of each value of a typed slice of a physical type
+func HashValues(values interface{}, fn func(hash uint64)) {
+	switch v := values.(type) {
+	case []int32:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []int64:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []ptype.Int96:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []float32:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []float64:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []ptype.ByteArray:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	case []ptype.FixedLenByteArray:
+		for _, value := range v {
+			fn(Hash(value))
+		}
+	default:
+		panic(fmt.Errorf("Bloom filters do not support values of type %T", values))
+	}
+}
+
+// The xxHash64 of the PLAIN encoding of a value, byte arrays without their
+// length
+func Hash(value interface{}) uint64 {
+	var buffer [12]byte
+	switch v := value.(type) {
+	case int32:
+		binary.LittleEndian.PutUint32(buffer[:], uint32(v))
+		return XxHash64(buffer[:4], 0)
+	case int64:
+		binary.LittleEndian.PutUint64(buffer[:], uint64(v))
+		return XxHash64(buffer[:8], 0)
+	case ptype.Int96:
+		for i, word := range v {
+			binary.LittleEndian.PutUint32(buffer[i*4:], word)
+		}
+		return XxHash64(buffer[:12], 0)
+	case float32:
+		binary.LittleEndian.PutUint32(buffer[:], math.Float32bits(v))
+		return XxHash64(buffer[:4], 0)
+	case float64:
+		binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(v))
+		return XxHash64(buffer[:8], 0)
+	case ptype.ByteArray:
+		return XxHash64(v, 0)
+	case ptype.FixedLenByteArray:
+		return XxHash64(v, 0)
+	}
+	panic(fmt.Errorf("Bloom filters do not support values of type %T", value))
+}
+
+// Write the thrift header followed by the bitset
+func (f *BlockSplitBloomFilter) WriteTo(w io.Writer) (int64, error) {
+	header := thrift.NewBloomFilterHeader()
+	header.NumBytes = int32(f.NumBytes())
+	header.Algorithm = &thrift.BloomFilterAlgorithm{BLOCK: thrift.NewSplitBlockAlgorithm()}
+	header.Hash = &thrift.BloomFilterHash{XXHASH: thrift.NewXxHash()}
+	header.Compression = &thrift.BloomFilterCompression{UNCOMPRESSED: thrift.NewUncompressed()}
+	var buffer bytes.Buffer
+	thrift.SerializeTriftMsg(header, int(unsafe.Sizeof(*header)), &buffer)
+	bitset := make([]byte, f.NumBytes())
+	for i, word := range f.bitset {
+		binary.LittleEndian.PutUint32(bitset[i*4:], word)
+	}
+	buffer.Write(bitset)
+	n, err := w.Write(buffer.Bytes())
+	return int64(n), err
+}
+
+// Read the header of a serialized filter, returns its length and the size of
+// the bitset following it
+func ReadHeader(buffer []byte) (int, int) {
+	header := thrift.NewBloomFilterHeader()
+	remaining := thrift.DeserializeThriftMsg(buffer, len(buffer), header)
+	if !header.Algorithm.IsSetBLOCK() || !header.Hash.IsSetXXHASH() ||
+		!header.Compression.IsSetUNCOMPRESSED() {
+		panic(fmt.Errorf("Unsupported Bloom filter %s", header))
+	}
+	if header.NumBytes < MINIMUM_BYTES || header.NumBytes > MAXIMUM_BYTES ||
+		header.NumBytes&(header.NumBytes-1) != 0 {
+		panic(fmt.Errorf("Invalid Bloom filter size: %d", header.NumBytes))
+	}
+	return len(buffer) - int(remaining), int(header.NumBytes)
+}
+
+// Deserialize a filter written by WriteTo, buffer holds at least the header
+// and the bitset
+func NewBlockSplitBloomFilterFromBuffer(buffer []byte) *BlockSplitBloomFilter {
+	headerSize, numBytes := ReadHeader(buffer)
+	if len(buffer) < headerSize+numBytes {
+		panic(fmt.Errorf("Bloom filter was smaller (%d) than expected (%d)",
+			len(buffer)-headerSize, numBytes))
+	}
+	bitset := buffer[headerSize : headerSize+numBytes]
+	f := &BlockSplitBloomFilter{bitset: make([]uint32, numBytes/4)}
+	for i := range f.bitset {
+		f.bitset[i] = binary.LittleEndian.Uint32(bitset[i*4:])
+	}
+	return f
+}
@@ -0,0 +1,63 @@
+package bloom
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/ptype"
+)
+
+func TestXxHash64(t *testing.T) {
+	tests := []struct {
+		data string
+		hash uint64
+	}{
+		{"", 0xEF46DB3751D8E999},
+		{"abc", 0x44BC2CF5AD770999},
+		// Longer than the 32 bytes hashed by stripes
+		{"Nobody inspects the spammish repetition", 0xFBCEA83C8A378BF1},
+	}
+	for _, test := range tests {
+		if hash := XxHash64([]byte(test.data), 0); hash != test.hash {
+			t.Errorf("XxHash64(%q) = %#x, want %#x", test.data, hash, test.hash)
+		}
+		if hash := Hash(ptype.ByteArray(test.data)); hash != test.hash {
+			t.Errorf("Hash(%q) = %#x, want %#x", test.data, hash, test.hash)
+		}
+	}
+}
+
+func TestBloomFilterRoundTrip(t *testing.T) {
+	f := NewBlockSplitBloomFilter(OptimalNumBytes(1000, 0.01))
+	for i := 0; i < 1000; i++ {
+		f.Insert(int64(i))
+		f.Insert(ptype.ByteArray(fmt.Sprintf("value %d", i)))
+	}
+	var buffer bytes.Buffer
+	if _, err := f.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read := NewBlockSplitBloomFilterFromBuffer(buffer.Bytes())
+	if read.NumBytes() != f.NumBytes() {
+		t.Fatalf("read %d bytes, want %d", read.NumBytes(), f.NumBytes())
+	}
+	for i := 0; i < 1000; i++ {
+		if !read.Find(int64(i)) {
+			t.Errorf("%d was not found", i)
+		}
+		if !read.Find(ptype.ByteArray(fmt.Sprintf("value %d", i))) {
+			t.Errorf("value %d was not found", i)
+		}
+	}
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if read.Find(int64(i)) {
+			falsePositives++
+		}
+	}
+	// 2000 values were inserted in a filter sized for 1000 at 1%
+	if falsePositives > 1000 {
+		t.Errorf("%d false positives out of 10000", falsePositives)
+	}
+}
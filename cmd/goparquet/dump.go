@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"io"
+	"reflect"
+)
+
+func runDump(flags *flag.FlagSet, args []string, out io.Writer) {
+	columnPath := flags.String("c", "", "only dump the column at this dot path")
+	maxValues := flags.Int64("n", 0, "only dump the first N levels of each column chunk, 0 for all")
+	reader := openFile(flags, args)
+	defer reader.Close()
+	columns := make([]int, reader.NumColumns())
+	for i := range columns {
+		columns[i] = i
+	}
+	if *columnPath != "" {
+		i := reader.Schema().ColumnIndex(*columnPath)
+		if i < 0 {
+			panic(fmt.Errorf("Column %s is not in the schema", *columnPath))
+		}
+		columns = []int{i}
+	}
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rowGroup := reader.RowGroup(i)
+		for _, j := range columns {
+			descr := reader.Descr(j)
+			fmt.Fprintf(out, "row group %d, column %s: %s, max definition level %d, max repetition level %d\n",
+				i, descr.Path().ToDotString(), ptype.TypeToString(descr.PhysicalType()),
+				descr.MaxDefinitionLevel(), descr.MaxRepetitionLevel())
+			dumpColumnChunk(rowGroup, j, *maxValues, out)
+		}
+	}
+}
+
+// Print the header of each page of column i, followed by the levels and
+// values of the data pages
+func dumpColumnChunk(rowGroup *file.RowGroupReader, i int, maxValues int64, out io.Writer) {
+	descr := rowGroup.Schema().Column(i)
+	reader := rowGroup.Column(i)
+	batchSize := int64(column.DEFAULT_WRITE_BATCH_SIZE)
+	defLevels := make([]int16, batchSize)
+	repLevels := make([]int16, batchSize)
+	values := encoding.MakeValues(descr.PhysicalType(), int(batchSize))
+	level := int64(0)
+	for j, header := range rowGroup.PageHeaders(i) {
+		fmt.Fprintf(out, "  page %d: %s\n", j, formatPageHeader(descr, header))
+		dataHeader := header.GetDataPageHeader()
+		if dataHeader == nil {
+			continue
+		}
+		// A batch never spans pages
+		for pageLevels := int64(0); pageLevels < int64(dataHeader.NumValues); {
+			if maxValues > 0 && level >= maxValues {
+				break
+			}
+			levelsRead, _ := reader.ReadBatch(batchSize, defLevels, repLevels, values)
+			if levelsRead == 0 {
+				break
+			}
+			valuePos := 0
+			for k := 0; k < int(levelsRead); k++ {
+				var def, rep int16
+				if descr.MaxDefinitionLevel() > 0 {
+					def = defLevels[k]
+				}
+				if descr.MaxRepetitionLevel() > 0 {
+					rep = repLevels[k]
+				}
+				var value interface{}
+				if def == descr.MaxDefinitionLevel() {
+					value = reflect.ValueOf(values).Index(valuePos).Interface()
+					valuePos++
+				}
+				if maxValues <= 0 || level < maxValues {
+					fmt.Fprintf(out, "    value %d: R:%d D:%d V:%s\n", level, rep, def,
+						formatValue(descr, value))
+				}
+				level++
+			}
+			pageLevels += levelsRead
+		}
+	}
+}
+
+func formatPageHeader(descr *schema.ColumnDescriptor, header *thrift.PageHeader) string {
+	result := fmt.Sprintf("%s, compressed size %d, uncompressed size %d", header.Type,
+		header.CompressedPageSize, header.UncompressedPageSize)
+	if header.IsSetCrc() {
+		result += fmt.Sprintf(", crc %d", header.GetCrc())
+	}
+	if dataHeader := header.GetDataPageHeader(); dataHeader != nil {
+		result += fmt.Sprintf(", values %d, encoding %s, definition levels %s, repetition levels %s",
+			dataHeader.NumValues, dataHeader.Encoding, dataHeader.DefinitionLevelEncoding,
+			dataHeader.RepetitionLevelEncoding)
+		if dataHeader.IsSetStatistics() {
+			result += ", statistics" + formatStatistics(column.NewStatisticsFromEncoded(descr,
+				column.EncodedStatisticsFromThrift(dataHeader.Statistics),
+				int64(dataHeader.NumValues)))
+		}
+	}
+	if dictionaryHeader := header.GetDictionaryPageHeader(); dictionaryHeader != nil {
+		result += fmt.Sprintf(", values %d, encoding %s", dictionaryHeader.NumValues,
+			dictionaryHeader.Encoding)
+		if dictionaryHeader.GetIsSorted() {
+			result += ", sorted"
+		}
+	}
+	if dataHeader := header.GetDataPageHeaderV2(); dataHeader != nil {
+		result += fmt.Sprintf(", values %d, nulls %d, rows %d, encoding %s", dataHeader.NumValues,
+			dataHeader.NumNulls, dataHeader.NumRows, dataHeader.Encoding)
+	}
+	return result
+}
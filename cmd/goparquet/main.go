@@ -0,0 +1,123 @@
+// Command goparquet inspects parquet files.
+//
+// Usage:
+//
+//	goparquet schema FILE            the schema in the message syntax
+//	goparquet meta FILE              the metadata of the row groups and column chunks
+//	goparquet head [-n N] FILE       the first N rows as JSON lines
+//	goparquet cat FILE               all the rows as JSON lines
+//	goparquet rowcount FILE          the number of rows
+//	goparquet dump [-c COLUMN] [-n N] FILE
+//	                                 the page headers, levels and values of the columns
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/record"
+	"github.com/zenixls2/goparquet/schema"
+	"io"
+	"os"
+)
+
+type command struct {
+	usage string
+	run   func(flags *flag.FlagSet, args []string, out io.Writer)
+}
+
+var commands = map[string]command{
+	"schema":   {"schema FILE", runSchema},
+	"meta":     {"meta FILE", runMeta},
+	"head":     {"head [-n N] FILE", runHead},
+	"cat":      {"cat FILE", runCat},
+	"rowcount": {"rowcount FILE", runRowCount},
+	"dump":     {"dump [-c COLUMN] [-n N] FILE", runDump},
+}
+
+var commandNames = []string{"schema", "meta", "head", "cat", "rowcount", "dump"}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: goparquet COMMAND [FLAGS] FILE")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range commandNames {
+		fmt.Fprintln(os.Stderr, "  goparquet", commands[name].usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: goparquet", cmd.usage)
+		flags.PrintDefaults()
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer func() {
+		out.Flush()
+		if failure := recover(); failure != nil {
+			fmt.Fprintln(os.Stderr, "goparquet:", failure)
+			os.Exit(1)
+		}
+	}()
+	cmd.run(flags, os.Args[2:], out)
+}
+
+// Parse the flags and open the file following them
+func openFile(flags *flag.FlagSet, args []string) *file.ParquetFileReader {
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	return file.OpenFile(flags.Arg(0))
+}
+
+func runSchema(flags *flag.FlagSet, args []string, out io.Writer) {
+	reader := openFile(flags, args)
+	defer reader.Close()
+	schema.PrintSchema(&reader.Schema().GroupNode().Node, out, 2)
+}
+
+func runRowCount(flags *flag.FlagSet, args []string, out io.Writer) {
+	reader := openFile(flags, args)
+	defer reader.Close()
+	fmt.Fprintln(out, reader.NumRows())
+}
+
+func runHead(flags *flag.FlagSet, args []string, out io.Writer) {
+	numRows := flags.Int64("n", 5, "number of rows")
+	reader := openFile(flags, args)
+	defer reader.Close()
+	if *numRows < 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	printRows(record.NewFileRowReader(reader), *numRows, out)
+}
+
+func runCat(flags *flag.FlagSet, args []string, out io.Writer) {
+	reader := openFile(flags, args)
+	defer reader.Close()
+	printRows(record.NewFileRowReader(reader), -1, out)
+}
+
+// Print up to numRows rows as JSON lines, all of them if -1
+func printRows(reader *record.RowReader, numRows int64, out io.Writer) {
+	rows := make([]record.Row, 1)
+	for printed := int64(0); numRows < 0 || printed < numRows; printed++ {
+		if reader.Read(rows) == 0 {
+			return
+		}
+		out.Write(record.RowToJSON(rows[0]))
+		fmt.Fprintln(out)
+	}
+}
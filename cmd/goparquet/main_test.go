@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/record"
+)
+
+type cliRow struct {
+	Id   int32    `parquet:"id"`
+	Name *string  `parquet:"name"`
+	Tags []string `parquet:"tags"`
+}
+
+func writeCliFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "rows.parquet")
+	sink, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	properties := column.NewWriterPropertiesBuilder().
+		SortingColumns(column.SortingColumn{ColumnIdx: 0}).
+		MaxRowGroupLength(2).
+		Build()
+	writer := record.NewWriter[cliRow](sink, properties)
+	name := "bob"
+	writer.Write(cliRow{Id: 1, Name: &name, Tags: []string{"a", "b"}})
+	writer.Write(cliRow{Id: 2})
+	writer.Write(cliRow{Id: 3, Tags: []string{"c"}})
+	writer.Close()
+	sink.Close()
+	return path
+}
+
+func runCommand(name string, args ...string) string {
+	var out bytes.Buffer
+	commands[name].run(flag.NewFlagSet(name, flag.PanicOnError), args, &out)
+	return out.String()
+}
+
+func TestCommands(t *testing.T) {
+	path := writeCliFile(t)
+	rows := `{"id":1,"name":"bob","tags":["a","b"]}
+{"id":2,"name":null,"tags":[]}
+{"id":3,"name":null,"tags":["c"]}
+`
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"schema", path}, `message cliRow {
+  required int32 id;
+  optional binary name (UTF8);
+  required group tags (LIST) {
+    repeated group list {
+      required binary element (UTF8);
+    }
+  }
+}
+`},
+		{[]string{"rowcount", path}, "3\n"},
+		{[]string{"head", "-n", "2", path}, rows[:strings.LastIndex(rows[:len(rows)-1], "\n")+1]},
+		{[]string{"head", path}, rows},
+		{[]string{"cat", path}, rows},
+	}
+	for _, test := range tests {
+		if output := runCommand(test.args[0], test.args[1:]...); output != test.expected {
+			t.Errorf("%v:\n%s\nwant:\n%s", test.args, output, test.expected)
+		}
+	}
+}
+
+func TestMetaCommand(t *testing.T) {
+	output := runCommand("meta", writeCliFile(t))
+	for _, line := range []string{
+		"rows: 3\n",
+		"row groups: 2\n",
+		"\nrow group 1: rows 1, ",
+		"  sorted by: id\n",
+		"  column tags.list.element: BYTE_ARRAY\n",
+		"    encodings RLE PLAIN_DICTIONARY, fully dictionary encoded\n",
+		"    statistics nulls 1 min \"a\" max \"b\"\n",
+		"    statistics nulls 0 min 3 max 3\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("no %q in:\n%s", line, output)
+		}
+	}
+}
+
+func TestDumpCommand(t *testing.T) {
+	path := writeCliFile(t)
+	output := runCommand("dump", "-c", "tags.list.element", "-n", "2", path)
+	expected := []string{
+		"row group 0, column tags.list.element: BYTE_ARRAY, max definition level 1, max repetition level 1",
+		"  page 0: DICTIONARY_PAGE, compressed size 10, uncompressed size 10, values 2, encoding PLAIN_DICTIONARY",
+		"    value 0: R:0 D:1 V:\"a\"",
+		"    value 1: R:1 D:1 V:\"b\"",
+		"row group 1, column tags.list.element: BYTE_ARRAY, max definition level 1, max repetition level 1",
+		"    value 0: R:0 D:1 V:\"c\"",
+	}
+	lines := strings.Split(output, "\n")
+	for _, line := range expected {
+		found := false
+		for _, actual := range lines {
+			found = found || actual == line
+		}
+		if !found {
+			t.Errorf("no %q in:\n%s", line, output)
+		}
+	}
+	if strings.Contains(output, "value 2:") || strings.Contains(output, "column id") {
+		t.Errorf("dumped more than 2 levels of tags:\n%s", output)
+	}
+
+	output = runCommand("dump", "-c", "name", path)
+	if !strings.Contains(output, "    value 1: R:0 D:0 V:null\n") {
+		t.Errorf("no null name in:\n%s", output)
+	}
+	message := ""
+	func() {
+		defer func() {
+			message = fmt.Sprint(recover())
+		}()
+		runCommand("dump", "-c", "missing", path)
+	}()
+	if message != "Column missing is not in the schema" {
+		t.Errorf("missing column: %q", message)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func runMeta(flags *flag.FlagSet, args []string, out io.Writer) {
+	reader := openFile(flags, args)
+	defer reader.Close()
+	metadata := reader.Metadata()
+	fmt.Fprintf(out, "created by: %s\n", metadata.CreatedBy())
+	fmt.Fprintf(out, "version: %d\n", metadata.Version())
+	fmt.Fprintf(out, "rows: %d\n", metadata.NumRows())
+	fmt.Fprintf(out, "row groups: %d\n", metadata.NumRowGroups())
+	fmt.Fprintf(out, "columns: %d\n", metadata.NumColumns())
+	keyValues := metadata.KeyValueMetadata()
+	for i := 0; i < keyValues.Size(); i++ {
+		fmt.Fprintf(out, "key value: %s = %s\n", keyValues.Key(i), keyValues.Value(i))
+	}
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rowGroup := reader.RowGroup(i)
+		rgMetadata := rowGroup.Metadata()
+		fmt.Fprintf(out, "\nrow group %d: rows %d, total byte size %d, compressed size %d\n",
+			i, rgMetadata.NumRows(), rgMetadata.TotalByteSize(),
+			rgMetadata.TotalCompressedSize())
+		if sorting := rowGroup.SortingColumns(); len(sorting) > 0 {
+			fmt.Fprintf(out, "  sorted by: %s\n", formatSortingColumns(reader.Schema(), sorting))
+		}
+		for j := 0; j < rgMetadata.NumColumns(); j++ {
+			printColumnChunk(rowGroup, j, out)
+		}
+	}
+}
+
+func printColumnChunk(rowGroup *file.RowGroupReader, i int, out io.Writer) {
+	chunk := rowGroup.Metadata().ColumnChunk(i)
+	descr := chunk.Descr()
+	fmt.Fprintf(out, "  column %s: %s\n", descr.Path().ToDotString(),
+		ptype.TypeToString(descr.PhysicalType()))
+	fmt.Fprintf(out, "    codec %s, values %d, compressed size %d, uncompressed size %d\n",
+		ptype.CompressionToString(chunk.Codec()), chunk.NumValues(),
+		chunk.TotalCompressedSize(), chunk.TotalUncompressedSize())
+	encodings := make([]string, len(chunk.Encodings()))
+	for j, encoding := range chunk.Encodings() {
+		encodings[j] = ptype.EncodingToString(encoding)
+	}
+	fmt.Fprintf(out, "    encodings %s", strings.Join(encodings, " "))
+	if chunk.IsFullyDictionaryEncoded() {
+		fmt.Fprint(out, ", fully dictionary encoded")
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "    data page offset %d", chunk.DataPageOffset())
+	if chunk.HasDictionaryPage() {
+		fmt.Fprintf(out, ", dictionary page offset %d", chunk.DictionaryPageOffset())
+	}
+	fmt.Fprintln(out)
+	if statistics := chunk.Statistics(); statistics != nil {
+		fmt.Fprintf(out, "    statistics%s\n", formatStatistics(statistics))
+	}
+	var indexes []string
+	if _, _, ok := chunk.ColumnIndexLocation(); ok {
+		indexes = append(indexes, "column index")
+	}
+	if _, _, ok := chunk.OffsetIndexLocation(); ok {
+		indexes = append(indexes, "offset index")
+	}
+	if chunk.HasBloomFilter() {
+		indexes = append(indexes, "bloom filter")
+	}
+	if len(indexes) > 0 {
+		fmt.Fprintf(out, "    %s\n", strings.Join(indexes, ", "))
+	}
+	keyValues := chunk.KeyValueMetadata()
+	for j := 0; j < keyValues.Size(); j++ {
+		fmt.Fprintf(out, "    key value: %s = %s\n", keyValues.Key(j), keyValues.Value(j))
+	}
+}
+
+func formatStatistics(statistics *column.Statistics) string {
+	var builder strings.Builder
+	if statistics.HasNullCount() {
+		fmt.Fprintf(&builder, " nulls %d", statistics.NullCount())
+	}
+	if statistics.HasDistinctCount() {
+		fmt.Fprintf(&builder, " distinct %d", statistics.DistinctCount())
+	}
+	if statistics.HasMinMax() {
+		fmt.Fprintf(&builder, " min %s max %s", formatValue(statistics.Descr(), statistics.Min()),
+			formatValue(statistics.Descr(), statistics.Max()))
+	}
+	return builder.String()
+}
+
+func formatSortingColumns(descr *schema.SchemaDescriptor, sorting []column.SortingColumn) string {
+	columns := make([]string, len(sorting))
+	for i, sortingColumn := range sorting {
+		columns[i] = descr.Column(sortingColumn.ColumnIdx).Path().ToDotString()
+		if sortingColumn.Descending {
+			columns[i] += " desc"
+		}
+		if sortingColumn.NullsFirst {
+			columns[i] += " nulls first"
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// A value of the column, quoted for strings
+func formatValue(descr *schema.ColumnDescriptor, value interface{}) string {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case ptype.ByteArray:
+		data = v
+	case ptype.FixedLenByteArray:
+		data = v
+	default:
+		return fmt.Sprint(value)
+	}
+	switch descr.LogicalType() {
+	case ptype.LogicalType_UTF8, ptype.LogicalType_ENUM, ptype.LogicalType_JSON:
+		return strconv.Quote(string(data))
+	}
+	return "0x" + hex.EncodeToString(data)
+}
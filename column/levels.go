@@ -0,0 +1,112 @@
+package column
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/ptype"
+)
+
+type LevelEncoder struct {
+	bitWidth int
+	rle      *encoding.RleEncoder
+}
+
+func NewLevelEncoder(maxLevel int16) *LevelEncoder {
+	bitWidth := encoding.BitWidth(uint64(maxLevel))
+	return &LevelEncoder{
+		bitWidth: bitWidth,
+		rle:      encoding.NewRleEncoder(bitWidth),
+	}
+}
+
+func (l *LevelEncoder) Encode(levels []int16) {
+	for _, level := range levels {
+		l.rle.Put(uint64(level))
+	}
+}
+
+// The RLE encoded levels prefixed by their 4 byte length, as stored in a
+// data page
+func (l *LevelEncoder) Flush() []byte {
+	encoded := l.rle.Flush()
+	result := make([]byte, 4+len(encoded))
+	binary.LittleEndian.PutUint32(result, uint32(len(encoded)))
+	copy(result[4:], encoded)
+	return result
+}
+
+type LevelDecoder struct {
+	bitWidth           int
+	numValuesRemaining int
+	encoding           ptype.Encoding
+	rle                *encoding.RleDecoder
+	data               []byte
+	bitOffset          int
+}
+
+func NewLevelDecoder() *LevelDecoder {
+	return &LevelDecoder{rle: &encoding.RleDecoder{}}
+}
+
+// Initialize the LevelDecoder state with new data
+// and return the number of bytes consumed
+func (l *LevelDecoder) SetData(enc ptype.Encoding, maxLevel int16,
+	numBufferedValues int, data []byte) int {
+	l.bitWidth = encoding.BitWidth(uint64(maxLevel))
+	l.numValuesRemaining = numBufferedValues
+	l.encoding = enc
+	switch enc {
+	case ptype.Encoding_RLE:
+		if len(data) < 4 {
+			panic(fmt.Errorf("Corrupt levels: missing RLE length"))
+		}
+		numBytes := int(binary.LittleEndian.Uint32(data))
+		if 4+numBytes > len(data) {
+			panic(fmt.Errorf("Corrupt levels: RLE length %d exceeds page", numBytes))
+		}
+		l.rle.Reset(data[4:4+numBytes], l.bitWidth)
+		return 4 + numBytes
+	case ptype.Encoding_BIT_PACKED:
+		numBytes := (numBufferedValues*l.bitWidth + 7) / 8
+		if numBytes > len(data) {
+			panic(fmt.Errorf("Corrupt levels: BIT_PACKED length %d exceeds page", numBytes))
+		}
+		l.data = data[:numBytes]
+		l.bitOffset = 0
+		return numBytes
+	default:
+		panic(fmt.Errorf("Unknown encoding type for levels: %s",
+			ptype.EncodingToString(enc)))
+	}
+}
+
+// Decodes a batch of levels into an array and returns the number of levels decoded
+func (l *LevelDecoder) Decode(levels []int16) int {
+	numDecoded := len(levels)
+	if numDecoded > l.numValuesRemaining {
+		numDecoded = l.numValuesRemaining
+	}
+	for i := 0; i < numDecoded; i++ {
+		if l.encoding == ptype.Encoding_RLE {
+			value, ok := l.rle.Next()
+			if !ok {
+				panic(fmt.Errorf("Corrupt levels: fewer levels than values"))
+			}
+			levels[i] = int16(value)
+		} else {
+			// BIT_PACKED levels are packed from the most significant bit
+			var value int16
+			for b := 0; b < l.bitWidth; b++ {
+				value <<= 1
+				if l.data[l.bitOffset/8]&(0x80>>uint(l.bitOffset%8)) != 0 {
+					value |= 1
+				}
+				l.bitOffset++
+			}
+			levels[i] = value
+		}
+	}
+	l.numValuesRemaining -= numDecoded
+	return numDecoded
+}
@@ -0,0 +1,156 @@
+package column
+
+import (
+	"bytes"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/thrift"
+)
+
+// Parquet column chunks are split into pages. A column chunk always starts
+// with an optional dictionary page followed by the data pages.
+
+type Page struct {
+	buffer *bytes.Buffer
+	_type  thrift.PageType
+}
+
+func (p *Page) Type() thrift.PageType {
+	return p._type
+}
+
+func (p *Page) Buffer() *bytes.Buffer {
+	return p.buffer
+}
+
+func (p *Page) Data() []byte {
+	return p.buffer.Bytes()
+}
+
+func (p *Page) Size() int32 {
+	return int32(p.buffer.Len())
+}
+
+type DataPage struct {
+	Page
+	numValues               int32
+	encoding                ptype.Encoding
+	definitionLevelEncoding ptype.Encoding
+	repetitionLevelEncoding ptype.Encoding
+	statistics              EncodedStatistics
+}
+
+func NewDataPage(buffer *bytes.Buffer, numValues int32, encoding ptype.Encoding,
+	definitionLevelEncoding ptype.Encoding, repetitionLevelEncoding ptype.Encoding,
+	statistics EncodedStatistics) *DataPage {
+	return &DataPage{
+		Page:                    Page{buffer: buffer, _type: thrift.PageType_DATA_PAGE},
+		numValues:               numValues,
+		encoding:                encoding,
+		definitionLevelEncoding: definitionLevelEncoding,
+		repetitionLevelEncoding: repetitionLevelEncoding,
+		statistics:              statistics,
+	}
+}
+
+func (d *DataPage) NumValues() int32 {
+	return d.numValues
+}
+
+func (d *DataPage) Encoding() ptype.Encoding {
+	return d.encoding
+}
+
+func (d *DataPage) DefinitionLevelEncoding() ptype.Encoding {
+	return d.definitionLevelEncoding
+}
+
+func (d *DataPage) RepetitionLevelEncoding() ptype.Encoding {
+	return d.repetitionLevelEncoding
+}
+
+func (d *DataPage) Statistics() *EncodedStatistics {
+	return &d.statistics
+}
+
+type CompressedDataPage struct {
+	DataPage
+	uncompressedSize int32
+	// Number of rows starting in the page
+	numRows int32
+}
+
+func NewCompressedDataPage(buffer *bytes.Buffer, numValues int32,
+	encoding ptype.Encoding, definitionLevelEncoding ptype.Encoding,
+	repetitionLevelEncoding ptype.Encoding, uncompressedSize int32,
+	statistics EncodedStatistics, numRows int32) *CompressedDataPage {
+	return &CompressedDataPage{
+		DataPage: *NewDataPage(buffer, numValues, encoding, definitionLevelEncoding,
+			repetitionLevelEncoding, statistics),
+		uncompressedSize: uncompressedSize,
+		numRows:          numRows,
+	}
+}
+
+func (c *CompressedDataPage) UncompressedSize() int32 {
+	return c.uncompressedSize
+}
+
+func (c *CompressedDataPage) NumRows() int32 {
+	return c.numRows
+}
+
+type DictionaryPage struct {
+	Page
+	numValues int32
+	encoding  ptype.Encoding
+	isSorted  bool
+}
+
+func NewDictionaryPage(buffer *bytes.Buffer, numValues int32,
+	encoding ptype.Encoding, isSorted bool) *DictionaryPage {
+	return &DictionaryPage{
+		Page:      Page{buffer: buffer, _type: thrift.PageType_DICTIONARY_PAGE},
+		numValues: numValues,
+		encoding:  encoding,
+		isSorted:  isSorted,
+	}
+}
+
+func (d *DictionaryPage) NumValues() int32 {
+	return d.numValues
+}
+
+func (d *DictionaryPage) Encoding() ptype.Encoding {
+	return d.encoding
+}
+
+func (d *DictionaryPage) IsSorted() bool {
+	return d.isSorted
+}
+
+// Sink of the pages of a single column chunk
+type PageWriter interface {
+	// Writes the page and returns the number of bytes written including
+	// the page header
+	WriteDataPage(page *CompressedDataPage) int64
+	WriteDictionaryPage(page *DictionaryPage) int64
+	Compress(buffer *bytes.Buffer) *bytes.Buffer
+	// Finish the column chunk, statistics and bloomFilter are nil when they
+	// are disabled
+	Close(hasDictionary bool, fallback bool, statistics *EncodedStatistics,
+		bloomFilter *bloom.BlockSplitBloomFilter)
+}
+
+// A page read back from a column chunk, either a *DataPage or a
+// *DictionaryPage
+type ColumnPage interface {
+	Type() thrift.PageType
+	Data() []byte
+}
+
+// Source of the pages of a single column chunk
+type PageReader interface {
+	// Returns nil when the column chunk is exhausted
+	NextPage() ColumnPage
+}
@@ -0,0 +1,521 @@
+package column
+
+import (
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+const (
+	DEFAULT_PAGE_SIZE                  = 1024 * 1024
+	DEFAULT_IS_DICTIONARY_ENABLED      = true
+	DEFAULT_ARE_STATISTICS_ENABLED     = true
+	DEFAULT_IS_DISTINCT_COUNT_ENABLED  = false
+	DEFAULT_STATISTICS_TRUNCATE_LENGTH = 0
+	DEFAULT_IS_PAGE_INDEX_ENABLED      = true
+	DEFAULT_IS_BLOOM_FILTER_ENABLED    = false
+	DEFAULT_BLOOM_FILTER_FPP           = 0.01
+	DEFAULT_BLOOM_FILTER_NDV           = 0
+	DEFAULT_DICTIONARY_PAGE_SIZE_LIMIT = DEFAULT_PAGE_SIZE
+	DEFAULT_WRITE_BATCH_SIZE           = 1024
+	DEFAULT_MAX_ROW_GROUP_LENGTH       = 64 * 1024 * 1024
+	DEFAULT_WRITE_CONCURRENCY          = 1
+	DEFAULT_IS_SORTING_VALIDATION      = false
+	DEFAULT_IS_SORT_ON_WRITE           = false
+	DEFAULT_ENCODING                   = ptype.Encoding_PLAIN
+	DEFAULT_COMPRESSION_TYPE           = ptype.Compression_UNCOMPRESSED
+	DEFAULT_CREATED_BY                 = "parquet-go version 1.0.0"
+)
+
+type ColumnProperties struct {
+	Encoding          ptype.Encoding
+	Codec             ptype.Compression
+	DictionaryEnabled bool
+	StatisticsEnabled bool
+	// Count the distinct values of each column chunk, this keeps all of
+	// them in memory while the chunk is written
+	DistinctCountEnabled bool
+	// Maximum length of the byte array min / max statistics, 0 keeps them
+	// whole
+	StatisticsTruncateLength int
+	// Write the ColumnIndex and OffsetIndex of the column chunks, the
+	// ColumnIndex also needs statistics
+	PageIndexEnabled bool
+	// Write a split block Bloom filter of each column chunk, sized for
+	// BloomFilterNDV distinct values (0 for the number of rows of the row
+	// group) with a false positive probability of BloomFilterFPP
+	BloomFilterEnabled bool
+	BloomFilterFPP     float64
+	BloomFilterNDV     int64
+}
+
+func DefaultColumnProperties() ColumnProperties {
+	return ColumnProperties{
+		Encoding:                 DEFAULT_ENCODING,
+		Codec:                    DEFAULT_COMPRESSION_TYPE,
+		DictionaryEnabled:        DEFAULT_IS_DICTIONARY_ENABLED,
+		StatisticsEnabled:        DEFAULT_ARE_STATISTICS_ENABLED,
+		DistinctCountEnabled:     DEFAULT_IS_DISTINCT_COUNT_ENABLED,
+		StatisticsTruncateLength: DEFAULT_STATISTICS_TRUNCATE_LENGTH,
+		PageIndexEnabled:         DEFAULT_IS_PAGE_INDEX_ENABLED,
+		BloomFilterEnabled:       DEFAULT_IS_BLOOM_FILTER_ENABLED,
+		BloomFilterFPP:           DEFAULT_BLOOM_FILTER_FPP,
+		BloomFilterNDV:           DEFAULT_BLOOM_FILTER_NDV,
+	}
+}
+
+type WriterProperties struct {
+	dictionaryPagesizeLimit int64
+	writeBatchSize          int64
+	maxRowGroupLength       int64
+	pagesize                int64
+	createdBy               string
+	writeConcurrency        int
+	sortingColumns          []SortingColumn
+	sortingValidation       bool
+	sortOnWrite             bool
+	defaultColumnProperties ColumnProperties
+	columnProperties        map[string]ColumnProperties
+}
+
+func DefaultWriterProperties() *WriterProperties {
+	return NewWriterPropertiesBuilder().Build()
+}
+
+func (w *WriterProperties) DictionaryPagesizeLimit() int64 {
+	return w.dictionaryPagesizeLimit
+}
+
+func (w *WriterProperties) WriteBatchSize() int64 {
+	return w.writeBatchSize
+}
+
+func (w *WriterProperties) MaxRowGroupLength() int64 {
+	return w.maxRowGroupLength
+}
+
+// Number of columns of a row group encoded at the same time by the record
+// writers, more than 1 buffers the column chunks in memory
+func (w *WriterProperties) WriteConcurrency() int {
+	return w.writeConcurrency
+}
+
+// The columns the rows of the row groups are declared sorted by
+func (w *WriterProperties) SortingColumns() []SortingColumn {
+	return w.sortingColumns
+}
+
+// Whether the writers check that the rows respect the sorting columns
+func (w *WriterProperties) SortingValidation() bool {
+	return w.sortingValidation
+}
+
+// Whether the record writers sort the rows of each row group by the
+// sorting columns before writing it
+func (w *WriterProperties) SortOnWrite() bool {
+	return w.sortOnWrite
+}
+
+func (w *WriterProperties) DataPagesize() int64 {
+	return w.pagesize
+}
+
+func (w *WriterProperties) CreatedBy() string {
+	return w.createdBy
+}
+
+func (w *WriterProperties) DictionaryIndexEncoding() ptype.Encoding {
+	return ptype.Encoding_PLAIN_DICTIONARY
+}
+
+func (w *WriterProperties) DictionaryPageEncoding() ptype.Encoding {
+	return ptype.Encoding_PLAIN_DICTIONARY
+}
+
+func (w *WriterProperties) ColumnProperties(path *schema.ColumnPath) ColumnProperties {
+	if properties, ok := w.columnProperties[path.ToDotString()]; ok {
+		return properties
+	}
+	return w.defaultColumnProperties
+}
+
+func (w *WriterProperties) Encoding(path *schema.ColumnPath) ptype.Encoding {
+	return w.ColumnProperties(path).Encoding
+}
+
+func (w *WriterProperties) Compression(path *schema.ColumnPath) ptype.Compression {
+	return w.ColumnProperties(path).Codec
+}
+
+func (w *WriterProperties) DictionaryEnabled(path *schema.ColumnPath) bool {
+	return w.ColumnProperties(path).DictionaryEnabled
+}
+
+func (w *WriterProperties) StatisticsEnabled(path *schema.ColumnPath) bool {
+	return w.ColumnProperties(path).StatisticsEnabled
+}
+
+func (w *WriterProperties) DistinctCountEnabled(path *schema.ColumnPath) bool {
+	return w.ColumnProperties(path).DistinctCountEnabled
+}
+
+func (w *WriterProperties) StatisticsTruncateLength(path *schema.ColumnPath) int {
+	return w.ColumnProperties(path).StatisticsTruncateLength
+}
+
+func (w *WriterProperties) PageIndexEnabled(path *schema.ColumnPath) bool {
+	return w.ColumnProperties(path).PageIndexEnabled
+}
+
+func (w *WriterProperties) BloomFilterEnabled(path *schema.ColumnPath) bool {
+	return w.ColumnProperties(path).BloomFilterEnabled
+}
+
+func (w *WriterProperties) BloomFilterFPP(path *schema.ColumnPath) float64 {
+	return w.ColumnProperties(path).BloomFilterFPP
+}
+
+func (w *WriterProperties) BloomFilterNDV(path *schema.ColumnPath) int64 {
+	return w.ColumnProperties(path).BloomFilterNDV
+}
+
+type WriterPropertiesBuilder struct {
+	dictionaryPagesizeLimit int64
+	writeBatchSize          int64
+	maxRowGroupLength       int64
+	pagesize                int64
+	createdBy               string
+	writeConcurrency        int
+	sortingColumns          []SortingColumn
+	sortingValidation       bool
+	sortOnWrite             bool
+	defaultColumnProperties ColumnProperties
+	encodings               map[string]ptype.Encoding
+	codecs                  map[string]ptype.Compression
+	dictionaryEnabled       map[string]bool
+	statisticsEnabled       map[string]bool
+	distinctCountEnabled    map[string]bool
+	truncateLengths         map[string]int
+	pageIndexEnabled        map[string]bool
+	bloomFilterEnabled      map[string]bool
+	bloomFilterFPPs         map[string]float64
+	bloomFilterNDVs         map[string]int64
+}
+
+func NewWriterPropertiesBuilder() *WriterPropertiesBuilder {
+	return &WriterPropertiesBuilder{
+		dictionaryPagesizeLimit: DEFAULT_DICTIONARY_PAGE_SIZE_LIMIT,
+		writeBatchSize:          DEFAULT_WRITE_BATCH_SIZE,
+		maxRowGroupLength:       DEFAULT_MAX_ROW_GROUP_LENGTH,
+		pagesize:                DEFAULT_PAGE_SIZE,
+		createdBy:               DEFAULT_CREATED_BY,
+		writeConcurrency:        DEFAULT_WRITE_CONCURRENCY,
+		sortingValidation:       DEFAULT_IS_SORTING_VALIDATION,
+		sortOnWrite:             DEFAULT_IS_SORT_ON_WRITE,
+		defaultColumnProperties: DefaultColumnProperties(),
+		encodings:               make(map[string]ptype.Encoding),
+		codecs:                  make(map[string]ptype.Compression),
+		dictionaryEnabled:       make(map[string]bool),
+		statisticsEnabled:       make(map[string]bool),
+		distinctCountEnabled:    make(map[string]bool),
+		truncateLengths:         make(map[string]int),
+		pageIndexEnabled:        make(map[string]bool),
+		bloomFilterEnabled:      make(map[string]bool),
+		bloomFilterFPPs:         make(map[string]float64),
+		bloomFilterNDVs:         make(map[string]int64),
+	}
+}
+
+func (b *WriterPropertiesBuilder) EnableDictionary() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.DictionaryEnabled = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableDictionary() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.DictionaryEnabled = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableDictionaryFor(path string) *WriterPropertiesBuilder {
+	b.dictionaryEnabled[path] = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableDictionaryFor(path string) *WriterPropertiesBuilder {
+	b.dictionaryEnabled[path] = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableStatistics() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.StatisticsEnabled = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableStatistics() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.StatisticsEnabled = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableStatisticsFor(path string) *WriterPropertiesBuilder {
+	b.statisticsEnabled[path] = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableStatisticsFor(path string) *WriterPropertiesBuilder {
+	b.statisticsEnabled[path] = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableDistinctCount() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.DistinctCountEnabled = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableDistinctCount() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.DistinctCountEnabled = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableDistinctCountFor(path string) *WriterPropertiesBuilder {
+	b.distinctCountEnabled[path] = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableDistinctCountFor(path string) *WriterPropertiesBuilder {
+	b.distinctCountEnabled[path] = false
+	return b
+}
+
+// Truncate the min / max statistics of BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY
+// columns to length bytes. The max is rounded up so both stay valid bounds,
+// and they are marked as not exact.
+func (b *WriterPropertiesBuilder) StatisticsTruncateLength(length int) *WriterPropertiesBuilder {
+	b.defaultColumnProperties.StatisticsTruncateLength = length
+	return b
+}
+
+func (b *WriterPropertiesBuilder) StatisticsTruncateLengthFor(path string, length int) *WriterPropertiesBuilder {
+	b.truncateLengths[path] = length
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnablePageIndex() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.PageIndexEnabled = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisablePageIndex() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.PageIndexEnabled = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnablePageIndexFor(path string) *WriterPropertiesBuilder {
+	b.pageIndexEnabled[path] = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisablePageIndexFor(path string) *WriterPropertiesBuilder {
+	b.pageIndexEnabled[path] = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableBloomFilter() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.BloomFilterEnabled = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableBloomFilter() *WriterPropertiesBuilder {
+	b.defaultColumnProperties.BloomFilterEnabled = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EnableBloomFilterFor(path string) *WriterPropertiesBuilder {
+	b.bloomFilterEnabled[path] = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableBloomFilterFor(path string) *WriterPropertiesBuilder {
+	b.bloomFilterEnabled[path] = false
+	return b
+}
+
+// False positive probability of the Bloom filters, in (0, 1)
+func (b *WriterPropertiesBuilder) BloomFilterFPP(fpp float64) *WriterPropertiesBuilder {
+	b.defaultColumnProperties.BloomFilterFPP = fpp
+	return b
+}
+
+func (b *WriterPropertiesBuilder) BloomFilterFPPFor(path string, fpp float64) *WriterPropertiesBuilder {
+	b.bloomFilterFPPs[path] = fpp
+	return b
+}
+
+// Expected number of distinct values of a column chunk
+func (b *WriterPropertiesBuilder) BloomFilterNDV(ndv int64) *WriterPropertiesBuilder {
+	b.defaultColumnProperties.BloomFilterNDV = ndv
+	return b
+}
+
+func (b *WriterPropertiesBuilder) BloomFilterNDVFor(path string, ndv int64) *WriterPropertiesBuilder {
+	b.bloomFilterNDVs[path] = ndv
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DictionaryPagesizeLimit(limit int64) *WriterPropertiesBuilder {
+	b.dictionaryPagesizeLimit = limit
+	return b
+}
+
+func (b *WriterPropertiesBuilder) WriteBatchSize(size int64) *WriterPropertiesBuilder {
+	b.writeBatchSize = size
+	return b
+}
+
+func (b *WriterPropertiesBuilder) MaxRowGroupLength(length int64) *WriterPropertiesBuilder {
+	b.maxRowGroupLength = length
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DataPagesize(size int64) *WriterPropertiesBuilder {
+	b.pagesize = size
+	return b
+}
+
+func (b *WriterPropertiesBuilder) WriteConcurrency(concurrency int) *WriterPropertiesBuilder {
+	b.writeConcurrency = concurrency
+	return b
+}
+
+// Declare the rows of each row group sorted by the columns, written to the
+// SortingColumns of the row groups. The rows are written as given, they
+// are only checked with EnableSortingValidation.
+func (b *WriterPropertiesBuilder) SortingColumns(columns ...SortingColumn) *WriterPropertiesBuilder {
+	b.sortingColumns = columns
+	return b
+}
+
+// Check that the rows written respect the sorting columns, a row group
+// that does not panics when closed. The sorting columns cannot be repeated.
+func (b *WriterPropertiesBuilder) EnableSortingValidation() *WriterPropertiesBuilder {
+	b.sortingValidation = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableSortingValidation() *WriterPropertiesBuilder {
+	b.sortingValidation = false
+	return b
+}
+
+// Have the record writers buffer the rows of each row group and sort them
+// by the sorting columns before writing it. Rows with equal keys keep the
+// order they were written in. The sorting columns cannot be repeated.
+func (b *WriterPropertiesBuilder) EnableSortOnWrite() *WriterPropertiesBuilder {
+	b.sortOnWrite = true
+	return b
+}
+
+func (b *WriterPropertiesBuilder) DisableSortOnWrite() *WriterPropertiesBuilder {
+	b.sortOnWrite = false
+	return b
+}
+
+func (b *WriterPropertiesBuilder) CreatedBy(createdBy string) *WriterPropertiesBuilder {
+	b.createdBy = createdBy
+	return b
+}
+
+// Define the encoding that is used when we don't utilise dictionary encoding.
+//
+// This either apply if dictionary encoding is disabled or if we fallback
+// as the dictionary grew too large.
+func (b *WriterPropertiesBuilder) Encoding(encoding ptype.Encoding) *WriterPropertiesBuilder {
+	b.defaultColumnProperties.Encoding = encoding
+	return b
+}
+
+func (b *WriterPropertiesBuilder) EncodingFor(path string, encoding ptype.Encoding) *WriterPropertiesBuilder {
+	b.encodings[path] = encoding
+	return b
+}
+
+func (b *WriterPropertiesBuilder) Compression(codec ptype.Compression) *WriterPropertiesBuilder {
+	b.defaultColumnProperties.Codec = codec
+	return b
+}
+
+func (b *WriterPropertiesBuilder) CompressionFor(path string, codec ptype.Compression) *WriterPropertiesBuilder {
+	b.codecs[path] = codec
+	return b
+}
+
+func (b *WriterPropertiesBuilder) Build() *WriterProperties {
+	columnProperties := make(map[string]ColumnProperties)
+	get := func(key string) ColumnProperties {
+		if properties, ok := columnProperties[key]; ok {
+			return properties
+		}
+		return b.defaultColumnProperties
+	}
+	for key, encoding := range b.encodings {
+		properties := get(key)
+		properties.Encoding = encoding
+		columnProperties[key] = properties
+	}
+	for key, codec := range b.codecs {
+		properties := get(key)
+		properties.Codec = codec
+		columnProperties[key] = properties
+	}
+	for key, enabled := range b.dictionaryEnabled {
+		properties := get(key)
+		properties.DictionaryEnabled = enabled
+		columnProperties[key] = properties
+	}
+	for key, enabled := range b.statisticsEnabled {
+		properties := get(key)
+		properties.StatisticsEnabled = enabled
+		columnProperties[key] = properties
+	}
+	for key, enabled := range b.distinctCountEnabled {
+		properties := get(key)
+		properties.DistinctCountEnabled = enabled
+		columnProperties[key] = properties
+	}
+	for key, length := range b.truncateLengths {
+		properties := get(key)
+		properties.StatisticsTruncateLength = length
+		columnProperties[key] = properties
+	}
+	for key, enabled := range b.pageIndexEnabled {
+		properties := get(key)
+		properties.PageIndexEnabled = enabled
+		columnProperties[key] = properties
+	}
+	for key, enabled := range b.bloomFilterEnabled {
+		properties := get(key)
+		properties.BloomFilterEnabled = enabled
+		columnProperties[key] = properties
+	}
+	for key, fpp := range b.bloomFilterFPPs {
+		properties := get(key)
+		properties.BloomFilterFPP = fpp
+		columnProperties[key] = properties
+	}
+	for key, ndv := range b.bloomFilterNDVs {
+		properties := get(key)
+		properties.BloomFilterNDV = ndv
+		columnProperties[key] = properties
+	}
+	return &WriterProperties{
+		dictionaryPagesizeLimit: b.dictionaryPagesizeLimit,
+		writeBatchSize:          b.writeBatchSize,
+		maxRowGroupLength:       b.maxRowGroupLength,
+		pagesize:                b.pagesize,
+		createdBy:               b.createdBy,
+		writeConcurrency:        b.writeConcurrency,
+		sortingColumns:          b.sortingColumns,
+		sortingValidation:       b.sortingValidation,
+		sortOnWrite:             b.sortOnWrite,
+		defaultColumnProperties: b.defaultColumnProperties,
+		columnProperties:        columnProperties,
+	}
+}
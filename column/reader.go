@@ -0,0 +1,251 @@
+package column
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+// ColumnReader reads the levels and values of one column chunk. Values are
+// returned in the typed slice matching the column's physical type, see the
+// encoding package for the mapping.
+type ColumnReader struct {
+	descr *schema.ColumnDescriptor
+	pager PageReader
+
+	currentPage ColumnPage
+
+	// Not set if full schema for this field has no optional or repeated elements
+	definitionLevelDecoder *LevelDecoder
+
+	// Not set for flat schemas.
+	repetitionLevelDecoder *LevelDecoder
+
+	// The total number of values stored in the data page. This is the maximum
+	// of the number of encoded definition levels or encoded values. For
+	// non-repeated, required columns, this is equal to the number of encoded
+	// values. For repeated or optional values, there may be fewer data values
+	// than levels, and this tells you how many encoded levels there are in that
+	// case.
+	numBufferedValues int64
+
+	// The number of values from the current data page that have been decoded
+	// into memory
+	numDecodedValues int64
+
+	currentDecoder encoding.Decoder
+	plainDecoder   *encoding.PlainDecoder
+	dictDecoder    *encoding.DictDecoder
+}
+
+func NewColumnReader(descr *schema.ColumnDescriptor, pager PageReader) *ColumnReader {
+	return &ColumnReader{
+		descr:                  descr,
+		pager:                  pager,
+		definitionLevelDecoder: NewLevelDecoder(),
+		repetitionLevelDecoder: NewLevelDecoder(),
+	}
+}
+
+func (r *ColumnReader) Descr() *schema.ColumnDescriptor {
+	return r.descr
+}
+
+func (r *ColumnReader) Type() ptype.Type {
+	return r.descr.PhysicalType()
+}
+
+// Returns true if there are still values in this column.
+func (r *ColumnReader) HasNext() bool {
+	// Either there is no data page available yet, or the data page has been
+	// exhausted
+	if r.numBufferedValues == 0 || r.numDecodedValues == r.numBufferedValues {
+		if !r.ReadNewPage() || r.numBufferedValues == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Advance to the next data page, configuring the dictionary on the way
+func (r *ColumnReader) ReadNewPage() bool {
+	for {
+		r.currentPage = r.pager.NextPage()
+		if r.currentPage == nil {
+			// EOS
+			return false
+		}
+
+		switch page := r.currentPage.(type) {
+		case *DictionaryPage:
+			r.ConfigureDictionary(page)
+			continue
+		case *DataPage:
+			// Read a data page.
+			r.numBufferedValues = int64(page.NumValues())
+
+			// Have not decoded any values from the data page yet
+			r.numDecodedValues = 0
+
+			buffer := page.Data()
+
+			// If the data page includes repetition and definition levels, we
+			// initialize the level decoder and subtract the encoded level bytes
+			// from the page size to determine the number of bytes in the encoded
+			// data.
+			if r.descr.MaxRepetitionLevel() > 0 {
+				levelsBytes := r.repetitionLevelDecoder.SetData(
+					page.RepetitionLevelEncoding(), r.descr.MaxRepetitionLevel(),
+					int(r.numBufferedValues), buffer)
+				buffer = buffer[levelsBytes:]
+			}
+			if r.descr.MaxDefinitionLevel() > 0 {
+				levelsBytes := r.definitionLevelDecoder.SetData(
+					page.DefinitionLevelEncoding(), r.descr.MaxDefinitionLevel(),
+					int(r.numBufferedValues), buffer)
+				buffer = buffer[levelsBytes:]
+			}
+
+			// Get a decoder object for this page or create a new decoder if this
+			// is the first page with this encoding.
+			switch page.Encoding() {
+			case ptype.Encoding_PLAIN_DICTIONARY, ptype.Encoding_RLE_DICTIONARY:
+				if r.dictDecoder == nil {
+					panic(fmt.Errorf("Column %s: dictionary page must be before any data page",
+						r.descr.Path().ToDotString()))
+				}
+				r.currentDecoder = r.dictDecoder
+			case ptype.Encoding_PLAIN:
+				if r.plainDecoder == nil {
+					r.plainDecoder = encoding.NewPlainDecoder(r.descr.PhysicalType(),
+						int(r.descr.TypeLength()))
+				}
+				r.currentDecoder = r.plainDecoder
+			default:
+				panic(fmt.Errorf("Unknown encoding type: %s",
+					ptype.EncodingToString(page.Encoding())))
+			}
+			r.currentDecoder.SetData(int(r.numBufferedValues), buffer)
+			return true
+		default:
+			// We don't know what this page type is. We're allowed to skip
+			// non-data pages.
+			continue
+		}
+	}
+}
+
+func (r *ColumnReader) ConfigureDictionary(page *DictionaryPage) {
+	if r.dictDecoder != nil {
+		panic(fmt.Errorf("Column cannot have more than one dictionary."))
+	}
+	r.dictDecoder = newDictDecoder(r.descr, page)
+}
+
+func newDictDecoder(descr *schema.ColumnDescriptor, page *DictionaryPage) *encoding.DictDecoder {
+	switch page.Encoding() {
+	case ptype.Encoding_PLAIN, ptype.Encoding_PLAIN_DICTIONARY:
+		dictionary := encoding.NewPlainDecoder(descr.PhysicalType(),
+			int(descr.TypeLength()))
+		dictionary.SetData(int(page.NumValues()), page.Data())
+		decoder := encoding.NewDictDecoder()
+		decoder.SetDict(descr.PhysicalType(), int(page.NumValues()), dictionary)
+		return decoder
+	}
+	panic(fmt.Errorf("Unsupported dictionary encoding: %s",
+		ptype.EncodingToString(page.Encoding())))
+}
+
+// The values of a dictionary page, in a slice of the column's type
+func DecodeDictionaryPage(descr *schema.ColumnDescriptor, page *DictionaryPage) interface{} {
+	return newDictDecoder(descr, page).Dictionary()
+}
+
+// Read multiple definition levels into preallocated memory
+//
+// Returns the number of decoded definition levels
+func (r *ColumnReader) ReadDefinitionLevels(batchSize int64, levels []int16) int64 {
+	if r.descr.MaxDefinitionLevel() == 0 {
+		return 0
+	}
+	return int64(r.definitionLevelDecoder.Decode(levels[:batchSize]))
+}
+
+// Read multiple repetition levels into preallocated memory
+//
+// Returns the number of decoded repetition levels
+func (r *ColumnReader) ReadRepetitionLevels(batchSize int64, levels []int16) int64 {
+	if r.descr.MaxRepetitionLevel() == 0 {
+		return 0
+	}
+	return int64(r.repetitionLevelDecoder.Decode(levels[:batchSize]))
+}
+
+// Read up to batchSize values from the current data page into the
+// pre-allocated memory values
+//
+// Returns the number of values read into the values slice
+func (r *ColumnReader) ReadValues(batchSize int64, values interface{}) int64 {
+	return int64(r.currentDecoder.Decode(encoding.SliceValues(values, 0, int(batchSize))))
+}
+
+// Read a batch of repetition levels, definition levels, and values from the
+// column.
+//
+// Since null values are not stored in the values, the number of values read
+// may be less than the number of repetition and definition levels. With
+// nested data this is almost certainly true.
+//
+// To fully exhaust a row group, you must read batches until the number of
+// values read reaches the number of stored values according to the metadata.
+//
+// Returns the number of levels read and the number of values read. Only
+// levels of the current data page are returned, a batch never spans pages.
+func (r *ColumnReader) ReadBatch(batchSize int64, defLevels []int16, repLevels []int16,
+	values interface{}) (int64, int64) {
+	// HasNext invokes ReadNewPage
+	if !r.HasNext() {
+		return 0, 0
+	}
+
+	// TODO(wesm): keep reading data pages until batch_size is reached, or the
+	// row group is finished
+	if remaining := r.numBufferedValues - r.numDecodedValues; batchSize > remaining {
+		batchSize = remaining
+	}
+
+	var numDefLevels, numRepLevels int64
+	valuesToRead := int64(0)
+
+	// If the field is required and non-repeated, there are no definition levels
+	if r.descr.MaxDefinitionLevel() > 0 && defLevels != nil {
+		numDefLevels = r.ReadDefinitionLevels(batchSize, defLevels)
+		// TODO(wesm): this tallying of values-to-decode can be performed with
+		// better cache-efficiency if fused with the level decoding.
+		for i := int64(0); i < numDefLevels; i++ {
+			if defLevels[i] == r.descr.MaxDefinitionLevel() {
+				valuesToRead++
+			}
+		}
+	} else {
+		// Required field, read all values
+		valuesToRead = batchSize
+	}
+
+	// Not present for non-repeated fields
+	if r.descr.MaxRepetitionLevel() > 0 && repLevels != nil {
+		numRepLevels = r.ReadRepetitionLevels(batchSize, repLevels)
+		if defLevels != nil && numDefLevels != numRepLevels {
+			panic(fmt.Errorf("Number of decoded rep / def levels did not match"))
+		}
+	}
+
+	valuesRead := r.ReadValues(valuesToRead, values)
+	totalValues := numDefLevels
+	if valuesRead > totalValues {
+		totalValues = valuesRead
+	}
+	r.numDecodedValues += totalValues
+	return totalValues, valuesRead
+}
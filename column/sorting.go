@@ -0,0 +1,140 @@
+package column
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"reflect"
+)
+
+// A column the rows of the row groups are sorted by, the first sorting
+// column of a row group being the most significant
+type SortingColumn struct {
+	// Index of the leaf column in the schema
+	ColumnIdx  int
+	Descending bool
+	NullsFirst bool
+}
+
+// Compares the values of a non repeated column, nil for nulls, in the
+// order of a sorting column
+type SortComparator struct {
+	sorting SortingColumn
+	order   ptype.SortOrder
+}
+
+// Only non repeated columns with a sort order can be compared
+func NewSortComparator(descr *schema.ColumnDescriptor, sorting SortingColumn) *SortComparator {
+	order := ptype.GetSortOrder(descr.LogicalType(), descr.PhysicalType())
+	if descr.MaxRepetitionLevel() > 0 || order == ptype.SortOrder_UNKNOWN {
+		panic(fmt.Errorf("Column %s cannot be sorted", descr.Path().ToDotString()))
+	}
+	return &SortComparator{sorting: sorting, order: order}
+}
+
+// Negative if a sorts before b, 0 if they are equal and positive if a sorts
+// after b
+func (s *SortComparator) Compare(a interface{}, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		if s.sorting.NullsFirst {
+			return -1
+		}
+		return 1
+	case b == nil:
+		if s.sorting.NullsFirst {
+			return 1
+		}
+		return -1
+	}
+	c := compareValues(s.order, a, b)
+	if s.sorting.Descending {
+		return -c
+	}
+	return c
+}
+
+// Compares each row of a non repeated column to the previous one in the
+// order of a sorting column
+type rowOrderTracker struct {
+	comparator *SortComparator
+	maxLevel   int16
+	started    bool
+	// nil for null
+	previous interface{}
+	// For each row after the first: -1 if it sorts after the previous row,
+	// 0 if they are equal and 1 if it sorts before
+	comparisons []int8
+}
+
+func (t *rowOrderTracker) update(numLevels int64, defLevels []int16, values interface{}) {
+	batch := reflect.ValueOf(values)
+	j := 0
+	for k := int64(0); k < numLevels; k++ {
+		var value interface{}
+		if t.maxLevel == 0 || defLevels[k] == t.maxLevel {
+			value = batch.Index(j).Interface()
+			j++
+		}
+		if t.started {
+			c := int8(0)
+			switch r := t.comparator.Compare(t.previous, value); {
+			case r < 0:
+				c = -1
+			case r > 0:
+				c = 1
+			}
+			t.comparisons = append(t.comparisons, c)
+		}
+		t.started = true
+		// The byte arrays of the batch may be reused by the caller
+		switch v := value.(type) {
+		case ptype.ByteArray:
+			value = append(ptype.ByteArray{}, v...)
+		case ptype.FixedLenByteArray:
+			value = append(ptype.FixedLenByteArray{}, v...)
+		}
+		t.previous = value
+	}
+}
+
+// Compare the rows written to those before them in the order of sorting,
+// see SortComparisons. Only non repeated columns with a sort order are
+// compared.
+func (w *ColumnWriter) TrackSortOrder(sorting SortingColumn) {
+	w.rowOrder = &rowOrderTracker{
+		comparator: NewSortComparator(w.descr, sorting),
+		maxLevel:   w.descr.MaxDefinitionLevel(),
+	}
+}
+
+// For each row after the first: -1 if it sorts after the previous row, 0 if
+// they are equal and 1 if it sorts before. nil unless TrackSortOrder was
+// called.
+func (w *ColumnWriter) SortComparisons() []int8 {
+	if w.rowOrder == nil {
+		return nil
+	}
+	return w.rowOrder.comparisons
+}
+
+// The first row out of the order of the sorting columns given the
+// SortComparisons of each of them, -1 if the rows are sorted
+func FindUnsortedRow(comparisons [][]int8) int64 {
+	if len(comparisons) == 0 {
+		return -1
+	}
+	for row := range comparisons[0] {
+		for _, column := range comparisons {
+			if column[row] < 0 {
+				break
+			}
+			if column[row] > 0 {
+				return int64(row) + 1
+			}
+		}
+	}
+	return -1
+}
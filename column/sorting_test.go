@@ -0,0 +1,102 @@
+package column
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+func newRowOrderTracker(sorting SortingColumn, order ptype.SortOrder) *rowOrderTracker {
+	return &rowOrderTracker{comparator: &SortComparator{sorting: sorting, order: order}}
+}
+
+func TestRowOrderTracker(t *testing.T) {
+	tests := []struct {
+		sorting   SortingColumn
+		order     ptype.SortOrder
+		numLevels int64
+		defLevels []int16
+		values    interface{}
+		expected  string
+	}{
+		{SortingColumn{}, ptype.SortOrder_SIGNED, 4, nil, []int32{-1, 2, 2, 1}, "[-1 0 1]"},
+		{SortingColumn{Descending: true}, ptype.SortOrder_SIGNED, 4, nil, []int32{-1, 2, 2, 1}, "[1 0 -1]"},
+		// -1 is the largest unsigned value
+		{SortingColumn{}, ptype.SortOrder_UNSIGNED, 2, nil, []int32{1, -1}, "[-1]"},
+		{SortingColumn{}, ptype.SortOrder_SIGNED, 4, []int16{1, 0, 0, 1}, []int64{3, 4}, "[-1 0 1]"},
+		{SortingColumn{NullsFirst: true}, ptype.SortOrder_SIGNED, 4, []int16{1, 0, 0, 1}, []int64{3, 4}, "[1 0 -1]"},
+		{SortingColumn{}, ptype.SortOrder_UNSIGNED, 3, nil,
+			[]ptype.ByteArray{ptype.ByteArray("a"), ptype.ByteArray("ab"), ptype.ByteArray("b")}, "[-1 -1]"},
+	}
+	for _, test := range tests {
+		tracker := newRowOrderTracker(test.sorting, test.order)
+		if test.defLevels != nil {
+			tracker.maxLevel = 1
+		}
+		tracker.update(test.numLevels, test.defLevels, test.values)
+		if comparisons := fmt.Sprint(tracker.comparisons); comparisons != test.expected {
+			t.Errorf("%v %v: %s, want %s", test.sorting, test.values, comparisons, test.expected)
+		}
+	}
+}
+
+func TestRowOrderAcrossBatches(t *testing.T) {
+	tracker := newRowOrderTracker(SortingColumn{}, ptype.SortOrder_UNSIGNED)
+	value := ptype.ByteArray("b")
+	tracker.update(1, nil, []ptype.ByteArray{value})
+	// The caller reuses its buffer
+	value[0] = 'z'
+	tracker.update(2, nil, []ptype.ByteArray{ptype.ByteArray("c"), ptype.ByteArray("a")})
+	if comparisons := fmt.Sprint(tracker.comparisons); comparisons != "[-1 1]" {
+		t.Errorf("comparisons %s", comparisons)
+	}
+}
+
+func TestFindUnsortedRow(t *testing.T) {
+	tests := []struct {
+		comparisons [][]int8
+		row         int64
+	}{
+		{nil, -1},
+		{[][]int8{{-1, 0, -1}}, -1},
+		{[][]int8{{-1, 1, -1}}, 2},
+		// The second column only orders the rows equal on the first
+		{[][]int8{{-1, 0, -1}, {1, -1, 1}}, -1},
+		{[][]int8{{-1, 0, 0}, {1, -1, 1}}, 3},
+		{[][]int8{{0, 0}, {0, 0}}, -1},
+	}
+	for _, test := range tests {
+		if row := FindUnsortedRow(test.comparisons); row != test.row {
+			t.Errorf("%v: row %d, want %d", test.comparisons, row, test.row)
+		}
+	}
+}
+
+func TestSortComparator(t *testing.T) {
+	descr := schema.NewSchemaDescriptor(&schema.MustParse(`message sorting {
+  optional binary name (UTF8);
+  repeated int32 tags;
+  optional int96 time;
+}
+`).Node)
+	comparator := NewSortComparator(descr.Column(0), SortingColumn{Descending: true, NullsFirst: true})
+	a, b := ptype.ByteArray("a"), ptype.ByteArray("b")
+	if comparator.Compare(a, b) <= 0 || comparator.Compare(b, a) >= 0 || comparator.Compare(a, a) != 0 ||
+		comparator.Compare(nil, a) >= 0 || comparator.Compare(a, nil) <= 0 || comparator.Compare(nil, nil) != 0 {
+		t.Errorf("descending comparisons with the nulls first")
+	}
+	for _, i := range []int{1, 2} {
+		message := ""
+		func() {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+			NewSortComparator(descr.Column(i), SortingColumn{ColumnIdx: i})
+		}()
+		if expected := fmt.Sprintf("Column %s cannot be sorted", descr.Column(i).Path().ToDotString()); message != expected {
+			t.Errorf("%q, want %q", message, expected)
+		}
+	}
+}
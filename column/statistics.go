@@ -0,0 +1,737 @@
+package column
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"math"
+	"unicode/utf8"
+)
+
+// Statistics in their PLAIN encoded form, as stored in page headers and
+// column chunk metadata
+type EncodedStatistics struct {
+	max              []byte
+	min              []byte
+	NullCount        int64
+	DistinctCount    int64
+	HasMin           bool
+	HasMax           bool
+	HasNullCount     bool
+	HasDistinctCount bool
+	// Whether min / max are the actual values rather than bounds, only
+	// meaningful when HasExact
+	IsMinExact bool
+	IsMaxExact bool
+	HasExact   bool
+	// Whether min / max are compared as signed values, the only order the
+	// deprecated min / max fields may hold
+	SignedOrder bool
+	// Set when min / max were read from the deprecated fields, which older
+	// writers filled in signed order whatever the column type
+	Deprecated bool
+}
+
+func (e *EncodedStatistics) Max() []byte {
+	return e.max
+}
+
+func (e *EncodedStatistics) Min() []byte {
+	return e.min
+}
+
+func (e *EncodedStatistics) IsSet() bool {
+	return e.HasMin || e.HasMax || e.HasNullCount || e.HasDistinctCount
+}
+
+func (e *EncodedStatistics) SetMax(value []byte) *EncodedStatistics {
+	e.max = value
+	e.HasMax = true
+	return e
+}
+
+func (e *EncodedStatistics) SetMin(value []byte) *EncodedStatistics {
+	e.min = value
+	e.HasMin = true
+	return e
+}
+
+func (e *EncodedStatistics) SetNullCount(value int64) *EncodedStatistics {
+	e.NullCount = value
+	e.HasNullCount = true
+	return e
+}
+
+func (e *EncodedStatistics) SetDistinctCount(value int64) *EncodedStatistics {
+	e.DistinctCount = value
+	e.HasDistinctCount = true
+	return e
+}
+
+func (e *EncodedStatistics) SetExact(isMinExact bool, isMaxExact bool) *EncodedStatistics {
+	e.IsMinExact = isMinExact
+	e.IsMaxExact = isMaxExact
+	e.HasExact = true
+	return e
+}
+
+func (e *EncodedStatistics) ToThrift() *thrift.Statistics {
+	statistics := thrift.NewStatistics()
+	if e.HasMin {
+		statistics.MinValue = e.Min()
+		if e.SignedOrder {
+			statistics.Min = e.Min()
+		}
+	}
+	if e.HasMax {
+		statistics.MaxValue = e.Max()
+		if e.SignedOrder {
+			statistics.Max = e.Max()
+		}
+	}
+	if e.HasNullCount {
+		nullCount := e.NullCount
+		statistics.NullCount = &nullCount
+	}
+	if e.HasDistinctCount {
+		distinctCount := e.DistinctCount
+		statistics.DistinctCount = &distinctCount
+	}
+	if e.HasExact {
+		isMinExact, isMaxExact := e.IsMinExact, e.IsMaxExact
+		statistics.IsMinValueExact = &isMinExact
+		statistics.IsMaxValueExact = &isMaxExact
+	}
+	return statistics
+}
+
+func EncodedStatisticsFromThrift(statistics *thrift.Statistics) *EncodedStatistics {
+	result := &EncodedStatistics{}
+	if statistics == nil {
+		return result
+	}
+	if statistics.IsSetMinValue() || statistics.IsSetMaxValue() {
+		if statistics.IsSetMinValue() {
+			result.SetMin(statistics.GetMinValue())
+		}
+		if statistics.IsSetMaxValue() {
+			result.SetMax(statistics.GetMaxValue())
+		}
+	} else if statistics.IsSetMin() || statistics.IsSetMax() {
+		if statistics.IsSetMin() {
+			result.SetMin(statistics.GetMin())
+		}
+		if statistics.IsSetMax() {
+			result.SetMax(statistics.GetMax())
+		}
+		result.Deprecated = true
+	}
+	if statistics.IsSetNullCount() {
+		result.SetNullCount(statistics.GetNullCount())
+	}
+	if statistics.IsSetDistinctCount() {
+		result.SetDistinctCount(statistics.GetDistinctCount())
+	}
+	if statistics.IsSetIsMinValueExact() || statistics.IsSetIsMaxValueExact() {
+		result.SetExact(statistics.GetIsMinValueExact(), statistics.GetIsMaxValueExact())
+	}
+	return result
+}
+
+// Statistics accumulates the typed min / max, null count and, optionally,
+// the distinct count of the values of a page or column chunk. Min and max
+// are kept as a value of the column's physical type (bool, int32, int64,
+// ptype.Int96, float32, float64, ptype.ByteArray or
+// ptype.FixedLenByteArray) and compared in the column's sort order. They are
+// not tracked for columns without a defined order. NaN is ignored, in the
+// distinct count too, and zero bounds are written as -0.0 / +0.0.
+type Statistics struct {
+	descr     *schema.ColumnDescriptor
+	order     ptype.SortOrder
+	hasMinMax bool
+	min       interface{}
+	max       interface{}
+	numValues int64
+	nullCount int64
+	// Files may not store the null count
+	hasNullCount bool
+
+	hasDistinctCount bool
+	distinctCount    int64
+	// The distinct values seen while writing, nil when not tracked
+	distinct map[interface{}]struct{}
+
+	// Set when min / max read from a file are truncated bounds
+	minInexact bool
+	maxInexact bool
+	// Length byte array min / max are truncated to when encoded, 0 for none
+	truncateLength int
+}
+
+func NewStatistics(descr *schema.ColumnDescriptor, trackDistinct bool) *Statistics {
+	s := &Statistics{
+		descr:        descr,
+		order:        ptype.GetSortOrder(descr.LogicalType(), descr.PhysicalType()),
+		hasNullCount: true,
+	}
+	if trackDistinct {
+		s.distinct = make(map[interface{}]struct{})
+		s.hasDistinctCount = true
+	}
+	return s
+}
+
+// Decode the statistics of a page or column chunk holding numValues values
+// (nulls included). Min and max that cannot be decoded or are NaN are
+// dropped, as are deprecated ones of columns not in signed order.
+func NewStatisticsFromEncoded(descr *schema.ColumnDescriptor, encoded *EncodedStatistics,
+	numValues int64) *Statistics {
+	s := NewStatistics(descr, false)
+	s.hasNullCount = encoded.HasNullCount
+	if encoded.HasNullCount {
+		s.nullCount = encoded.NullCount
+	}
+	s.numValues = numValues - s.nullCount
+	if encoded.HasDistinctCount {
+		s.hasDistinctCount = true
+		s.distinctCount = encoded.DistinctCount
+	}
+	if encoded.HasMin && encoded.HasMax && s.order != ptype.SortOrder_UNKNOWN &&
+		(!encoded.Deprecated || s.order == ptype.SortOrder_SIGNED) {
+		s.minInexact = encoded.HasExact && !encoded.IsMinExact
+		s.maxInexact = encoded.HasExact && !encoded.IsMaxExact
+		min, minOk := decodeStatisticsValue(descr, encoded.Min(), !s.minInexact)
+		max, maxOk := decodeStatisticsValue(descr, encoded.Max(), !s.maxInexact)
+		if minOk && maxOk && !isNaN(min) && !isNaN(max) {
+			s.min, s.max = normalizeZeros(min, max)
+			s.hasMinMax = true
+		}
+	}
+	return s
+}
+
+// Truncate the byte array min / max to length bytes when encoding them, 0
+// keeps them whole. Only applies to columns in unsigned order, where a
+// prefix still is a bound.
+func (s *Statistics) SetTruncateLength(length int) {
+	s.truncateLength = length
+}
+
+func (s *Statistics) Descr() *schema.ColumnDescriptor {
+	return s.descr
+}
+
+func (s *Statistics) SortOrder() ptype.SortOrder {
+	return s.order
+}
+
+func (s *Statistics) HasMinMax() bool {
+	return s.hasMinMax
+}
+
+func (s *Statistics) Min() interface{} {
+	return s.min
+}
+
+func (s *Statistics) Max() interface{} {
+	return s.max
+}
+
+// Whether Min is the actual minimum rather than a lower bound
+func (s *Statistics) IsMinExact() bool {
+	return !s.minInexact
+}
+
+// Whether Max is the actual maximum rather than an upper bound
+func (s *Statistics) IsMaxExact() bool {
+	return !s.maxInexact
+}
+
+// Number of non null values
+func (s *Statistics) NumValues() int64 {
+	return s.numValues
+}
+
+func (s *Statistics) NullCount() int64 {
+	return s.nullCount
+}
+
+func (s *Statistics) HasNullCount() bool {
+	return s.hasNullCount
+}
+
+func (s *Statistics) HasDistinctCount() bool {
+	return s.hasDistinctCount
+}
+
+func (s *Statistics) DistinctCount() int64 {
+	if s.distinct != nil {
+		return int64(len(s.distinct))
+	}
+	return s.distinctCount
+}
+
+func (s *Statistics) Reset() {
+	s.hasMinMax = false
+	s.min = nil
+	s.max = nil
+	s.numValues = 0
+	s.nullCount = 0
+	s.hasNullCount = true
+	s.distinctCount = 0
+	s.minInexact = false
+	s.maxInexact = false
+	if s.distinct != nil {
+		s.distinct = make(map[interface{}]struct{})
+	}
+}
+
+// Compare two values of the column in its sort order
+func (s *Statistics) Compare(a interface{}, b interface{}) int {
+	return compareValues(s.order, a, b)
+}
+
+func compareValues(order ptype.SortOrder, a interface{}, b interface{}) int {
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case int32:
+		y := b.(int32)
+		if order == ptype.SortOrder_UNSIGNED {
+			return compareOrdered(uint32(x), uint32(y))
+		}
+		return compareOrdered(x, y)
+	case int64:
+		y := b.(int64)
+		if order == ptype.SortOrder_UNSIGNED {
+			return compareOrdered(uint64(x), uint64(y))
+		}
+		return compareOrdered(x, y)
+	case float32:
+		return compareOrdered(x, b.(float32))
+	case float64:
+		return compareOrdered(x, b.(float64))
+	case ptype.ByteArray:
+		return compareBytes(order, x, b.(ptype.ByteArray))
+	case ptype.FixedLenByteArray:
+		return compareBytes(order, x, b.(ptype.FixedLenByteArray))
+	}
+	panic(fmt.Errorf("Values of type %T have no sort order", a))
+}
+
+func compareOrdered[T int32 | int64 | uint32 | uint64 | float32 | float64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Byte arrays compare as unsigned bytes, or as big endian two's complement
+// integers for a signed order (DECIMAL)
+func compareBytes(order ptype.SortOrder, a []byte, b []byte) int {
+	if order != ptype.SortOrder_SIGNED {
+		return bytes.Compare(a, b)
+	}
+	negativeA := len(a) > 0 && a[0]&0x80 != 0
+	negativeB := len(b) > 0 && b[0]&0x80 != 0
+	if negativeA != negativeB {
+		if negativeA {
+			return -1
+		}
+		return 1
+	}
+	// Sign extend the shorter value
+	var extension byte
+	if negativeA {
+		extension = 0xff
+	}
+	length := len(a)
+	if len(b) > length {
+		length = len(b)
+	}
+	for i := 0; i < length; i++ {
+		x, y := extension, extension
+		if j := i - (length - len(a)); j >= 0 {
+			x = a[j]
+		}
+		if j := i - (length - len(b)); j >= 0 {
+			y = b[j]
+		}
+		if x != y {
+			return compareOrdered(uint32(x), uint32(y))
+		}
+	}
+	return 0
+}
+
+// The min and max of a batch, skipping values that are not ordered (NaN)
+func batchMinMax[T any](values []T, less func(a T, b T) bool,
+	skip func(v T) bool) (T, T, bool) {
+	var min, max T
+	found := false
+	for _, v := range values {
+		if skip != nil && skip(v) {
+			continue
+		}
+		if !found {
+			min, max, found = v, v, true
+			continue
+		}
+		if less(v, min) {
+			min = v
+		}
+		if less(max, v) {
+			max = v
+		}
+	}
+	return min, max, found
+}
+
+// Update the statistics with a batch of non null values, in the typed slice
+// of the column's physical type, and the number of nulls next to them
+func (s *Statistics) Update(values interface{}, numNull int64) {
+	s.nullCount += numNull
+	s.numValues += int64(encoding.ValuesLen(values))
+	s.trackDistinct(values)
+	var min, max interface{}
+	found := false
+	switch v := values.(type) {
+	case []bool:
+		min, max, found = boxMinMax(batchMinMax(v, func(a, b bool) bool { return !a && b }, nil))
+	case []int32:
+		less := func(a, b int32) bool { return a < b }
+		if s.order == ptype.SortOrder_UNSIGNED {
+			less = func(a, b int32) bool { return uint32(a) < uint32(b) }
+		}
+		min, max, found = boxMinMax(batchMinMax(v, less, nil))
+	case []int64:
+		less := func(a, b int64) bool { return a < b }
+		if s.order == ptype.SortOrder_UNSIGNED {
+			less = func(a, b int64) bool { return uint64(a) < uint64(b) }
+		}
+		min, max, found = boxMinMax(batchMinMax(v, less, nil))
+	case []ptype.Int96:
+		// No defined order
+	case []float32:
+		min, max, found = boxMinMax(batchMinMax(v, func(a, b float32) bool { return a < b },
+			func(v float32) bool { return v != v }))
+	case []float64:
+		min, max, found = boxMinMax(batchMinMax(v, func(a, b float64) bool { return a < b },
+			math.IsNaN))
+	case []ptype.ByteArray:
+		less := func(a, b ptype.ByteArray) bool { return compareBytes(s.order, a, b) < 0 }
+		var minValue, maxValue ptype.ByteArray
+		if minValue, maxValue, found = batchMinMax(v, less, nil); found {
+			// Copy, the values may alias buffers reused by the caller
+			min = append(ptype.ByteArray(nil), minValue...)
+			max = append(ptype.ByteArray(nil), maxValue...)
+		}
+	case []ptype.FixedLenByteArray:
+		less := func(a, b ptype.FixedLenByteArray) bool { return compareBytes(s.order, a, b) < 0 }
+		var minValue, maxValue ptype.FixedLenByteArray
+		if minValue, maxValue, found = batchMinMax(v, less, nil); found {
+			min = append(ptype.FixedLenByteArray(nil), minValue...)
+			max = append(ptype.FixedLenByteArray(nil), maxValue...)
+		}
+	default:
+		panic(fmt.Errorf("Unsupported value slice %T", values))
+	}
+	if found && s.order != ptype.SortOrder_UNKNOWN {
+		s.updateMinMax(min, max)
+	}
+}
+
+func boxMinMax[T any](min T, max T, found bool) (interface{}, interface{}, bool) {
+	return min, max, found
+}
+
+func trackDistinctValues[T comparable](distinct map[interface{}]struct{}, values []T) {
+	for _, v := range values {
+		distinct[v] = struct{}{}
+	}
+}
+
+func (s *Statistics) trackDistinct(values interface{}) {
+	if s.distinct == nil {
+		return
+	}
+	switch v := values.(type) {
+	case []bool:
+		trackDistinctValues(s.distinct, v)
+	case []int32:
+		trackDistinctValues(s.distinct, v)
+	case []int64:
+		trackDistinctValues(s.distinct, v)
+	case []ptype.Int96:
+		trackDistinctValues(s.distinct, v)
+	case []float32:
+		for _, value := range v {
+			if value == value {
+				s.distinct[value] = struct{}{}
+			}
+		}
+	case []float64:
+		for _, value := range v {
+			if !math.IsNaN(value) {
+				s.distinct[value] = struct{}{}
+			}
+		}
+	case []ptype.ByteArray:
+		for _, value := range v {
+			s.distinct[string(value)] = struct{}{}
+		}
+	case []ptype.FixedLenByteArray:
+		for _, value := range v {
+			s.distinct[string(value)] = struct{}{}
+		}
+	}
+}
+
+func (s *Statistics) updateMinMax(min interface{}, max interface{}) {
+	min, max = normalizeZeros(min, max)
+	if !s.hasMinMax {
+		s.min, s.max, s.hasMinMax = min, max, true
+		return
+	}
+	if s.Compare(min, s.min) < 0 {
+		s.min = min
+	}
+	if s.Compare(s.max, max) < 0 {
+		s.max = max
+	}
+}
+
+// A zero min is written as -0.0 and a zero max as +0.0, since the sign of
+// the zeros of a page is not known from its bounds
+func normalizeZeros(min interface{}, max interface{}) (interface{}, interface{}) {
+	switch x := min.(type) {
+	case float32:
+		if x == 0 {
+			min = float32(math.Copysign(0, -1))
+		}
+		if max.(float32) == 0 {
+			max = float32(0)
+		}
+	case float64:
+		if x == 0 {
+			min = math.Copysign(0, -1)
+		}
+		if max.(float64) == 0 {
+			max = float64(0)
+		}
+	}
+	return min, max
+}
+
+// Whether a float min / max read from a file is NaN, which orders nothing
+func isNaN(v interface{}) bool {
+	switch x := v.(type) {
+	case float32:
+		return x != x
+	case float64:
+		return math.IsNaN(x)
+	}
+	return false
+}
+
+// Merge the statistics of another page or column chunk of the same column
+func (s *Statistics) Merge(other *Statistics) {
+	s.numValues += other.numValues
+	s.nullCount += other.nullCount
+	s.hasNullCount = s.hasNullCount && other.hasNullCount
+	if other.hasMinMax {
+		s.updateMinMax(other.min, other.max)
+		// Conservatively, a bound may have come from either side
+		s.minInexact = s.minInexact || other.minInexact
+		s.maxInexact = s.maxInexact || other.maxInexact
+	}
+	switch {
+	case s.distinct != nil && other.distinct != nil:
+		for value := range other.distinct {
+			s.distinct[value] = struct{}{}
+		}
+	default:
+		// Distinct counts cannot be added up
+		s.distinct = nil
+		s.hasDistinctCount = false
+	}
+}
+
+func (s *Statistics) EncodeMin() []byte {
+	return encodeStatisticsValue(s.min)
+}
+
+func (s *Statistics) EncodeMax() []byte {
+	return encodeStatisticsValue(s.max)
+}
+
+func (s *Statistics) Encode() *EncodedStatistics {
+	encoded := &EncodedStatistics{}
+	if s.hasMinMax {
+		min, max := s.EncodeMin(), s.EncodeMax()
+		if s.truncateLength > 0 && s.order == ptype.SortOrder_UNSIGNED &&
+			(s.descr.PhysicalType() == ptype.Type_BYTE_ARRAY ||
+				s.descr.PhysicalType() == ptype.Type_FIXED_LEN_BYTE_ARRAY) {
+			isUTF8 := s.descr.LogicalType() == ptype.LogicalType_UTF8
+			var minExact, maxExact bool
+			min, minExact = truncateMin(min, s.truncateLength, isUTF8)
+			max, maxExact = truncateMax(max, s.truncateLength, isUTF8)
+			encoded.SetExact(minExact && !s.minInexact, maxExact && !s.maxInexact)
+		} else if s.minInexact || s.maxInexact {
+			encoded.SetExact(!s.minInexact, !s.maxInexact)
+		}
+		encoded.SetMin(min)
+		encoded.SetMax(max)
+		encoded.SignedOrder = s.order == ptype.SortOrder_SIGNED
+	}
+	if s.hasNullCount {
+		encoded.SetNullCount(s.nullCount)
+	}
+	if s.hasDistinctCount {
+		encoded.SetDistinctCount(s.DistinctCount())
+	}
+	return encoded
+}
+
+// Min and max are stored PLAIN encoded, byte arrays without their length
+func encodeStatisticsValue(v interface{}) []byte {
+	switch value := v.(type) {
+	case bool:
+		if value {
+			return []byte{1}
+		}
+		return []byte{0}
+	case int32:
+		result := make([]byte, 4)
+		binary.LittleEndian.PutUint32(result, uint32(value))
+		return result
+	case int64:
+		result := make([]byte, 8)
+		binary.LittleEndian.PutUint64(result, uint64(value))
+		return result
+	case ptype.Int96:
+		result := make([]byte, 12)
+		for i, word := range value {
+			binary.LittleEndian.PutUint32(result[i*4:], word)
+		}
+		return result
+	case float32:
+		return encodeStatisticsValue(int32(math.Float32bits(value)))
+	case float64:
+		return encodeStatisticsValue(int64(math.Float64bits(value)))
+	case ptype.ByteArray:
+		return append([]byte(nil), value...)
+	case ptype.FixedLenByteArray:
+		return append([]byte(nil), value...)
+	}
+	panic(fmt.Errorf("Cannot encode statistics value of type %T", v))
+}
+
+// A prefix of at most length bytes is a lower bound of value. Cuts UTF8 at
+// a character boundary.
+func truncateMin(value []byte, length int, isUTF8 bool) ([]byte, bool) {
+	if len(value) <= length {
+		return value, true
+	}
+	if isUTF8 {
+		for length > 0 && !utf8.RuneStart(value[length]) {
+			length--
+		}
+	}
+	return value[:length], false
+}
+
+// Incrementing the last byte (character for UTF8) of a prefix gives an upper
+// bound of value. Returns value itself if no prefix can be incremented.
+func truncateMax(value []byte, length int, isUTF8 bool) ([]byte, bool) {
+	if len(value) <= length {
+		return value, true
+	}
+	if isUTF8 {
+		for length > 0 && !utf8.RuneStart(value[length]) {
+			length--
+		}
+		prefix := value[:length]
+		for len(prefix) > 0 {
+			r, size := utf8.DecodeLastRune(prefix)
+			prefix = prefix[:len(prefix)-size]
+			if r == utf8.RuneError && size <= 1 {
+				break
+			}
+			if r++; r >= 0xD800 && r <= 0xDFFF {
+				// Surrogates are not valid in UTF8
+				r = 0xE000
+			}
+			if r <= utf8.MaxRune {
+				result := append([]byte(nil), prefix...)
+				return append(result, string(r)...), false
+			}
+		}
+		return value, true
+	}
+	result := append([]byte(nil), value[:length]...)
+	for i := len(result) - 1; i >= 0; i-- {
+		if result[i] != 0xFF {
+			result[i]++
+			return result[:i+1], false
+		}
+	}
+	return value, true
+}
+
+// Truncated byte arrays may be shorter than the column's type length
+func decodeStatisticsValue(descr *schema.ColumnDescriptor, data []byte,
+	exact bool) (interface{}, bool) {
+	switch descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		if len(data) == 1 {
+			return data[0] != 0, true
+		}
+	case ptype.Type_INT32:
+		if len(data) == 4 {
+			return int32(binary.LittleEndian.Uint32(data)), true
+		}
+	case ptype.Type_INT64:
+		if len(data) == 8 {
+			return int64(binary.LittleEndian.Uint64(data)), true
+		}
+	case ptype.Type_INT96:
+		if len(data) == 12 {
+			var value ptype.Int96
+			for i := range value {
+				value[i] = binary.LittleEndian.Uint32(data[i*4:])
+			}
+			return value, true
+		}
+	case ptype.Type_FLOAT:
+		if len(data) == 4 {
+			return math.Float32frombits(binary.LittleEndian.Uint32(data)), true
+		}
+	case ptype.Type_DOUBLE:
+		if len(data) == 8 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(data)), true
+		}
+	case ptype.Type_BYTE_ARRAY:
+		return append(ptype.ByteArray{}, data...), true
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		if len(data) == int(descr.TypeLength()) ||
+			!exact && len(data) < int(descr.TypeLength()) {
+			return append(ptype.FixedLenByteArray{}, data...), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,317 @@
+package column
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+)
+
+const statisticsSchema = `message statistics {
+  optional int32 signed;
+  optional int32 unsigned (UINT_32);
+  optional int64 unsigned64 (UINT_64);
+  optional double real;
+  optional binary text (UTF8);
+  optional fixed_len_byte_array(2) decimal (DECIMAL(4,2));
+  optional int96 legacy;
+  optional boolean flag;
+  optional float single;
+}
+`
+
+func statisticsColumns() *schema.SchemaDescriptor {
+	return schema.NewSchemaDescriptor(&schema.MustParse(statisticsSchema).Node)
+}
+
+func TestGetSortOrder(t *testing.T) {
+	tests := []struct {
+		logical  ptype.LogicalType
+		physical ptype.Type
+		order    ptype.SortOrder
+	}{
+		{ptype.LogicalType_NONE, ptype.Type_INT32, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_NONE, ptype.Type_BOOLEAN, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_NONE, ptype.Type_DOUBLE, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_NONE, ptype.Type_BYTE_ARRAY, ptype.SortOrder_UNSIGNED},
+		{ptype.LogicalType_NONE, ptype.Type_FIXED_LEN_BYTE_ARRAY, ptype.SortOrder_UNSIGNED},
+		{ptype.LogicalType_NONE, ptype.Type_INT96, ptype.SortOrder_UNKNOWN},
+		{ptype.LogicalType_UINT_32, ptype.Type_INT32, ptype.SortOrder_UNSIGNED},
+		{ptype.LogicalType_UINT_64, ptype.Type_INT64, ptype.SortOrder_UNSIGNED},
+		{ptype.LogicalType_DATE, ptype.Type_INT32, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_TIMESTAMP_MICROS, ptype.Type_INT64, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_UTF8, ptype.Type_BYTE_ARRAY, ptype.SortOrder_UNSIGNED},
+		{ptype.LogicalType_DECIMAL, ptype.Type_BYTE_ARRAY, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_DECIMAL, ptype.Type_FIXED_LEN_BYTE_ARRAY, ptype.SortOrder_SIGNED},
+		{ptype.LogicalType_INTERVAL, ptype.Type_FIXED_LEN_BYTE_ARRAY, ptype.SortOrder_UNKNOWN},
+	}
+	for _, test := range tests {
+		if order := ptype.GetSortOrder(test.logical, test.physical); order != test.order {
+			t.Errorf("%s %s: order %d, want %d", ptype.LogicalTypeToString(test.logical),
+				ptype.TypeToString(test.physical), order, test.order)
+		}
+	}
+}
+
+func TestStatisticsMinMax(t *testing.T) {
+	descr := statisticsColumns()
+	tests := []struct {
+		column   int
+		values   interface{}
+		min, max interface{}
+	}{
+		{0, []int32{3, -7, 12, 0}, int32(-7), int32(12)},
+		// -1 is the largest UINT_32
+		{1, []int32{3, -1, 12, 0}, int32(0), int32(-1)},
+		{2, []int64{math.MinInt64, 5}, int64(5), int64(math.MinInt64)},
+		{3, []float64{math.NaN(), 2.5, -1, math.NaN()}, -1.0, 2.5},
+		{4, []ptype.ByteArray{ptype.ByteArray("b"), ptype.ByteArray("\xff"),
+			ptype.ByteArray("ab")}, ptype.ByteArray("ab"), ptype.ByteArray("\xff")},
+		// Two's complement big endian: -2.56, 1.00 and -0.01
+		{5, []ptype.FixedLenByteArray{{0xff, 0x00}, {0x00, 0x64}, {0xff, 0xff}},
+			ptype.FixedLenByteArray{0xff, 0x00}, ptype.FixedLenByteArray{0x00, 0x64}},
+		{7, []bool{true, true}, true, true},
+		{8, []float32{float32(math.NaN()), 1.5}, float32(1.5), float32(1.5)},
+	}
+	for _, test := range tests {
+		statistics := NewStatistics(descr.Column(test.column), false)
+		statistics.Update(test.values, 2)
+		name := descr.Column(test.column).Name()
+		if !statistics.HasMinMax() || !reflect.DeepEqual(statistics.Min(), test.min) ||
+			!reflect.DeepEqual(statistics.Max(), test.max) {
+			t.Errorf("%s: min %v max %v, want %v %v", name, statistics.Min(), statistics.Max(),
+				test.min, test.max)
+		}
+		if statistics.NullCount() != 2 ||
+			statistics.NumValues() != int64(reflect.ValueOf(test.values).Len()) {
+			t.Errorf("%s: %d values, %d nulls", name, statistics.NumValues(), statistics.NullCount())
+		}
+	}
+
+	// INT96 has no defined order, and a batch of NaN has no min / max
+	statistics := NewStatistics(descr.Column(6), false)
+	statistics.Update([]ptype.Int96{{1, 2, 3}}, 0)
+	if statistics.HasMinMax() || statistics.SortOrder() != ptype.SortOrder_UNKNOWN {
+		t.Errorf("INT96 statistics have a min / max")
+	}
+	statistics = NewStatistics(descr.Column(3), false)
+	statistics.Update([]float64{math.NaN()}, 0)
+	if statistics.HasMinMax() {
+		t.Errorf("NaN statistics have a min / max")
+	}
+}
+
+func TestStatisticsMerge(t *testing.T) {
+	descr := statisticsColumns().Column(4)
+	first := NewStatistics(descr, true)
+	first.Update([]ptype.ByteArray{ptype.ByteArray("m"), ptype.ByteArray("c"),
+		ptype.ByteArray("m")}, 1)
+	second := NewStatistics(descr, true)
+	second.Update([]ptype.ByteArray{ptype.ByteArray("a"), ptype.ByteArray("m")}, 0)
+	if first.DistinctCount() != 2 {
+		t.Errorf("%d distinct values, want 2", first.DistinctCount())
+	}
+	first.Merge(second)
+	if !reflect.DeepEqual(first.Min(), ptype.ByteArray("a")) ||
+		!reflect.DeepEqual(first.Max(), ptype.ByteArray("m")) {
+		t.Errorf("merged min %v max %v", first.Min(), first.Max())
+	}
+	if first.NumValues() != 5 || first.NullCount() != 1 || first.DistinctCount() != 3 {
+		t.Errorf("merged %d values, %d nulls, %d distinct", first.NumValues(),
+			first.NullCount(), first.DistinctCount())
+	}
+	// Counts without the values cannot be merged
+	first.Merge(NewStatistics(descr, false))
+	if first.HasDistinctCount() {
+		t.Errorf("merged statistics kept a distinct count of %d", first.DistinctCount())
+	}
+	first.Reset()
+	if first.HasMinMax() || first.NumValues() != 0 || first.NullCount() != 0 {
+		t.Errorf("statistics were not reset")
+	}
+}
+
+func TestStatisticsEncodeRoundTrip(t *testing.T) {
+	descr := statisticsColumns()
+	tests := []struct {
+		column int
+		values interface{}
+	}{
+		{0, []int32{-5, 7}},
+		{1, []int32{1, -1}},
+		{3, []float64{-0.5, 1e300}},
+		{4, []ptype.ByteArray{ptype.ByteArray("x"), ptype.ByteArray("yz")}},
+		{5, []ptype.FixedLenByteArray{{0x80, 0x00}, {0x7f, 0xff}}},
+		{7, []bool{false, true}},
+		{8, []float32{-1.25, 3}},
+	}
+	for _, test := range tests {
+		statistics := NewStatistics(descr.Column(test.column), true)
+		statistics.Update(test.values, 3)
+		encoded := EncodedStatisticsFromThrift(statistics.Encode().ToThrift())
+		decoded := NewStatisticsFromEncoded(descr.Column(test.column), encoded,
+			statistics.NumValues()+statistics.NullCount())
+		name := descr.Column(test.column).Name()
+		if !decoded.HasMinMax() || !reflect.DeepEqual(decoded.Min(), statistics.Min()) ||
+			!reflect.DeepEqual(decoded.Max(), statistics.Max()) {
+			t.Errorf("%s: decoded min %v max %v, want %v %v", name, decoded.Min(),
+				decoded.Max(), statistics.Min(), statistics.Max())
+		}
+		if decoded.NumValues() != statistics.NumValues() || decoded.NullCount() != 3 ||
+			!decoded.HasDistinctCount() || decoded.DistinctCount() != 2 {
+			t.Errorf("%s: decoded %d values, %d nulls, %d distinct", name, decoded.NumValues(),
+				decoded.NullCount(), decoded.DistinctCount())
+		}
+	}
+
+	// Values of the wrong size are dropped
+	encoded := (&EncodedStatistics{}).SetMin([]byte{1}).SetMax([]byte{2})
+	if NewStatisticsFromEncoded(descr.Column(0), encoded, 1).HasMinMax() {
+		t.Errorf("decoded a min / max of one byte for an int32 column")
+	}
+}
+
+func TestStatisticsFloatZerosAndNaN(t *testing.T) {
+	descr := statisticsColumns()
+	negativeZero := math.Copysign(0, -1)
+	statistics := NewStatistics(descr.Column(3), true)
+	statistics.Update([]float64{0, math.NaN(), 0, math.NaN()}, 0)
+	// A zero min is -0.0 and a zero max +0.0, whatever the sign written
+	if min, max := statistics.Min().(float64), statistics.Max().(float64); min != 0 ||
+		!math.Signbit(min) || max != 0 || math.Signbit(max) {
+		t.Errorf("min %v max %v, want -0 and +0", min, max)
+	}
+	if statistics.DistinctCount() != 1 || statistics.NumValues() != 4 {
+		t.Errorf("%d distinct of %d values", statistics.DistinctCount(), statistics.NumValues())
+	}
+	statistics.Update([]float64{negativeZero, 2}, 0)
+	if max := statistics.Max().(float64); max != 2 || !math.Signbit(statistics.Min().(float64)) {
+		t.Errorf("min %v max %v", statistics.Min(), max)
+	}
+
+	single := NewStatistics(descr.Column(8), true)
+	single.Update([]float32{float32(negativeZero), float32(math.NaN())}, 0)
+	if max := single.Max().(float32); max != 0 || math.Signbit(float64(max)) || single.DistinctCount() != 1 {
+		t.Errorf("float max %v, %d distinct", max, single.DistinctCount())
+	}
+
+	// Bounds read from a file are normalized too, and NaN ones dropped
+	encoded := (&EncodedStatistics{}).SetMin(encodeStatisticsValue(0.0)).
+		SetMax(encodeStatisticsValue(negativeZero))
+	decoded := NewStatisticsFromEncoded(descr.Column(3), encoded, 1)
+	if !math.Signbit(decoded.Min().(float64)) || math.Signbit(decoded.Max().(float64)) {
+		t.Errorf("decoded min %v max %v", decoded.Min(), decoded.Max())
+	}
+	encoded = (&EncodedStatistics{}).SetMin(encodeStatisticsValue(math.NaN())).
+		SetMax(encodeStatisticsValue(1.0))
+	if NewStatisticsFromEncoded(descr.Column(3), encoded, 1).HasMinMax() {
+		t.Errorf("decoded a NaN min")
+	}
+}
+
+func TestDeprecatedStatistics(t *testing.T) {
+	descr := statisticsColumns()
+	// Only signed orders fill the deprecated fields too
+	signed := NewStatistics(descr.Column(0), false)
+	signed.Update([]int32{-1, 1}, 0)
+	if written := signed.Encode().ToThrift(); written.Min == nil || written.MinValue == nil {
+		t.Errorf("signed statistics written as %v", written)
+	}
+	unsigned := NewStatistics(descr.Column(1), false)
+	unsigned.Update([]int32{-1, 1}, 0)
+	if written := unsigned.Encode().ToThrift(); written.Min != nil || written.Max != nil ||
+		written.MaxValue == nil {
+		t.Errorf("unsigned statistics written as %v", written)
+	}
+
+	deprecated := thrift.NewStatistics()
+	deprecated.Min, deprecated.Max = encodeStatisticsValue(int32(-1)), encodeStatisticsValue(int32(1))
+	for _, test := range []struct {
+		column int
+		ok     bool
+	}{{0, true}, {1, false}} {
+		decoded := NewStatisticsFromEncoded(descr.Column(test.column),
+			EncodedStatisticsFromThrift(deprecated), 2)
+		if decoded.HasMinMax() != test.ok {
+			t.Errorf("%s: deprecated min / max decoded %v, want %v",
+				descr.Column(test.column).Name(), decoded.HasMinMax(), test.ok)
+		}
+	}
+	// min_value / max_value win over the deprecated fields
+	deprecated.MinValue, deprecated.MaxValue = encodeStatisticsValue(int32(0)), encodeStatisticsValue(int32(-1))
+	decoded := NewStatisticsFromEncoded(descr.Column(1), EncodedStatisticsFromThrift(deprecated), 2)
+	if !decoded.HasMinMax() || decoded.Min() != int32(0) || decoded.Max() != int32(-1) {
+		t.Errorf("decoded min %v max %v", decoded.Min(), decoded.Max())
+	}
+}
+
+func TestTruncateStatistics(t *testing.T) {
+	tests := []struct {
+		value    string
+		isUTF8   bool
+		min, max string
+		exact    bool
+	}{
+		{"abc", false, "abc", "abc", true},
+		{"abcdef", false, "abc", "abd", false},
+		{"ab\xff\xffzz", false, "ab\xff", "ac", false},
+		// No prefix can be incremented, the max is kept whole
+		{"\xff\xff\xff\xff", false, "\xff\xff\xff", "\xff\xff\xff\xff", false},
+		// Cut at a character boundary
+		{"héllo", true, "hé", "hê", false},
+		{"a\U0010FFFF\U0010FFFFb", true, "a", "b", false},
+		// Incrementing past the surrogates
+		{"\ud7ffzzz", true, "\ud7ff", "\ue000", false},
+	}
+	for _, test := range tests {
+		min, minExact := truncateMin([]byte(test.value), 3, test.isUTF8)
+		max, maxExact := truncateMax([]byte(test.value), 3, test.isUTF8)
+		if string(min) != test.min || string(max) != test.max || minExact != test.exact ||
+			maxExact != (test.exact || test.max == test.value) {
+			t.Errorf("%q: min %q %v, max %q %v", test.value, min, minExact, max, maxExact)
+		}
+	}
+}
+
+func TestTruncatedStatisticsRoundTrip(t *testing.T) {
+	descr := statisticsColumns()
+	text := NewStatistics(descr.Column(4), false)
+	text.SetTruncateLength(4)
+	text.Update([]ptype.ByteArray{ptype.ByteArray("apple pie"), ptype.ByteArray("kiwi"),
+		ptype.ByteArray("zucchini")}, 0)
+	encoded := text.Encode()
+	if string(encoded.Min()) != "appl" || string(encoded.Max()) != "zucd" ||
+		!encoded.HasExact || encoded.IsMinExact || encoded.IsMaxExact {
+		t.Errorf("encoded min %q max %q exact %v %v", encoded.Min(), encoded.Max(),
+			encoded.IsMinExact, encoded.IsMaxExact)
+	}
+	decoded := NewStatisticsFromEncoded(descr.Column(4),
+		EncodedStatisticsFromThrift(encoded.ToThrift()), 3)
+	if !decoded.HasMinMax() || decoded.IsMinExact() || decoded.IsMaxExact() ||
+		string(decoded.Min().(ptype.ByteArray)) != "appl" {
+		t.Errorf("decoded min %v max %v exact %v %v", decoded.Min(), decoded.Max(),
+			decoded.IsMinExact(), decoded.IsMaxExact())
+	}
+	// The bounds stay inexact once merged and encoded again
+	merged := NewStatistics(descr.Column(4), false)
+	merged.Merge(decoded)
+	if encoded := merged.Encode(); !encoded.HasExact || encoded.IsMinExact || encoded.IsMaxExact {
+		t.Errorf("merged bounds are exact")
+	}
+
+	// Values within the length are exact, and signed DECIMAL is not truncated
+	text.Reset()
+	text.Update([]ptype.ByteArray{ptype.ByteArray("ab")}, 0)
+	if encoded := text.Encode(); !encoded.IsMinExact || !encoded.IsMaxExact {
+		t.Errorf("short values are not exact")
+	}
+	decimal := NewStatistics(descr.Column(5), false)
+	decimal.SetTruncateLength(1)
+	decimal.Update([]ptype.FixedLenByteArray{{0x01, 0x02}}, 0)
+	if encoded := decimal.Encode(); len(encoded.Min()) != 2 || encoded.HasExact {
+		t.Errorf("DECIMAL min %v was truncated", encoded.Min())
+	}
+}
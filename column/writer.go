@@ -0,0 +1,388 @@
+package column
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+// ColumnWriter writes the values of one column chunk. Values are passed as
+// the typed slice matching the column's physical type, see the encoding
+// package for the mapping.
+type ColumnWriter struct {
+	descr         *schema.ColumnDescriptor
+	pager         PageWriter
+	expectedRows  int64
+	hasDictionary bool
+	encoding      ptype.Encoding
+	properties    *WriterProperties
+
+	currentEncoder encoding.Encoder
+	dictEncoder    *encoding.DictEncoder
+
+	// The total number of values stored in the data page. This is the maximum
+	// of the number of encoded definition levels or encoded values. For
+	// non-repeated, required columns, this is equal to the number of encoded
+	// values. For repeated or optional values, there may be fewer data values
+	// than levels, and this tells you how many encoded levels there are in that
+	// case.
+	numBufferedValues int64
+
+	// The total number of stored values. For repeated or optional values, this
+	// number may be lower than numBufferedValues.
+	numBufferedEncodedValues int64
+
+	// Total number of rows written with this ColumnWriter
+	numRows int64
+
+	// Number of rows starting in the buffered data page
+	numBufferedRows int64
+
+	// Records the total number of bytes written by the serializer
+	totalBytesWritten int64
+
+	// Flag to check if the Writer has been closed
+	closed bool
+
+	// Flag to infer if dictionary encoding has fallen back to PLAIN
+	fallback bool
+
+	definitionLevels []int16
+	repetitionLevels []int16
+
+	dataPages []*CompressedDataPage
+
+	// Not set when statistics are disabled for the column
+	pageStatistics  *Statistics
+	chunkStatistics *Statistics
+
+	// Not set when the Bloom filter is disabled for the column
+	bloomFilter *bloom.BlockSplitBloomFilter
+	// Without expected rows nor NDV, the hashes of the values, the Bloom
+	// filter being sized for them on Close
+	bloomHashes map[uint64]struct{}
+
+	// Only set when the order of a sorting column is validated
+	rowOrder *rowOrderTracker
+}
+
+func NewColumnWriter(descr *schema.ColumnDescriptor, pager PageWriter,
+	expectedRows int64, hasDictionary bool, enc ptype.Encoding,
+	properties *WriterProperties) *ColumnWriter {
+	w := &ColumnWriter{
+		descr:         descr,
+		pager:         pager,
+		expectedRows:  expectedRows,
+		hasDictionary: hasDictionary,
+		encoding:      enc,
+		properties:    properties,
+	}
+	if properties.StatisticsEnabled(descr.Path()) {
+		trackDistinct := properties.DistinctCountEnabled(descr.Path())
+		w.pageStatistics = NewStatistics(descr, trackDistinct)
+		w.chunkStatistics = NewStatistics(descr, trackDistinct)
+		truncateLength := properties.StatisticsTruncateLength(descr.Path())
+		w.pageStatistics.SetTruncateLength(truncateLength)
+		w.chunkStatistics.SetTruncateLength(truncateLength)
+	}
+	if properties.BloomFilterEnabled(descr.Path()) &&
+		descr.PhysicalType() != ptype.Type_BOOLEAN {
+		ndv := properties.BloomFilterNDV(descr.Path())
+		if ndv == 0 {
+			ndv = expectedRows
+		}
+		if ndv > 0 {
+			w.bloomFilter = bloom.NewBlockSplitBloomFilter(
+				bloom.OptimalNumBytes(ndv, properties.BloomFilterFPP(descr.Path())))
+		} else {
+			w.bloomHashes = make(map[uint64]struct{})
+		}
+	}
+	if hasDictionary {
+		w.dictEncoder = encoding.NewDictEncoder(descr.PhysicalType(),
+			int(descr.TypeLength()))
+		w.currentEncoder = w.dictEncoder
+	} else if enc == ptype.Encoding_PLAIN {
+		w.currentEncoder = encoding.NewPlainEncoder(descr.PhysicalType(),
+			int(descr.TypeLength()))
+	} else {
+		panic(fmt.Errorf("Selected encoding is not supported: %s",
+			ptype.EncodingToString(enc)))
+	}
+	return w
+}
+
+func NewColumnWriterMake(descr *schema.ColumnDescriptor, pager PageWriter,
+	expectedRows int64, properties *WriterProperties) *ColumnWriter {
+	useDictionary := properties.DictionaryEnabled(descr.Path()) &&
+		descr.PhysicalType() != ptype.Type_BOOLEAN
+	enc := properties.Encoding(descr.Path())
+	if useDictionary {
+		enc = properties.DictionaryIndexEncoding()
+	}
+	return NewColumnWriter(descr, pager, expectedRows, useDictionary, enc,
+		properties)
+}
+
+func (w *ColumnWriter) Descr() *schema.ColumnDescriptor {
+	return w.descr
+}
+
+func (w *ColumnWriter) Type() ptype.Type {
+	return w.descr.PhysicalType()
+}
+
+func (w *ColumnWriter) RowsWritten() int64 {
+	return w.numRows
+}
+
+// Estimated size of the column chunk written so far: the pages written, the
+// pages held until the dictionary is written, the dictionary and the values
+// buffered for the next page
+func (w *ColumnWriter) EstimatedSize() int64 {
+	size := w.totalBytesWritten + w.currentEncoder.EstimatedDataEncodedSize()
+	for _, page := range w.dataPages {
+		size += int64(page.Buffer().Len())
+	}
+	if w.hasDictionary && !w.fallback {
+		size += w.dictEncoder.DictEncodedSize()
+	}
+	return size
+}
+
+// Write a batch of repetition levels, definition levels, and values to the
+// column.
+func (w *ColumnWriter) WriteBatch(numValues int64, defLevels []int16,
+	repLevels []int16, values interface{}) {
+	// We check for DataPage limits only after we have inserted the values. If a
+	// user writes a large number of values, the DataPage size can be much above
+	// the limit. The purpose of this chunking is to bound this. Even if a user
+	// writes large number of values, the chunking will ensure the AddDataPage()
+	// is called at a reasonable pagesize limit. Pages of repeated columns must
+	// start with a row for the offset index, so their chunks are extended to
+	// end before the next one
+	writeBatchSize := w.properties.WriteBatchSize()
+	var valueOffset int64
+	for offset := int64(0); offset < numValues; {
+		end := offset + writeBatchSize
+		if end > numValues {
+			end = numValues
+		}
+		if w.descr.MaxRepetitionLevel() > 0 {
+			for end < numValues && end < int64(len(repLevels)) && repLevels[end] != 0 {
+				end++
+			}
+		}
+		valueOffset += w.WriteMiniBatch(end-offset,
+			levelsSlice(defLevels, offset, end),
+			levelsSlice(repLevels, offset, end),
+			values, valueOffset)
+		offset = end
+	}
+}
+
+func levelsSlice(levels []int16, start int64, end int64) []int16 {
+	if levels == nil {
+		return nil
+	}
+	return levels[start:end]
+}
+
+// Write values for a batch of levels, starting at valueOffset in values.
+// Returns the number of values consumed. The page may be cut after the batch,
+// so the batches of repeated columns must end before a row.
+func (w *ColumnWriter) WriteMiniBatch(numValues int64, defLevels []int16,
+	repLevels []int16, values interface{}, valueOffset int64) int64 {
+	if w.closed {
+		panic(fmt.Errorf("Cannot write to a closed column writer"))
+	}
+	valuesToWrite := int64(0)
+	// If the field is required and non-repeated, there are no definition levels
+	if w.descr.MaxDefinitionLevel() > 0 {
+		if int64(len(defLevels)) < numValues {
+			panic(fmt.Errorf("Expected %d definition levels, got %d", numValues,
+				len(defLevels)))
+		}
+		for _, level := range defLevels {
+			if level == w.descr.MaxDefinitionLevel() {
+				valuesToWrite++
+			}
+		}
+		w.definitionLevels = append(w.definitionLevels, defLevels...)
+	} else {
+		// Required field, write all values
+		valuesToWrite = numValues
+	}
+
+	// Not present for non-repeated fields
+	if w.descr.MaxRepetitionLevel() > 0 {
+		if int64(len(repLevels)) < numValues {
+			panic(fmt.Errorf("Expected %d repetition levels, got %d", numValues,
+				len(repLevels)))
+		}
+		// A row could include more than one value
+		// Count the occasions where we start a new row
+		for _, level := range repLevels {
+			if level == 0 {
+				w.numRows++
+				w.numBufferedRows++
+			}
+		}
+		w.repetitionLevels = append(w.repetitionLevels, repLevels...)
+	} else {
+		// Each value is exactly one row
+		w.numRows += numValues
+		w.numBufferedRows += numValues
+	}
+
+	if w.expectedRows > 0 && w.numRows > w.expectedRows {
+		panic(fmt.Errorf("More rows were written in the column chunk than expected"))
+	}
+
+	if int64(encoding.ValuesLen(values)) < valueOffset+valuesToWrite {
+		panic(fmt.Errorf("Expected %d values, got %d", valueOffset+valuesToWrite,
+			encoding.ValuesLen(values)))
+	}
+	batch := encoding.SliceValues(values, int(valueOffset), int(valueOffset+valuesToWrite))
+	w.currentEncoder.Put(batch)
+	if w.pageStatistics != nil {
+		w.pageStatistics.Update(batch, numValues-valuesToWrite)
+	}
+	if w.bloomFilter != nil {
+		w.bloomFilter.InsertValues(batch)
+	} else if w.bloomHashes != nil {
+		bloom.HashValues(batch, func(hash uint64) { w.bloomHashes[hash] = struct{}{} })
+	}
+	if w.rowOrder != nil {
+		w.rowOrder.update(numValues, defLevels, batch)
+	}
+
+	w.numBufferedValues += numValues
+	w.numBufferedEncodedValues += valuesToWrite
+
+	if w.currentEncoder.EstimatedDataEncodedSize() >= w.properties.DataPagesize() {
+		w.AddDataPage()
+	}
+	if w.hasDictionary && !w.fallback {
+		w.CheckDictionarySizeLimit()
+	}
+
+	return valuesToWrite
+}
+
+// Serializes the buffered levels and values into a data page
+func (w *ColumnWriter) AddDataPage() {
+	var buffer bytes.Buffer
+	if w.descr.MaxRepetitionLevel() > 0 {
+		levelEncoder := NewLevelEncoder(w.descr.MaxRepetitionLevel())
+		levelEncoder.Encode(w.repetitionLevels)
+		buffer.Write(levelEncoder.Flush())
+	}
+	if w.descr.MaxDefinitionLevel() > 0 {
+		levelEncoder := NewLevelEncoder(w.descr.MaxDefinitionLevel())
+		levelEncoder.Encode(w.definitionLevels)
+		buffer.Write(levelEncoder.Flush())
+	}
+	buffer.Write(w.currentEncoder.FlushValues())
+	uncompressedSize := int32(buffer.Len())
+
+	var pageStatistics EncodedStatistics
+	if w.pageStatistics != nil {
+		pageStatistics = *w.pageStatistics.Encode()
+		w.chunkStatistics.Merge(w.pageStatistics)
+		w.pageStatistics.Reset()
+	}
+
+	compressedData := w.pager.Compress(&buffer)
+	page := NewCompressedDataPage(compressedData, int32(w.numBufferedValues),
+		w.encoding, ptype.Encoding_RLE, ptype.Encoding_RLE, uncompressedSize,
+		pageStatistics, int32(w.numBufferedRows))
+
+	// Write the page to OutputStream eagerly if there is no dictionary or
+	// if dictionary encoding has fallen back to PLAIN
+	if w.hasDictionary && !w.fallback {
+		// Save pages until end of dictionary encoding
+		w.dataPages = append(w.dataPages, page)
+	} else {
+		// Eagerly write pages
+		w.WriteDataPage(page)
+	}
+
+	// Re-initialize the sinks as GetBuffer made them invalid.
+	w.definitionLevels = w.definitionLevels[:0]
+	w.repetitionLevels = w.repetitionLevels[:0]
+	w.numBufferedValues = 0
+	w.numBufferedEncodedValues = 0
+	w.numBufferedRows = 0
+}
+
+func (w *ColumnWriter) WriteDataPage(page *CompressedDataPage) {
+	w.totalBytesWritten += w.pager.WriteDataPage(page)
+}
+
+func (w *ColumnWriter) WriteDictionaryPage() {
+	buffer := bytes.NewBuffer(w.dictEncoder.WriteDict())
+	page := NewDictionaryPage(buffer, int32(w.dictEncoder.NumEntries()),
+		w.properties.DictionaryPageEncoding(), false)
+	w.totalBytesWritten += w.pager.WriteDictionaryPage(page)
+}
+
+// Checks if the Dictionary Page size limit is reached
+// If the limit is reached, the Dictionary and Data Pages are serialized
+// The encoding is switched to PLAIN
+func (w *ColumnWriter) CheckDictionarySizeLimit() {
+	if w.dictEncoder.DictEncodedSize() >= w.properties.DictionaryPagesizeLimit() {
+		w.WriteDictionaryPage()
+		// Serialize the buffered Dictionary Indicies
+		w.FlushBufferedDataPages()
+		w.fallback = true
+		// Only PLAIN encoding is supported for fallback in V1
+		w.currentEncoder = encoding.NewPlainEncoder(w.descr.PhysicalType(),
+			int(w.descr.TypeLength()))
+		w.encoding = ptype.Encoding_PLAIN
+	}
+}
+
+func (w *ColumnWriter) FlushBufferedDataPages() {
+	// Write all outstanding data to a new page
+	if w.numBufferedValues > 0 {
+		w.AddDataPage()
+	}
+	for _, page := range w.dataPages {
+		w.WriteDataPage(page)
+	}
+	w.dataPages = nil
+}
+
+// Closes the ColumnWriter, commits any buffered values to pages.
+//
+// Returns the total bytes written to the sink
+func (w *ColumnWriter) Close() int64 {
+	if !w.closed {
+		w.closed = true
+		if w.hasDictionary && !w.fallback {
+			w.WriteDictionaryPage()
+		}
+
+		w.FlushBufferedDataPages()
+
+		var chunkStatistics *EncodedStatistics
+		if w.chunkStatistics != nil {
+			chunkStatistics = w.chunkStatistics.Encode()
+		}
+		if w.bloomHashes != nil {
+			w.bloomFilter = bloom.NewBlockSplitBloomFilter(bloom.OptimalNumBytes(
+				int64(len(w.bloomHashes)), w.properties.BloomFilterFPP(w.descr.Path())))
+			for hash := range w.bloomHashes {
+				w.bloomFilter.InsertHash(hash)
+			}
+			w.bloomHashes = nil
+		}
+		w.pager.Close(w.hasDictionary, w.fallback, chunkStatistics, w.bloomFilter)
+	}
+
+	return w.totalBytesWritten
+}
@@ -0,0 +1,233 @@
+package encoding
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"math"
+)
+
+// ----------------------------------------------------------------------
+// Dictionary encoder
+//
+// Values are memoized into a dictionary, and the data is encoded as a
+// bit width byte followed by the RLE / bit-packed hybrid encoding of the
+// dictionary indices. The dictionary itself is written PLAIN encoded into
+// the dictionary page.
+
+type DictEncoder struct {
+	_type      ptype.Type
+	typeLength int
+	memo       map[interface{}]int32
+	dictionary *PlainEncoder
+	// Plain encoded size of the dictionary
+	dictEncodedSize int64
+	numEntries      int
+	indices         []int32
+}
+
+func NewDictEncoder(_type ptype.Type, typeLength int) *DictEncoder {
+	if _type == ptype.Type_BOOLEAN {
+		panic(fmt.Errorf("Dictionary encoding is not supported for BOOLEAN"))
+	}
+	return &DictEncoder{
+		_type:      _type,
+		typeLength: typeLength,
+		memo:       make(map[interface{}]int32),
+		dictionary: NewPlainEncoder(_type, typeLength),
+	}
+}
+
+func (e *DictEncoder) Encoding() ptype.Encoding {
+	return ptype.Encoding_PLAIN_DICTIONARY
+}
+
+func (e *DictEncoder) NumEntries() int {
+	return e.numEntries
+}
+
+func (e *DictEncoder) DictEncodedSize() int64 {
+	return e.dictEncodedSize
+}
+
+func (e *DictEncoder) BitWidth() int {
+	if e.numEntries == 0 {
+		return 0
+	}
+	return BitWidth(uint64(e.numEntries - 1))
+}
+
+func (e *DictEncoder) putIndex(key interface{}, value interface{}, size int64) {
+	index, ok := e.memo[key]
+	if !ok {
+		index = int32(e.numEntries)
+		e.memo[key] = index
+		e.numEntries++
+		e.dictEncodedSize += size
+		e.dictionary.Put(value)
+	}
+	e.indices = append(e.indices, index)
+}
+
+func (e *DictEncoder) Put(values interface{}) {
+	switch v := values.(type) {
+	case []int32:
+		for i, value := range v {
+			e.putIndex(value, v[i:i+1], 4)
+		}
+	case []int64:
+		for i, value := range v {
+			e.putIndex(value, v[i:i+1], 8)
+		}
+	case []ptype.Int96:
+		for i, value := range v {
+			e.putIndex(value, v[i:i+1], 12)
+		}
+	case []float32:
+		for i, value := range v {
+			e.putIndex(math.Float32bits(value), v[i:i+1], 4)
+		}
+	case []float64:
+		for i, value := range v {
+			e.putIndex(math.Float64bits(value), v[i:i+1], 8)
+		}
+	case []ptype.ByteArray:
+		for i, value := range v {
+			e.putIndex(string(value), v[i:i+1], int64(4+len(value)))
+		}
+	case []ptype.FixedLenByteArray:
+		for i, value := range v {
+			e.putIndex(string(value), v[i:i+1], int64(e.typeLength))
+		}
+	default:
+		panic(fmt.Errorf("Unsupported value slice type %T", values))
+	}
+}
+
+func (e *DictEncoder) EstimatedDataEncodedSize() int64 {
+	// Account for the bit width byte and the worst case of bit packing
+	return 1 + int64(len(e.indices)+7)/8*int64(e.BitWidth()+1)
+}
+
+func (e *DictEncoder) FlushValues() []byte {
+	bitWidth := e.BitWidth()
+	encoder := NewRleEncoder(bitWidth)
+	for _, index := range e.indices {
+		encoder.Put(uint64(index))
+	}
+	e.indices = e.indices[:0]
+	return append([]byte{byte(bitWidth)}, encoder.Flush()...)
+}
+
+// The PLAIN encoded dictionary, written as the dictionary page
+func (e *DictEncoder) WriteDict() []byte {
+	return e.dictionary.FlushValues()
+}
+
+// ----------------------------------------------------------------------
+// Dictionary decoder
+
+type DictDecoder struct {
+	dictionary interface{}
+	numEntries int
+	indices    *RleDecoder
+	numValues  int
+	scratch    []int32
+}
+
+func NewDictDecoder() *DictDecoder {
+	return &DictDecoder{indices: &RleDecoder{}}
+}
+
+func (d *DictDecoder) Encoding() ptype.Encoding {
+	return ptype.Encoding_RLE_DICTIONARY
+}
+
+// Decode the dictionary page values with the given decoder
+func (d *DictDecoder) SetDict(_type ptype.Type, numEntries int, dictionary Decoder) {
+	d.dictionary = MakeValues(_type, numEntries)
+	d.numEntries = dictionary.Decode(d.dictionary)
+	if d.numEntries != numEntries {
+		panic(fmt.Errorf("Corrupt dictionary page: expected %d entries, read %d",
+			numEntries, d.numEntries))
+	}
+}
+
+func (d *DictDecoder) Dictionary() interface{} {
+	return d.dictionary
+}
+
+func (d *DictDecoder) SetData(numValues int, data []byte) {
+	if len(data) == 0 {
+		if numValues > 0 {
+			panic(fmt.Errorf("Corrupt dictionary indices: missing bit width"))
+		}
+		d.numValues = 0
+		return
+	}
+	d.numValues = numValues
+	d.indices.Reset(data[1:], int(data[0]))
+}
+
+func (d *DictDecoder) ValuesLeft() int {
+	return d.numValues
+}
+
+func (d *DictDecoder) Decode(values interface{}) int {
+	n := ValuesLen(values)
+	if n > d.numValues {
+		n = d.numValues
+	}
+	if cap(d.scratch) < n {
+		d.scratch = make([]int32, n)
+	}
+	indices := d.scratch[:n]
+	if d.indices.GetBatch(indices) != n {
+		panic(fmt.Errorf("Corrupt dictionary indices: expected %d values", n))
+	}
+	for _, index := range indices {
+		if index < 0 || int(index) >= d.numEntries {
+			panic(fmt.Errorf("Dictionary index %d out of range [0, %d)", index, d.numEntries))
+		}
+	}
+	switch v := values.(type) {
+	case []int32:
+		dict := d.dictionary.([]int32)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []int64:
+		dict := d.dictionary.([]int64)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []ptype.Int96:
+		dict := d.dictionary.([]ptype.Int96)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []float32:
+		dict := d.dictionary.([]float32)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []float64:
+		dict := d.dictionary.([]float64)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []ptype.ByteArray:
+		dict := d.dictionary.([]ptype.ByteArray)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	case []ptype.FixedLenByteArray:
+		dict := d.dictionary.([]ptype.FixedLenByteArray)
+		for i, index := range indices {
+			v[i] = dict[index]
+		}
+	default:
+		panic(fmt.Errorf("Unsupported value slice type %T", values))
+	}
+	d.numValues -= n
+	return n
+}
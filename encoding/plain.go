@@ -0,0 +1,287 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"math"
+)
+
+// Encoders take and decoders fill typed value slices. Each physical type
+// maps to exactly one slice type:
+//
+//   BOOLEAN              []bool
+//   INT32                []int32
+//   INT64                []int64
+//   INT96                []ptype.Int96
+//   FLOAT                []float32
+//   DOUBLE               []float64
+//   BYTE_ARRAY           []ptype.ByteArray
+//   FIXED_LEN_BYTE_ARRAY []ptype.FixedLenByteArray
+
+type Encoder interface {
+	Encoding() ptype.Encoding
+	Put(values interface{})
+	EstimatedDataEncodedSize() int64
+	FlushValues() []byte
+}
+
+type Decoder interface {
+	Encoding() ptype.Encoding
+	SetData(numValues int, data []byte)
+	// Decode up to len(values) values into values, returns the number decoded
+	Decode(values interface{}) int
+	ValuesLeft() int
+}
+
+// Make an empty value slice of the given physical type
+func MakeValues(_type ptype.Type, length int) interface{} {
+	switch _type {
+	case ptype.Type_BOOLEAN:
+		return make([]bool, length)
+	case ptype.Type_INT32:
+		return make([]int32, length)
+	case ptype.Type_INT64:
+		return make([]int64, length)
+	case ptype.Type_INT96:
+		return make([]ptype.Int96, length)
+	case ptype.Type_FLOAT:
+		return make([]float32, length)
+	case ptype.Type_DOUBLE:
+		return make([]float64, length)
+	case ptype.Type_BYTE_ARRAY:
+		return make([]ptype.ByteArray, length)
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		return make([]ptype.FixedLenByteArray, length)
+	}
+	panic(fmt.Errorf("Unknown physical type: %d", _type))
+}
+
+// Number of values in a typed value slice
+func ValuesLen(values interface{}) int {
+	switch v := values.(type) {
+	case []bool:
+		return len(v)
+	case []int32:
+		return len(v)
+	case []int64:
+		return len(v)
+	case []ptype.Int96:
+		return len(v)
+	case []float32:
+		return len(v)
+	case []float64:
+		return len(v)
+	case []ptype.ByteArray:
+		return len(v)
+	case []ptype.FixedLenByteArray:
+		return len(v)
+	}
+	panic(fmt.Errorf("Unsupported value slice type %T", values))
+}
+
+// values[start:end] for a typed value slice
+func SliceValues(values interface{}, start int, end int) interface{} {
+	switch v := values.(type) {
+	case []bool:
+		return v[start:end]
+	case []int32:
+		return v[start:end]
+	case []int64:
+		return v[start:end]
+	case []ptype.Int96:
+		return v[start:end]
+	case []float32:
+		return v[start:end]
+	case []float64:
+		return v[start:end]
+	case []ptype.ByteArray:
+		return v[start:end]
+	case []ptype.FixedLenByteArray:
+		return v[start:end]
+	}
+	panic(fmt.Errorf("Unsupported value slice type %T", values))
+}
+
+// ----------------------------------------------------------------------
+// Plain encoder
+
+type PlainEncoder struct {
+	_type      ptype.Type
+	typeLength int
+	sink       bytes.Buffer
+	bits       []bool
+}
+
+func NewPlainEncoder(_type ptype.Type, typeLength int) *PlainEncoder {
+	return &PlainEncoder{_type: _type, typeLength: typeLength}
+}
+
+func (e *PlainEncoder) Encoding() ptype.Encoding {
+	return ptype.Encoding_PLAIN
+}
+
+func (e *PlainEncoder) Put(values interface{}) {
+	var scratch [8]byte
+	switch v := values.(type) {
+	case []bool:
+		// bit packed when flushed
+		e.bits = append(e.bits, v...)
+	case []int32:
+		for _, value := range v {
+			binary.LittleEndian.PutUint32(scratch[:], uint32(value))
+			e.sink.Write(scratch[:4])
+		}
+	case []int64:
+		for _, value := range v {
+			binary.LittleEndian.PutUint64(scratch[:], uint64(value))
+			e.sink.Write(scratch[:8])
+		}
+	case []ptype.Int96:
+		for _, value := range v {
+			for _, word := range value {
+				binary.LittleEndian.PutUint32(scratch[:], word)
+				e.sink.Write(scratch[:4])
+			}
+		}
+	case []float32:
+		for _, value := range v {
+			binary.LittleEndian.PutUint32(scratch[:], math.Float32bits(value))
+			e.sink.Write(scratch[:4])
+		}
+	case []float64:
+		for _, value := range v {
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(value))
+			e.sink.Write(scratch[:8])
+		}
+	case []ptype.ByteArray:
+		for _, value := range v {
+			binary.LittleEndian.PutUint32(scratch[:], uint32(len(value)))
+			e.sink.Write(scratch[:4])
+			e.sink.Write(value)
+		}
+	case []ptype.FixedLenByteArray:
+		for _, value := range v {
+			if len(value) != e.typeLength {
+				panic(fmt.Errorf("FIXED_LEN_BYTE_ARRAY value has length %d, expected %d",
+					len(value), e.typeLength))
+			}
+			e.sink.Write(value)
+		}
+	default:
+		panic(fmt.Errorf("Unsupported value slice type %T", values))
+	}
+}
+
+func (e *PlainEncoder) EstimatedDataEncodedSize() int64 {
+	return int64(e.sink.Len()) + int64(len(e.bits)+7)/8
+}
+
+func (e *PlainEncoder) FlushValues() []byte {
+	if e._type == ptype.Type_BOOLEAN {
+		packed := make([]byte, (len(e.bits)+7)/8)
+		for i, value := range e.bits {
+			if value {
+				packed[i/8] |= 1 << uint(i%8)
+			}
+		}
+		e.bits = e.bits[:0]
+		return packed
+	}
+	result := make([]byte, e.sink.Len())
+	copy(result, e.sink.Bytes())
+	e.sink.Reset()
+	return result
+}
+
+// ----------------------------------------------------------------------
+// Plain decoder
+
+type PlainDecoder struct {
+	_type      ptype.Type
+	typeLength int
+	data       []byte
+	numValues  int
+	bitOffset  int
+}
+
+func NewPlainDecoder(_type ptype.Type, typeLength int) *PlainDecoder {
+	return &PlainDecoder{_type: _type, typeLength: typeLength}
+}
+
+func (d *PlainDecoder) Encoding() ptype.Encoding {
+	return ptype.Encoding_PLAIN
+}
+
+func (d *PlainDecoder) SetData(numValues int, data []byte) {
+	d.numValues = numValues
+	d.data = data
+	d.bitOffset = 0
+}
+
+func (d *PlainDecoder) ValuesLeft() int {
+	return d.numValues
+}
+
+func (d *PlainDecoder) need(numBytes int) []byte {
+	if len(d.data) < numBytes {
+		panic(fmt.Errorf("Corrupt PLAIN data: need %d bytes, %d left", numBytes, len(d.data)))
+	}
+	result := d.data[:numBytes]
+	d.data = d.data[numBytes:]
+	return result
+}
+
+func (d *PlainDecoder) Decode(values interface{}) int {
+	n := ValuesLen(values)
+	if n > d.numValues {
+		n = d.numValues
+	}
+	switch v := values.(type) {
+	case []bool:
+		if (d.bitOffset+n+7)/8 > len(d.data) {
+			panic(fmt.Errorf("Corrupt PLAIN data: not enough bits for %d booleans", n))
+		}
+		for i := 0; i < n; i++ {
+			v[i] = d.data[d.bitOffset/8]&(1<<uint(d.bitOffset%8)) != 0
+			d.bitOffset++
+		}
+	case []int32:
+		for i := 0; i < n; i++ {
+			v[i] = int32(binary.LittleEndian.Uint32(d.need(4)))
+		}
+	case []int64:
+		for i := 0; i < n; i++ {
+			v[i] = int64(binary.LittleEndian.Uint64(d.need(8)))
+		}
+	case []ptype.Int96:
+		for i := 0; i < n; i++ {
+			buf := d.need(12)
+			for w := 0; w < 3; w++ {
+				v[i][w] = binary.LittleEndian.Uint32(buf[4*w:])
+			}
+		}
+	case []float32:
+		for i := 0; i < n; i++ {
+			v[i] = math.Float32frombits(binary.LittleEndian.Uint32(d.need(4)))
+		}
+	case []float64:
+		for i := 0; i < n; i++ {
+			v[i] = math.Float64frombits(binary.LittleEndian.Uint64(d.need(8)))
+		}
+	case []ptype.ByteArray:
+		for i := 0; i < n; i++ {
+			length := int(binary.LittleEndian.Uint32(d.need(4)))
+			v[i] = ptype.ByteArray(d.need(length))
+		}
+	case []ptype.FixedLenByteArray:
+		for i := 0; i < n; i++ {
+			v[i] = ptype.FixedLenByteArray(d.need(d.typeLength))
+		}
+	default:
+		panic(fmt.Errorf("Unsupported value slice type %T", values))
+	}
+	d.numValues -= n
+	return n
+}
@@ -0,0 +1,216 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// RLE / bit-packing hybrid encoding, used for repetition / definition levels
+// and dictionary indices.
+//
+// rle-bit-packed-hybrid: <length> <encoded-data>
+// encoded-data := <run>*
+// run := <bit-packed-run> | <rle-run>
+// bit-packed-run := <bit-packed-header> <bit-packed-values>
+// bit-packed-header := varint-encode(<bit-pack-count> << 1 | 1)
+// rle-run := <rle-header> <repeated-value>
+// rle-header := varint-encode( (number of times repeated) << 1)
+//
+// Values of a bit-packed run are packed from the least significant bit of
+// each byte; each bit-packed run holds a multiple of 8 values.
+
+// Minimum number of repeated values worth encoding as a run
+const minRepeatedRunLength = 8
+
+// Number of bits needed to represent values in [0, maxValue]
+func BitWidth(maxValue uint64) int {
+	return bits.Len64(maxValue)
+}
+
+type RleEncoder struct {
+	bitWidth int
+	values   []uint64
+}
+
+func NewRleEncoder(bitWidth int) *RleEncoder {
+	if bitWidth < 0 || bitWidth > 64 {
+		panic(fmt.Errorf("Invalid RLE bit width: %d", bitWidth))
+	}
+	return &RleEncoder{bitWidth: bitWidth}
+}
+
+func (e *RleEncoder) Put(value uint64) {
+	e.values = append(e.values, value)
+}
+
+func (e *RleEncoder) NumBufferedValues() int {
+	return len(e.values)
+}
+
+// Upper bound of the encoded size of the buffered values
+func (e *RleEncoder) EstimatedEncodedSize() int64 {
+	groups := int64(len(e.values)+7) / 8
+	return groups*int64(e.bitWidth) + groups*binary.MaxVarintLen32
+}
+
+// Encode the buffered values and reset the encoder
+func (e *RleEncoder) Flush() []byte {
+	var buffer bytes.Buffer
+	values := e.values
+	n := len(values)
+	i := 0
+	for i < n {
+		run := runLength(values, i, n)
+		if run >= minRepeatedRunLength {
+			e.writeRepeatedRun(&buffer, values[i], run)
+			i += run
+			continue
+		}
+
+		// Bit pack groups of 8 values until a long enough run starts on a
+		// group boundary
+		start := i
+		for i < n {
+			i += 8
+			if i >= n {
+				i = n
+				break
+			}
+			if runLength(values, i, n) >= minRepeatedRunLength {
+				break
+			}
+		}
+		e.writeLiteralRun(&buffer, values[start:i])
+	}
+	e.values = e.values[:0]
+	return buffer.Bytes()
+}
+
+func runLength(values []uint64, start int, end int) int {
+	run := 1
+	for start+run < end && values[start+run] == values[start] {
+		run++
+	}
+	return run
+}
+
+func (e *RleEncoder) writeRepeatedRun(buffer *bytes.Buffer, value uint64, count int) {
+	var header [binary.MaxVarintLen64]byte
+	buffer.Write(header[:binary.PutUvarint(header[:], uint64(count)<<1)])
+	for i := 0; i < (e.bitWidth+7)/8; i++ {
+		buffer.WriteByte(byte(value >> (8 * uint(i))))
+	}
+}
+
+func (e *RleEncoder) writeLiteralRun(buffer *bytes.Buffer, values []uint64) {
+	groups := (len(values) + 7) / 8
+	var header [binary.MaxVarintLen64]byte
+	buffer.Write(header[:binary.PutUvarint(header[:], uint64(groups)<<1|1)])
+
+	// The last group is padded with zeros
+	packed := make([]byte, groups*e.bitWidth)
+	bitOffset := 0
+	for _, value := range values {
+		for b := 0; b < e.bitWidth; b++ {
+			if value&(1<<uint(b)) != 0 {
+				packed[bitOffset/8] |= 1 << uint(bitOffset%8)
+			}
+			bitOffset++
+		}
+	}
+	buffer.Write(packed)
+}
+
+type RleDecoder struct {
+	data         []byte
+	pos          int
+	bitWidth     int
+	currentValue uint64
+	repeatCount  int
+	literalCount int
+	bitOffset    int
+}
+
+func NewRleDecoder(data []byte, bitWidth int) *RleDecoder {
+	d := &RleDecoder{}
+	d.Reset(data, bitWidth)
+	return d
+}
+
+func (d *RleDecoder) Reset(data []byte, bitWidth int) {
+	if bitWidth < 0 || bitWidth > 64 {
+		panic(fmt.Errorf("Invalid RLE bit width: %d", bitWidth))
+	}
+	d.data = data
+	d.pos = 0
+	d.bitWidth = bitWidth
+	d.repeatCount = 0
+	d.literalCount = 0
+}
+
+// Read the next run header, returns false if the data is exhausted
+func (d *RleDecoder) nextCounts() bool {
+	if d.pos >= len(d.data) {
+		return false
+	}
+	header, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		panic(fmt.Errorf("Corrupt RLE run header"))
+	}
+	d.pos += n
+	if header&1 == 1 {
+		d.literalCount = int(header>>1) * 8
+		d.bitOffset = d.pos * 8
+		d.pos += int(header>>1) * d.bitWidth
+		if d.pos > len(d.data) {
+			panic(fmt.Errorf("Corrupt RLE bit-packed run: truncated data"))
+		}
+	} else {
+		d.repeatCount = int(header >> 1)
+		width := (d.bitWidth + 7) / 8
+		if d.pos+width > len(d.data) {
+			panic(fmt.Errorf("Corrupt RLE run: truncated value"))
+		}
+		d.currentValue = 0
+		for i := 0; i < width; i++ {
+			d.currentValue |= uint64(d.data[d.pos+i]) << (8 * uint(i))
+		}
+		d.pos += width
+	}
+	return true
+}
+
+func (d *RleDecoder) Next() (uint64, bool) {
+	for d.repeatCount == 0 && d.literalCount == 0 {
+		if !d.nextCounts() {
+			return 0, false
+		}
+	}
+	if d.repeatCount > 0 {
+		d.repeatCount--
+		return d.currentValue, true
+	}
+	var value uint64
+	for b := 0; b < d.bitWidth; b++ {
+		if d.data[d.bitOffset/8]&(1<<uint(d.bitOffset%8)) != 0 {
+			value |= 1 << uint(b)
+		}
+		d.bitOffset++
+	}
+	d.literalCount--
+	return value, true
+}
+
+// Decode up to len(values) values, returns the number of values decoded
+func (d *RleDecoder) GetBatch(values []int32) int {
+	for i := range values {
+		value, ok := d.Next()
+		if !ok {
+			return i
+		}
+		values[i] = int32(value)
+	}
+	return len(values)
+}
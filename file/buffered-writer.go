@@ -0,0 +1,85 @@
+package file
+
+import (
+	"github.com/zenixls2/goparquet/column"
+)
+
+// Writes rows to buffered row groups as they come, closing the row group
+// and starting a new one once it holds MaxRows rows or an estimated
+// MaxBytes bytes. A limit of 0 is disabled. Without MaxRows the Bloom
+// filters without a BloomFilterNDV are sized for the values of the row
+// group once it is closed.
+//
+// Rows are written to all the columns of the current row group with Column,
+// EndRows then checks the size of the row group.
+type BufferedRowGroupWriter struct {
+	FileWriter *ParquetFileWriter
+	MaxRows    int64
+	MaxBytes   int64
+	rowGroup   *RowGroupWriter
+}
+
+func NewBufferedRowGroupWriter(file_writer *ParquetFileWriter, max_rows int64,
+	max_bytes int64) *BufferedRowGroupWriter {
+	return &BufferedRowGroupWriter{
+		FileWriter: file_writer,
+		MaxRows:    max_rows,
+		MaxBytes:   max_bytes,
+	}
+}
+
+// The writer of column i of the current row group, starting one if needed.
+// The writers are only valid until the row group is closed by EndRows or
+// Flush.
+func (b *BufferedRowGroupWriter) Column(i int) *column.ColumnWriter {
+	if b.rowGroup == nil {
+		b.rowGroup = b.FileWriter.AppendBufferedRowGroup(b.MaxRows)
+	}
+	return b.rowGroup.Column(i)
+}
+
+// Number of rows written to the current row group
+func (b *BufferedRowGroupWriter) NumRows() int64 {
+	if b.rowGroup == nil {
+		return 0
+	}
+	return b.rowGroup.NumRows()
+}
+
+// Estimated size of the current row group once written
+func (b *BufferedRowGroupWriter) EstimatedSize() int64 {
+	if b.rowGroup == nil {
+		return 0
+	}
+	size := int64(0)
+	for i := 0; i < b.rowGroup.NumColumns(); i++ {
+		size += b.rowGroup.Column(i).EstimatedSize()
+	}
+	return size
+}
+
+// Called once the rows written are complete in all the columns, closes the
+// row group if it reached MaxRows or MaxBytes
+func (b *BufferedRowGroupWriter) EndRows() {
+	if b.rowGroup == nil {
+		return
+	}
+	if b.MaxRows > 0 && b.NumRows() >= b.MaxRows ||
+		b.MaxBytes > 0 && b.EstimatedSize() >= b.MaxBytes {
+		b.Flush()
+	}
+}
+
+// Close the current row group, the next rows start a new one
+func (b *BufferedRowGroupWriter) Flush() {
+	if b.rowGroup != nil {
+		b.rowGroup.Close()
+		b.rowGroup = nil
+	}
+}
+
+// Close the current row group and the file
+func (b *BufferedRowGroupWriter) Close() {
+	b.Flush()
+	b.FileWriter.Close()
+}
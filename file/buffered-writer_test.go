@@ -0,0 +1,114 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+const bufferedSchema = `message buffered {
+  required int32 id;
+  optional binary name (UTF8);
+}
+`
+
+// Write numRows rows a few at a time and read the file back
+func writeBufferedFile(t *testing.T, properties *column.WriterProperties, max_rows int64,
+	max_bytes int64, numRows int) *ParquetFileReader {
+	var buffer bytes.Buffer
+	file_writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(bufferedSchema), properties)
+	writer := NewBufferedRowGroupWriter(file_writer, max_rows, max_bytes)
+	for i := 0; i < numRows; i += 3 {
+		ids := []int32{int32(i), int32(i + 1), int32(i + 2)}
+		names := []ptype.ByteArray{ptype.ByteArray(fmt.Sprintf("name %06d", i))}
+		writer.Column(0).WriteBatch(3, nil, nil, ids)
+		writer.Column(1).WriteBatch(3, []int16{1, 0, 0}, nil, names)
+		writer.EndRows()
+		if writer.NumRows() >= max_rows && max_rows > 0 {
+			t.Errorf("%d rows buffered with at most %d", writer.NumRows(), max_rows)
+		}
+	}
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if reader.NumRows() != int64(numRows) {
+		t.Errorf("%d rows written, want %d", reader.NumRows(), numRows)
+	}
+	return reader
+}
+
+// Write numRows rows a few at a time and return the rows of each row group
+func writeBufferedRows(t *testing.T, properties *column.WriterProperties, max_rows int64,
+	max_bytes int64, numRows int) []int64 {
+	reader := writeBufferedFile(t, properties, max_rows, max_bytes, numRows)
+	var rows []int64
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		rows = append(rows, reader.RowGroup(i).NumRows())
+	}
+	return rows
+}
+
+func TestBufferedRowGroupWriterMaxRows(t *testing.T) {
+	rows := writeBufferedRows(t, nil, 30, 0, 99)
+	if fmt.Sprint(rows) != "[30 30 30 9]" {
+		t.Errorf("row groups of %v rows", rows)
+	}
+}
+
+func TestBufferedRowGroupWriterMaxBytes(t *testing.T) {
+	properties := column.NewWriterPropertiesBuilder().DisableDictionary().Build()
+	rows := writeBufferedRows(t, properties, 0, 4000, 3000)
+	if len(rows) < 3 {
+		t.Fatalf("row groups of %v rows", rows)
+	}
+	for _, numRows := range rows[:len(rows)-1] {
+		// About 4 + 6 bytes a row
+		if numRows < 200 || numRows > 600 || numRows != rows[0] {
+			t.Errorf("row groups of %v rows", rows)
+			break
+		}
+	}
+}
+
+func TestBufferedRowGroupWriterBloomFilterNDV(t *testing.T) {
+	properties := column.NewWriterPropertiesBuilder().EnableBloomFilterFor("name").
+		BloomFilterNDVFor("name", 100).Build()
+	if rows := writeBufferedRows(t, properties, 0, 4000, 30); fmt.Sprint(rows) != "[30]" {
+		t.Errorf("row groups of %v rows", rows)
+	}
+	properties = column.NewWriterPropertiesBuilder().EnableBloomFilterFor("name").Build()
+	if rows := writeBufferedRows(t, properties, 21, 0, 30); fmt.Sprint(rows) != "[21 9]" {
+		t.Errorf("row groups of %v rows", rows)
+	}
+}
+
+func TestBufferedRowGroupWriterBloomFilterMaxBytes(t *testing.T) {
+	properties := column.NewWriterPropertiesBuilder().DisableDictionary().
+		EnableBloomFilterFor("name").Build()
+	reader := writeBufferedFile(t, properties, 0, 4000, 3000)
+	if reader.NumRowGroups() < 3 {
+		t.Fatalf("%d row groups", reader.NumRowGroups())
+	}
+	first_row := 0
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		num_rows := int(reader.RowGroup(i).NumRows())
+		filter := reader.RowGroup(i).ColumnBloomFilter(1)
+		// Sized for the names of the row group, one every 3 rows
+		expected := bloom.NewBlockSplitBloomFilter(
+			bloom.OptimalNumBytes(int64(num_rows/3), column.DEFAULT_BLOOM_FILTER_FPP))
+		if filter == nil || filter.NumBytes() != expected.NumBytes() {
+			t.Fatalf("row group %d of %d rows: Bloom filter %v", i, num_rows, filter)
+		}
+		for row := first_row; row < first_row+num_rows; row += 3 {
+			if !filter.Find(ptype.ByteArray(fmt.Sprintf("name %06d", row))) {
+				t.Errorf("row group %d: name %d not found", i, row)
+			}
+		}
+		first_row += num_rows
+	}
+}
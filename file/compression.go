@@ -0,0 +1,70 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/golang/snappy"
+	"github.com/zenixls2/goparquet/ptype"
+	"io/ioutil"
+)
+
+type Codec struct {
+	codec ptype.Compression
+}
+
+// Returns nil for UNCOMPRESSED, callers use that as the fast path
+func NewCodec(codec ptype.Compression) *Codec {
+	switch codec {
+	case ptype.Compression_UNCOMPRESSED:
+		return nil
+	case ptype.Compression_SNAPPY, ptype.Compression_GZIP:
+		return &Codec{codec: codec}
+	default:
+		panic(fmt.Errorf("Unsupported compression codec: %s",
+			ptype.CompressionToString(codec)))
+	}
+}
+
+func (c *Codec) Compress(buffer *bytes.Buffer) *bytes.Buffer {
+	switch c.codec {
+	case ptype.Compression_SNAPPY:
+		return bytes.NewBuffer(snappy.Encode(nil, buffer.Bytes()))
+	case ptype.Compression_GZIP:
+		var result bytes.Buffer
+		writer := gzip.NewWriter(&result)
+		writer.Write(buffer.Bytes())
+		if err := writer.Close(); err != nil {
+			panic(fmt.Errorf("GZIP compression failed: %v", err))
+		}
+		return &result
+	}
+	panic(fmt.Errorf("Unsupported compression codec: %s",
+		ptype.CompressionToString(c.codec)))
+}
+
+func (c *Codec) Decompress(input []byte, uncompressedLen int) []byte {
+	var result []byte
+	var err error
+	switch c.codec {
+	case ptype.Compression_SNAPPY:
+		result, err = snappy.Decode(make([]byte, uncompressedLen), input)
+	case ptype.Compression_GZIP:
+		var reader *gzip.Reader
+		if reader, err = gzip.NewReader(bytes.NewReader(input)); err == nil {
+			result, err = ioutil.ReadAll(reader)
+		}
+	default:
+		panic(fmt.Errorf("Unsupported compression codec: %s",
+			ptype.CompressionToString(c.codec)))
+	}
+	if err != nil {
+		panic(fmt.Errorf("%s decompression failed: %v",
+			ptype.CompressionToString(c.codec), err))
+	}
+	if len(result) != uncompressedLen {
+		panic(fmt.Errorf("Decompressed %d bytes, expected %d", len(result),
+			uncompressedLen))
+	}
+	return result
+}
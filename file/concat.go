@@ -0,0 +1,36 @@
+package file
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"io"
+)
+
+// Write the row groups of the inputs, in order, to sink as a single file.
+// The inputs must have the same schema. The column chunks are copied as
+// they are, with their page indexes and Bloom filters, only their offsets
+// change. The key / value metadata of the footers is merged, the later
+// inputs replacing the values of the earlier ones. A nil properties uses
+// DefaultWriterProperties, of which only created_by is used.
+func Concat(sink io.Writer, inputs []*ParquetFileReader, properties *column.WriterProperties) {
+	if len(inputs) == 0 {
+		panic(fmt.Errorf("No files to concatenate"))
+	}
+	schema := inputs[0].Schema()
+	for i, input := range inputs[1:] {
+		if !schema.Equals(input.Schema()) {
+			panic(fmt.Errorf("The schema of file %d differs from that of file 0", i+1))
+		}
+	}
+	writer := NewParquetFileWriterOpen(sink, schema.GroupNode(), properties)
+	for _, input := range inputs {
+		for i := 0; i < input.NumRowGroups(); i++ {
+			writer.CopyRowGroup(input.RowGroup(i))
+		}
+		metadata := input.KeyValueMetadata()
+		for i := 0; i < metadata.Size(); i++ {
+			writer.AddKeyValueMetadata(metadata.Key(i), metadata.Value(i))
+		}
+	}
+	writer.Close()
+}
@@ -0,0 +1,193 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+// A file of row groups of the values of a, b being a dictionary encoded
+// name of each value
+func writeConcatInput(key string, row_groups ...[]int32) *ParquetFileReader {
+	properties := column.NewWriterPropertiesBuilder().
+		EnablePageIndex().
+		EnableBloomFilterFor("b").
+		BloomFilterNDV(16).
+		DataPagesize(32).
+		WriteBatchSize(8).
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(dictionarySchema), properties)
+	for _, a := range row_groups {
+		b := make([]ptype.ByteArray, len(a))
+		for i, value := range a {
+			b[i] = ptype.ByteArray(fmt.Sprint("v", value%5))
+		}
+		row_group := writer.AppendRowGroup(int64(len(a)))
+		row_group.NextColumn().WriteBatch(int64(len(a)), nil, nil, a)
+		row_group.NextColumn().WriteBatch(int64(len(b)), nil, nil, b)
+		row_group.Close()
+	}
+	writer.AddKeyValueMetadata("input", key)
+	writer.AddKeyValueMetadata(key, "1")
+	writer.Close()
+	return NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+}
+
+// The values of the required columns a and b of a row group
+func readConcatRowGroup(row_group *RowGroupReader) ([]int32, []string) {
+	var a []int32
+	reader := row_group.Column(0)
+	for reader.HasNext() {
+		values := make([]int32, 16)
+		_, n := reader.ReadBatch(16, nil, nil, values)
+		a = append(a, values[:n]...)
+	}
+	var b []string
+	reader = row_group.Column(1)
+	for reader.HasNext() {
+		values := make([]ptype.ByteArray, 16)
+		_, n := reader.ReadBatch(16, nil, nil, values)
+		for _, value := range values[:n] {
+			b = append(b, string(value))
+		}
+	}
+	return a, b
+}
+
+func sequence(start int32, n int) []int32 {
+	values := make([]int32, n)
+	for i := range values {
+		values[i] = start + int32(i)
+	}
+	return values
+}
+
+func TestConcat(t *testing.T) {
+	inputs := []*ParquetFileReader{
+		writeConcatInput("first", sequence(0, 40), sequence(40, 7)),
+		writeConcatInput("second", sequence(100, 30)),
+	}
+	var buffer bytes.Buffer
+	Concat(&buffer, inputs, nil)
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+
+	if reader.NumRowGroups() != 3 || reader.NumRows() != 77 {
+		t.Fatalf("%d row groups of %d rows", reader.NumRowGroups(), reader.NumRows())
+	}
+	expected := [][]int32{sequence(0, 40), sequence(40, 7), sequence(100, 30)}
+	for i, values := range expected {
+		a, b := readConcatRowGroup(reader.RowGroup(i))
+		if fmt.Sprint(a) != fmt.Sprint(values) || len(b) != len(values) || b[len(b)-1] != fmt.Sprint("v", values[len(values)-1]%5) {
+			t.Errorf("row group %d: %v %v", i, a, b)
+		}
+		if !reader.RowGroup(i).Metadata().ColumnChunk(1).HasDictionaryPage() {
+			t.Errorf("row group %d lost its dictionary", i)
+		}
+		filter := reader.RowGroup(i).ColumnBloomFilter(1)
+		if filter == nil || !filter.Find(ptype.ByteArray("v3")) {
+			t.Errorf("row group %d lost its Bloom filter", i)
+		}
+		if index := reader.RowGroup(i).OffsetIndex(0); index == nil ||
+			index.PageLocations[0].Offset != reader.RowGroup(i).Metadata().ColumnChunk(0).DataPageOffset() {
+			t.Errorf("row group %d offset index %v", i, index)
+		}
+	}
+	metadata := reader.KeyValueMetadata()
+	if value, _ := metadata.Get("input"); value != "second" || metadata.Size() != 3 || metadata.FindKey("first") < 0 {
+		t.Errorf("key / value metadata %v", metadata.ToThrift())
+	}
+}
+
+func TestConcatInvalid(t *testing.T) {
+	var buffer bytes.Buffer
+	NewParquetFileWriterOpen(&buffer, _schema.MustParse(keyValueSchema), nil).Close()
+	other := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	inputs := []*ParquetFileReader{writeConcatInput("first", sequence(0, 4)), other}
+	if message := builderPanic(func() { Concat(&bytes.Buffer{}, inputs, nil) }); message !=
+		"The schema of file 1 differs from that of file 0" {
+		t.Errorf("different schemas: %q", message)
+	}
+	if message := builderPanic(func() { Concat(&bytes.Buffer{}, nil, nil) }); message != "No files to concatenate" {
+		t.Errorf("no inputs: %q", message)
+	}
+}
+
+// The page index and dictionary of the copied column chunks are moved with
+// them, the Bloom filters being written after the row groups
+func TestConcatPageIndex(t *testing.T) {
+	inputs := []*ParquetFileReader{
+		writeConcatInput("first", sequence(0, 40), sequence(40, 7)),
+		writeConcatInput("second", sequence(1000, 200)),
+	}
+	var buffer bytes.Buffer
+	Concat(&buffer, inputs, nil)
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	sources := []*RowGroupReader{inputs[0].RowGroup(0), inputs[0].RowGroup(1), inputs[1].RowGroup(0)}
+
+	chunks_end := int64(4)
+	for i, source := range sources {
+		row_group := reader.RowGroup(i)
+		for c := 0; c < 2; c++ {
+			col := row_group.Metadata().ColumnChunk(c)
+			source_col := source.Metadata().ColumnChunk(c)
+			// The column chunks follow each other
+			if col.ChunkRange().Offset != chunks_end {
+				t.Errorf("row group %d column %d at %d, want %d", i, c, col.ChunkRange().Offset, chunks_end)
+			}
+			chunks_end = col.ChunkRange().Offset + col.ChunkRange().Length
+			delta := col.ChunkRange().Offset - source_col.ChunkRange().Offset
+			if col.DataPageOffset() != source_col.DataPageOffset()+delta ||
+				!col.HasDictionaryPage() ||
+				col.DictionaryPageOffset() != source_col.DictionaryPageOffset()+delta {
+				t.Errorf("row group %d column %d: offsets not moved by %d", i, c, delta)
+			}
+			locations := row_group.OffsetIndex(c).PageLocations
+			source_locations := source.OffsetIndex(c).PageLocations
+			if len(locations) != len(source_locations) {
+				t.Fatalf("row group %d column %d: %d pages, want %d", i, c, len(locations), len(source_locations))
+			}
+			for j, location := range locations {
+				if location.Offset != source_locations[j].Offset+delta ||
+					location.CompressedPageSize != source_locations[j].CompressedPageSize ||
+					location.FirstRowIndex != source_locations[j].FirstRowIndex {
+					t.Errorf("row group %d column %d page %d: %v, source %v", i, c, j, location, source_locations[j])
+				}
+			}
+		}
+	}
+	for i := range sources {
+		if offset := reader.RowGroup(i).Metadata().ColumnChunk(1).BloomFilterOffset(); offset < chunks_end {
+			t.Errorf("row group %d: Bloom filter at %d, between the column chunks", i, offset)
+		}
+	}
+
+	// Read the last page of each column of the row group of the second file,
+	// after its dictionary
+	row_group := reader.RowGroup(2)
+	last := RowRange{Start: row_group.NumRows() - 1, End: row_group.NumRows()}
+	a_reader, a_rows := row_group.ColumnRanges(0, []RowRange{last})
+	if len(a_rows) != 1 || a_rows[0].End != 200 || a_rows[0].Start == 0 {
+		t.Fatalf("pages of rows %v", a_rows)
+	}
+	a := make([]int32, 200)
+	_, n := a_reader.ReadBatch(200, nil, nil, a)
+	if fmt.Sprint(a[:n]) != fmt.Sprint(sequence(1000+int32(a_rows[0].Start), 200-int(a_rows[0].Start))) {
+		t.Errorf("a %v of rows %v", a[:n], a_rows)
+	}
+	b_reader, b_rows := row_group.ColumnRanges(1, []RowRange{last})
+	b := make([]ptype.ByteArray, 200)
+	_, n = b_reader.ReadBatch(200, nil, nil, b)
+	if len(b_rows) != 1 || b_rows[0].Start == 0 || int64(n) != b_rows[0].End-b_rows[0].Start {
+		t.Fatalf("%d values of rows %v", n, b_rows)
+	}
+	for k, value := range b[:n] {
+		if row := b_rows[0].Start + int64(k); string(value) != fmt.Sprint("v", row%5) {
+			t.Errorf("b of row %d: %s", row, value)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Reads a file served over HTTP with range requests
+type HTTPRangeReader struct {
+	URL    string
+	Client *http.Client
+	size   int64
+}
+
+// Finds the size of the file with a HEAD request, a nil client is
+// http.DefaultClient
+func NewHTTPRangeReader(url string, client *http.Client) *HTTPRangeReader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Head(url)
+	if err != nil {
+		panic(fmt.Errorf("Could not get the size of %s: %v", url, err))
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || response.ContentLength < 0 {
+		panic(fmt.Errorf("Could not get the size of %s: %s", url, response.Status))
+	}
+	return &HTTPRangeReader{URL: url, Client: client, size: response.ContentLength}
+}
+
+func (h *HTTPRangeReader) Size() int64 {
+	return h.size
+}
+
+func (h *HTTPRangeReader) ReadRange(offset int64, length int64) ([]byte, error) {
+	if length <= 0 {
+		return []byte{}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	response, err := h.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body := io.Reader(response.Body)
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range and sends the whole file
+		if _, err := io.CopyN(ioutil.Discard, body, offset); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Range request to %s failed: %s", h.URL, response.Status)
+	}
+	buffer := make([]byte, length)
+	n, err := io.ReadFull(body, buffer)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return buffer[:n], err
+}
+
+func (h *HTTPRangeReader) ReadAt(buffer []byte, offset int64) (int, error) {
+	data, err := h.ReadRange(offset, int64(len(buffer)))
+	n := copy(buffer, data)
+	if err == nil && n < len(buffer) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// API convenience to open a Parquet file served over HTTP
+func OpenURL(url string, client *http.Client) *ParquetFileReader {
+	source := NewHTTPRangeReader(url, client)
+	return NewParquetFileReaderOpen(source, source.Size())
+}
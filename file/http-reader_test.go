@@ -0,0 +1,114 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func httpTestData() []byte {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 13)
+	}
+	return data
+}
+
+func TestHTTPRangeReaderPartialContent(t *testing.T) {
+	data := httpTestData()
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			ranges = append(ranges, r.Header.Get("Range"))
+		}
+		http.ServeContent(w, r, "data", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	reader := NewHTTPRangeReader(server.URL, server.Client())
+	if reader.Size() != int64(len(data)) {
+		t.Fatalf("size %d, want %d", reader.Size(), len(data))
+	}
+	buffer, err := reader.ReadRange(1000, 500)
+	if err != nil || !bytes.Equal(buffer, data[1000:1500]) {
+		t.Errorf("ReadRange(1000, 500): %d bytes, %v", len(buffer), err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-1499" {
+		t.Errorf("requested ranges %v", ranges)
+	}
+	// Past the end of the file
+	buffer = make([]byte, 100)
+	n, err := reader.ReadAt(buffer, int64(len(data))-40)
+	if n != 40 || err != io.EOF || !bytes.Equal(buffer[:n], data[len(data)-40:]) {
+		t.Errorf("ReadAt at the end: %d, %v", n, err)
+	}
+}
+
+func TestHTTPRangeReaderIgnoredRange(t *testing.T) {
+	data := httpTestData()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The whole file whatever the range
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	reader := NewHTTPRangeReader(server.URL, server.Client())
+	for _, r := range []ByteRange{{0, 100}, {4321, 1000}, {9990, 10}} {
+		buffer, err := reader.ReadRange(r.Offset, r.Length)
+		if err != nil || !bytes.Equal(buffer, data[r.Offset:r.End()]) {
+			t.Errorf("ReadRange(%d, %d): %d bytes, %v", r.Offset, r.Length, len(buffer), err)
+		}
+	}
+}
+
+func TestHTTPRangeReaderShortRead(t *testing.T) {
+	data := httpTestData()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			return
+		}
+		// Half of the range asked for
+		var start, end int
+		fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end)
+		end = start + (end-start+1)/2
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start:end])
+	}))
+	defer server.Close()
+
+	reader := NewHTTPRangeReader(server.URL, server.Client())
+	buffer, err := reader.ReadRange(2000, 100)
+	if err != io.EOF || !bytes.Equal(buffer, data[2000:2050]) {
+		t.Errorf("ReadRange(2000, 100): %d bytes, %v", len(buffer), err)
+	}
+	if message := fetchPanic(reader, []ByteRange{{2000, 100}}); message == "" {
+		t.Errorf("FetchRanges did not fail on a short read")
+	}
+}
+
+func TestHTTPRangeReaderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", "100")
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	reader := NewHTTPRangeReader(server.URL, server.Client())
+	if _, err := reader.ReadRange(0, 10); err == nil {
+		t.Errorf("ReadRange did not fail on a %d response", http.StatusServiceUnavailable)
+	}
+}
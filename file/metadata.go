@@ -0,0 +1,716 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"io"
+	"sort"
+)
+
+// Key / value pairs of the footer or of a column chunk, in the order they
+// were added. Appending a key again replaces its value.
+type KeyValueMetadata struct {
+	keys   []string
+	values []string
+}
+
+func NewKeyValueMetadata() *KeyValueMetadata {
+	return &KeyValueMetadata{}
+}
+
+// Pairs without a value get an empty one
+func KeyValueMetadataFromThrift(pairs []*thrift.KeyValue) *KeyValueMetadata {
+	metadata := NewKeyValueMetadata()
+	for _, pair := range pairs {
+		metadata.Append(pair.Key, pair.GetValue())
+	}
+	return metadata
+}
+
+func (k *KeyValueMetadata) Append(key string, value string) {
+	if i := k.FindKey(key); i >= 0 {
+		k.values[i] = value
+		return
+	}
+	k.keys = append(k.keys, key)
+	k.values = append(k.values, value)
+}
+
+// The position of key, -1 if it is missing
+func (k *KeyValueMetadata) FindKey(key string) int {
+	for i, existing := range k.keys {
+		if existing == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (k *KeyValueMetadata) Get(key string) (string, bool) {
+	if i := k.FindKey(key); i >= 0 {
+		return k.values[i], true
+	}
+	return "", false
+}
+
+func (k *KeyValueMetadata) Size() int {
+	return len(k.keys)
+}
+
+func (k *KeyValueMetadata) Key(i int) string {
+	return k.keys[i]
+}
+
+func (k *KeyValueMetadata) Value(i int) string {
+	return k.values[i]
+}
+
+// nil without any pair, leaving the thrift field unset
+func (k *KeyValueMetadata) ToThrift() []*thrift.KeyValue {
+	if k == nil || len(k.keys) == 0 {
+		return nil
+	}
+	pairs := make([]*thrift.KeyValue, len(k.keys))
+	for i := range k.keys {
+		value := k.values[i]
+		pairs[i] = &thrift.KeyValue{Key: k.keys[i], Value: &value}
+	}
+	return pairs
+}
+
+// -----------------------------------------------------------------
+// ColumnChunkMetaDataBuilder
+
+// Fills the thrift ColumnChunk of a column of a row group. The locations of
+// the Bloom filter and page indexes, written after the row groups, can be set
+// once it is finished.
+type ColumnChunkMetaDataBuilder struct {
+	properties  *column.WriterProperties
+	column      *_schema.ColumnDescriptor
+	columnChunk *thrift.ColumnChunk
+	finished    bool
+}
+
+func NewColumnChunkMetaDataBuilder(properties *column.WriterProperties,
+	column *_schema.ColumnDescriptor, column_chunk *thrift.ColumnChunk) *ColumnChunkMetaDataBuilder {
+	metadata := thrift.NewColumnMetaData()
+	metadata.Type = thrift.Type(column.PhysicalType())
+	metadata.PathInSchema = column.Path().Path
+	metadata.Codec = properties.Compression(column.Path()).ToThrift()
+	column_chunk.MetaData = metadata
+	return &ColumnChunkMetaDataBuilder{
+		properties:  properties,
+		column:      column,
+		columnChunk: column_chunk,
+	}
+}
+
+func (c *ColumnChunkMetaDataBuilder) Descr() *_schema.ColumnDescriptor {
+	return c.column
+}
+
+func (c *ColumnChunkMetaDataBuilder) Finished() bool {
+	return c.finished
+}
+
+func (c *ColumnChunkMetaDataBuilder) SetStatistics(statistics *column.EncodedStatistics) {
+	c.columnChunk.MetaData.Statistics = statistics.ToThrift()
+}
+
+func (c *ColumnChunkMetaDataBuilder) SetKeyValueMetadata(metadata *KeyValueMetadata) {
+	c.columnChunk.MetaData.KeyValueMetadata = metadata.ToThrift()
+}
+
+func (c *ColumnChunkMetaDataBuilder) SetColumnIndexLocation(offset int64, length int32) {
+	c.columnChunk.ColumnIndexOffset = &offset
+	c.columnChunk.ColumnIndexLength = &length
+}
+
+func (c *ColumnChunkMetaDataBuilder) SetOffsetIndexLocation(offset int64, length int32) {
+	c.columnChunk.OffsetIndexOffset = &offset
+	c.columnChunk.OffsetIndexLength = &length
+}
+
+func (c *ColumnChunkMetaDataBuilder) SetBloomFilterLocation(offset int64, length int32) {
+	c.columnChunk.MetaData.BloomFilterOffset = &offset
+	c.columnChunk.MetaData.BloomFilterLength = &length
+}
+
+// Record the pages written. The encoding stats count the pages of each
+// encoding, nil maps leave them out. An index_page_offset of 0 means no
+// index page.
+func (c *ColumnChunkMetaDataBuilder) Finish(num_values int64, dictionary_page_offset int64,
+	index_page_offset int64, data_page_offset int64, compressed_size int64,
+	uncompressed_size int64, has_dictionary bool, dictionary_fallback bool,
+	dict_encoding_stats map[ptype.Encoding]int32, data_encoding_stats map[ptype.Encoding]int32) {
+	metadata := c.columnChunk.MetaData
+	chunk_start := data_page_offset
+	if has_dictionary {
+		metadata.DictionaryPageOffset = &dictionary_page_offset
+		chunk_start = dictionary_page_offset
+	}
+	if index_page_offset > 0 {
+		metadata.IndexPageOffset = &index_page_offset
+	}
+	c.columnChunk.FileOffset = chunk_start
+	metadata.NumValues = num_values
+	metadata.DataPageOffset = data_page_offset
+	metadata.TotalCompressedSize = compressed_size
+	metadata.TotalUncompressedSize = uncompressed_size
+
+	// The levels are RLE encoded
+	encodings := []ptype.Encoding{ptype.Encoding_RLE}
+	if has_dictionary {
+		encodings = append(encodings, c.properties.DictionaryIndexEncoding(),
+			c.properties.DictionaryPageEncoding())
+	} else {
+		encodings = append(encodings, c.properties.Encoding(c.column.Path()))
+	}
+	// Only PLAIN encoding is supported for fallback in V1
+	if dictionary_fallback {
+		encodings = append(encodings, ptype.Encoding_PLAIN)
+	}
+	metadata.Encodings = nil
+	seen := make(map[ptype.Encoding]bool)
+	for _, encoding := range encodings {
+		if !seen[encoding] {
+			seen[encoding] = true
+			metadata.Encodings = append(metadata.Encodings, encoding.ToThrift())
+		}
+	}
+
+	metadata.EncodingStats = nil
+	addEncodingStats := func(page_type thrift.PageType, stats map[ptype.Encoding]int32) {
+		encodings := make([]ptype.Encoding, 0, len(stats))
+		for encoding := range stats {
+			encodings = append(encodings, encoding)
+		}
+		sort.Slice(encodings, func(i, j int) bool { return encodings[i] < encodings[j] })
+		for _, encoding := range encodings {
+			entry := thrift.NewPageEncodingStats()
+			entry.PageType = page_type
+			entry.Encoding = encoding.ToThrift()
+			entry.Count = stats[encoding]
+			metadata.EncodingStats = append(metadata.EncodingStats, entry)
+		}
+	}
+	addEncodingStats(thrift.PageType_DICTIONARY_PAGE, dict_encoding_stats)
+	addEncodingStats(thrift.PageType_DATA_PAGE, data_encoding_stats)
+	c.finished = true
+}
+
+// Copy the metadata of a column chunk of another file, whose pages moved by
+// delta bytes, under the path of this column. The locations of its page
+// index and Bloom filter are left out.
+func (c *ColumnChunkMetaDataBuilder) FinishCopy(source *ColumnChunkMetaData, delta int64) {
+	metadata := *source.metadata
+	metadata.PathInSchema = c.column.Path().Path
+	metadata.DataPageOffset += delta
+	if source.HasDictionaryPage() {
+		dictionary_page_offset := source.DictionaryPageOffset() + delta
+		metadata.DictionaryPageOffset = &dictionary_page_offset
+	}
+	if source.HasIndexPage() {
+		index_page_offset := source.IndexPageOffset() + delta
+		metadata.IndexPageOffset = &index_page_offset
+	}
+	metadata.BloomFilterOffset = nil
+	metadata.BloomFilterLength = nil
+	c.columnChunk.MetaData = &metadata
+	c.columnChunk.FileOffset = source.ChunkRange().Offset + delta
+	c.finished = true
+}
+
+// -----------------------------------------------------------------
+// RowGroupMetaDataBuilder
+
+type RowGroupMetaDataBuilder struct {
+	properties     *column.WriterProperties
+	schema         *_schema.SchemaDescriptor
+	rowGroup       *thrift.RowGroup
+	columnBuilders []*ColumnChunkMetaDataBuilder
+	sortingColumns []column.SortingColumn
+}
+
+func NewRowGroupMetaDataBuilder(properties *column.WriterProperties,
+	schema *_schema.SchemaDescriptor, row_group *thrift.RowGroup) *RowGroupMetaDataBuilder {
+	row_group.Columns = make([]*thrift.ColumnChunk, schema.NumColumns())
+	return &RowGroupMetaDataBuilder{
+		properties:     properties,
+		schema:         schema,
+		rowGroup:       row_group,
+		sortingColumns: properties.SortingColumns(),
+	}
+}
+
+func (r *RowGroupMetaDataBuilder) NumColumns() int {
+	return r.schema.NumColumns()
+}
+
+// Number of columns started
+func (r *RowGroupMetaDataBuilder) CurrentColumn() int {
+	return len(r.columnBuilders)
+}
+
+func (r *RowGroupMetaDataBuilder) NumRows() int64 {
+	return r.rowGroup.NumRows
+}
+
+// The sorting columns of the properties by default
+func (r *RowGroupMetaDataBuilder) SetSortingColumns(sorting_columns []column.SortingColumn) {
+	r.sortingColumns = sorting_columns
+}
+
+// The rows of a buffered row group are only known once written
+func (r *RowGroupMetaDataBuilder) SetNumRows(num_rows int64) {
+	r.rowGroup.NumRows = num_rows
+}
+
+// Throws an error if all the columns were started
+func (r *RowGroupMetaDataBuilder) NextColumnChunnk() *ColumnChunkMetaDataBuilder {
+	i := len(r.columnBuilders)
+	if i >= r.NumColumns() {
+		panic(fmt.Errorf("The schema only has %d columns, requested metadata for column: %d",
+			r.NumColumns(), i))
+	}
+	column_chunk := thrift.NewColumnChunk()
+	r.rowGroup.Columns[i] = column_chunk
+	builder := NewColumnChunkMetaDataBuilder(r.properties, r.schema.Column(i), column_chunk)
+	r.columnBuilders = append(r.columnBuilders, builder)
+	return builder
+}
+
+// Throws an error if a column was not written
+func (r *RowGroupMetaDataBuilder) Finish(total_bytes_written int64) {
+	if len(r.columnBuilders) != r.NumColumns() {
+		panic(fmt.Errorf("Only %d out of %d columns are initialized",
+			len(r.columnBuilders), r.NumColumns()))
+	}
+	for _, builder := range r.columnBuilders {
+		if !builder.Finished() {
+			panic(fmt.Errorf("Column %s was not finished",
+				builder.Descr().Path().ToDotString()))
+		}
+	}
+	r.rowGroup.TotalByteSize = total_bytes_written
+	if len(r.sortingColumns) > 0 {
+		r.rowGroup.SortingColumns = make([]*thrift.SortingColumn, len(r.sortingColumns))
+		for i, sorting := range r.sortingColumns {
+			r.rowGroup.SortingColumns[i] = &thrift.SortingColumn{
+				ColumnIdx:  int32(sorting.ColumnIdx),
+				Descending: sorting.Descending,
+				NullsFirst: sorting.NullsFirst,
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------
+// FileMetaDataBuilder
+
+type FileMetaDataBuilder struct {
+	properties       *column.WriterProperties
+	schema           *_schema.SchemaDescriptor
+	metadata         *thrift.FileMetaData
+	keyValueMetadata *KeyValueMetadata
+}
+
+func NewFileMetaDataBuilderMake(schema *_schema.SchemaDescriptor,
+	properties *column.WriterProperties) *FileMetaDataBuilder {
+	for _, sorting := range properties.SortingColumns() {
+		if sorting.ColumnIdx < 0 || sorting.ColumnIdx >= schema.NumColumns() {
+			panic(fmt.Errorf("The schema only has %d columns, requested sorting by column: %d",
+				schema.NumColumns(), sorting.ColumnIdx))
+		}
+	}
+	return &FileMetaDataBuilder{
+		properties: properties,
+		schema:     schema,
+		metadata:   thrift.NewFileMetaData(),
+	}
+}
+
+// num_rows is the number of rows expected, a buffered row group sets it
+// once written
+func (f *FileMetaDataBuilder) AppendRowGroup(num_rows int64) *RowGroupMetaDataBuilder {
+	row_group := thrift.NewRowGroup()
+	row_group.NumRows = num_rows
+	f.metadata.RowGroups = append(f.metadata.RowGroups, row_group)
+	return NewRowGroupMetaDataBuilder(f.properties, f.schema, row_group)
+}
+
+func (f *FileMetaDataBuilder) SetKeyValueMetadata(metadata *KeyValueMetadata) {
+	f.keyValueMetadata = metadata
+}
+
+func (f *FileMetaDataBuilder) Finish() *FileMetaData {
+	f.metadata.NumRows = 0
+	for _, row_group := range f.metadata.RowGroups {
+		f.metadata.NumRows += row_group.NumRows
+	}
+	f.metadata.Version = 1
+	f.metadata.Schema = _schema.ToParquet(f.schema.GroupNode())
+	created_by := f.properties.CreatedBy()
+	f.metadata.CreatedBy = &created_by
+	f.metadata.KeyValueMetadata = f.keyValueMetadata.ToThrift()
+	if f.metadata.RowGroups == nil {
+		f.metadata.RowGroups = []*thrift.RowGroup{}
+	}
+	// Min / max are written in the order of the column type
+	f.metadata.ColumnOrders = make([]*thrift.ColumnOrder, f.schema.NumColumns())
+	for i := range f.metadata.ColumnOrders {
+		f.metadata.ColumnOrders[i] = &thrift.ColumnOrder{TYPE_ORDER: thrift.NewTypeDefinedOrder()}
+	}
+	return &FileMetaData{metadata: f.metadata, schema: f.schema}
+}
+
+// -----------------------------------------------------------------
+// FileMetaData
+
+// The footer of a file
+type FileMetaData struct {
+	metadata *thrift.FileMetaData
+	schema   *_schema.SchemaDescriptor
+}
+
+// Serialize the thrift FileMetaData, without the footer length and magic
+func (f *FileMetaData) WriteTo(w io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+	thrift.SerializeTriftMsg(f.metadata, 0, &buffer)
+	n, err := w.Write(buffer.Bytes())
+	return int64(n), err
+}
+
+// Read the footer serialized by WriteTo, panics if it is corrupted
+func (f *FileMetaData) ReadFrom(r io.Reader) (int64, error) {
+	var buffer bytes.Buffer
+	n, err := buffer.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	*f = *NewFileMetaDataFromBuffer(buffer.Bytes())
+	return n, nil
+}
+
+func NewFileMetaData(metadata *thrift.FileMetaData) *FileMetaData {
+	return &FileMetaData{metadata: metadata, schema: _schema.FromParquet(metadata.Schema)}
+}
+
+// Deserialize a thrift FileMetaData, panics if it is corrupted
+func NewFileMetaDataFromBuffer(buffer []byte) *FileMetaData {
+	metadata := thrift.NewFileMetaData()
+	thrift.DeserializeThriftMsg(buffer, len(buffer), metadata)
+	return NewFileMetaData(metadata)
+}
+
+func (f *FileMetaData) Version() int32 {
+	return f.metadata.Version
+}
+
+func (f *FileMetaData) CreatedBy() string {
+	return f.metadata.GetCreatedBy()
+}
+
+func (f *FileMetaData) NumRows() int64 {
+	return f.metadata.NumRows
+}
+
+func (f *FileMetaData) NumRowGroups() int {
+	return len(f.metadata.RowGroups)
+}
+
+func (f *FileMetaData) NumColumns() int {
+	return f.schema.NumColumns()
+}
+
+func (f *FileMetaData) Schema() *_schema.SchemaDescriptor {
+	return f.schema
+}
+
+// Empty without any pair
+func (f *FileMetaData) KeyValueMetadata() *KeyValueMetadata {
+	return KeyValueMetadataFromThrift(f.metadata.KeyValueMetadata)
+}
+
+func (f *FileMetaData) RowGroup(i int) *RowGroupMetaData {
+	if i < 0 || i >= f.NumRowGroups() {
+		panic(fmt.Errorf("The file only has %d row groups, requested metadata for row group: %d",
+			f.NumRowGroups(), i))
+	}
+	return NewRowGroupMetaData(f.metadata.RowGroups[i], f.schema)
+}
+
+// -----------------------------------------------------------------
+// RowGroupMetaData
+
+type RowGroupMetaData struct {
+	rowGroup *thrift.RowGroup
+	schema   *_schema.SchemaDescriptor
+}
+
+func NewRowGroupMetaData(row_group *thrift.RowGroup,
+	schema *_schema.SchemaDescriptor) *RowGroupMetaData {
+	return &RowGroupMetaData{rowGroup: row_group, schema: schema}
+}
+
+func (r *RowGroupMetaData) NumColumns() int {
+	return len(r.rowGroup.Columns)
+}
+
+func (r *RowGroupMetaData) NumRows() int64 {
+	return r.rowGroup.NumRows
+}
+
+func (r *RowGroupMetaData) TotalByteSize() int64 {
+	return r.rowGroup.TotalByteSize
+}
+
+// Sum of the compressed sizes of the column chunks
+func (r *RowGroupMetaData) TotalCompressedSize() int64 {
+	size := int64(0)
+	for i := 0; i < r.NumColumns(); i++ {
+		size += r.ColumnChunk(i).TotalCompressedSize()
+	}
+	return size
+}
+
+// The columns the rows are sorted by, the first one most significantly.
+// Empty if the writer did not declare any.
+func (r *RowGroupMetaData) SortingColumns() []column.SortingColumn {
+	sorting_columns := make([]column.SortingColumn, len(r.rowGroup.SortingColumns))
+	for i, sorting := range r.rowGroup.SortingColumns {
+		sorting_columns[i] = column.SortingColumn{
+			ColumnIdx:  int(sorting.ColumnIdx),
+			Descending: sorting.Descending,
+			NullsFirst: sorting.NullsFirst,
+		}
+	}
+	return sorting_columns
+}
+
+func (r *RowGroupMetaData) Schema() *_schema.SchemaDescriptor {
+	return r.schema
+}
+
+func (r *RowGroupMetaData) ColumnChunk(i int) *ColumnChunkMetaData {
+	if i < 0 || i >= r.NumColumns() {
+		panic(fmt.Errorf("The row group only has %d columns, requested metadata for column: %d",
+			r.NumColumns(), i))
+	}
+	return NewColumnChunkMetaData(r.rowGroup.Columns[i], r.schema.Column(i))
+}
+
+// -----------------------------------------------------------------
+// ColumnChunkMetaData
+
+type ColumnChunkMetaData struct {
+	columnChunk *thrift.ColumnChunk
+	metadata    *thrift.ColumnMetaData
+	descr       *_schema.ColumnDescriptor
+}
+
+// Panics if the chunk has no metadata, which this reader does not look for
+// in other files
+func NewColumnChunkMetaData(column_chunk *thrift.ColumnChunk,
+	descr *_schema.ColumnDescriptor) *ColumnChunkMetaData {
+	if column_chunk.MetaData == nil {
+		panic(fmt.Errorf("Column %s has no metadata", descr.Path().ToDotString()))
+	}
+	return &ColumnChunkMetaData{
+		columnChunk: column_chunk,
+		metadata:    column_chunk.MetaData,
+		descr:       descr,
+	}
+}
+
+func (c *ColumnChunkMetaData) Descr() *_schema.ColumnDescriptor {
+	return c.descr
+}
+
+func (c *ColumnChunkMetaData) FilePath() string {
+	return c.columnChunk.GetFilePath()
+}
+
+func (c *ColumnChunkMetaData) FileOffset() int64 {
+	return c.columnChunk.FileOffset
+}
+
+func (c *ColumnChunkMetaData) PathInSchema() *_schema.ColumnPath {
+	return _schema.NewColumnPath(c.metadata.PathInSchema)
+}
+
+func (c *ColumnChunkMetaData) Type() ptype.Type {
+	return ptype.Type(c.metadata.Type)
+}
+
+func (c *ColumnChunkMetaData) Codec() ptype.Compression {
+	return ptype.Compression(c.metadata.Codec)
+}
+
+func (c *ColumnChunkMetaData) Encodings() []ptype.Encoding {
+	encodings := make([]ptype.Encoding, len(c.metadata.Encodings))
+	for i, encoding := range c.metadata.Encodings {
+		encodings[i] = ptype.Encoding(encoding)
+	}
+	return encodings
+}
+
+func (c *ColumnChunkMetaData) NumValues() int64 {
+	return c.metadata.NumValues
+}
+
+func (c *ColumnChunkMetaData) HasDictionaryPage() bool {
+	return c.metadata.IsSetDictionaryPageOffset() && c.metadata.GetDictionaryPageOffset() > 0
+}
+
+func (c *ColumnChunkMetaData) DictionaryPageOffset() int64 {
+	return c.metadata.GetDictionaryPageOffset()
+}
+
+func (c *ColumnChunkMetaData) DataPageOffset() int64 {
+	return c.metadata.DataPageOffset
+}
+
+func (c *ColumnChunkMetaData) HasIndexPage() bool {
+	return c.metadata.IsSetIndexPageOffset()
+}
+
+func (c *ColumnChunkMetaData) IndexPageOffset() int64 {
+	return c.metadata.GetIndexPageOffset()
+}
+
+func (c *ColumnChunkMetaData) TotalCompressedSize() int64 {
+	return c.metadata.TotalCompressedSize
+}
+
+func (c *ColumnChunkMetaData) TotalUncompressedSize() int64 {
+	return c.metadata.TotalUncompressedSize
+}
+
+// The bytes of the chunk, from its first page header
+func (c *ColumnChunkMetaData) ChunkRange() ByteRange {
+	start := c.DataPageOffset()
+	if c.HasDictionaryPage() && c.DictionaryPageOffset() < start {
+		start = c.DictionaryPageOffset()
+	}
+	return ByteRange{Offset: start, Length: c.TotalCompressedSize()}
+}
+
+func (c *ColumnChunkMetaData) IsStatsSet() bool {
+	return c.metadata.IsSetStatistics()
+}
+
+// nil if the writer did not store any
+func (c *ColumnChunkMetaData) EncodedStatistics() *column.EncodedStatistics {
+	if !c.IsStatsSet() {
+		return nil
+	}
+	return column.EncodedStatisticsFromThrift(c.metadata.Statistics)
+}
+
+// The statistics decoded for the column type, nil if the writer did not
+// store any
+func (c *ColumnChunkMetaData) Statistics() *column.Statistics {
+	if !c.IsStatsSet() {
+		return nil
+	}
+	return column.NewStatisticsFromEncoded(c.descr, c.EncodedStatistics(), c.NumValues())
+}
+
+// Empty without any pair
+func (c *ColumnChunkMetaData) KeyValueMetadata() *KeyValueMetadata {
+	return KeyValueMetadataFromThrift(c.metadata.KeyValueMetadata)
+}
+
+func (c *ColumnChunkMetaData) HasBloomFilter() bool {
+	return c.metadata.IsSetBloomFilterOffset()
+}
+
+func (c *ColumnChunkMetaData) BloomFilterOffset() int64 {
+	return c.metadata.GetBloomFilterOffset()
+}
+
+// Older writers only give the offset of the Bloom filter
+func (c *ColumnChunkMetaData) HasBloomFilterLength() bool {
+	return c.metadata.IsSetBloomFilterLength()
+}
+
+func (c *ColumnChunkMetaData) BloomFilterLength() int32 {
+	return c.metadata.GetBloomFilterLength()
+}
+
+// The location of the column index, false if it was not written
+func (c *ColumnChunkMetaData) ColumnIndexLocation() (int64, int32, bool) {
+	if !c.columnChunk.IsSetColumnIndexOffset() || !c.columnChunk.IsSetColumnIndexLength() {
+		return 0, 0, false
+	}
+	return c.columnChunk.GetColumnIndexOffset(), c.columnChunk.GetColumnIndexLength(), true
+}
+
+// The location of the offset index, false if it was not written
+func (c *ColumnChunkMetaData) OffsetIndexLocation() (int64, int32, bool) {
+	if !c.columnChunk.IsSetOffsetIndexOffset() || !c.columnChunk.IsSetOffsetIndexLength() {
+		return 0, 0, false
+	}
+	return c.columnChunk.GetOffsetIndexOffset(), c.columnChunk.GetOffsetIndexLength(), true
+}
+
+// The number of pages of a type and encoding in a column chunk
+type PageEncodingStats struct {
+	PageType thrift.PageType
+	Encoding ptype.Encoding
+	Count    int32
+}
+
+// Empty if the writer did not record them
+func (c *ColumnChunkMetaData) EncodingStats() []PageEncodingStats {
+	stats := make([]PageEncodingStats, len(c.metadata.EncodingStats))
+	for i, entry := range c.metadata.EncodingStats {
+		stats[i] = PageEncodingStats{
+			PageType: entry.PageType,
+			Encoding: ptype.Encoding(entry.Encoding),
+			Count:    entry.Count,
+		}
+	}
+	return stats
+}
+
+func isDictionaryEncoding(encoding ptype.Encoding) bool {
+	return encoding == ptype.Encoding_PLAIN_DICTIONARY ||
+		encoding == ptype.Encoding_RLE_DICTIONARY
+}
+
+// Whether all the data pages of the chunk are dictionary encoded, so its
+// dictionary holds all of its values. Without encoding stats, the encodings
+// only tell for files using PLAIN_DICTIONARY.
+func (c *ColumnChunkMetaData) IsFullyDictionaryEncoded() bool {
+	if !c.HasDictionaryPage() {
+		return false
+	}
+	if c.metadata.IsSetEncodingStats() {
+		for _, entry := range c.EncodingStats() {
+			if entry.PageType != thrift.PageType_DICTIONARY_PAGE && entry.Count > 0 &&
+				!isDictionaryEncoding(entry.Encoding) {
+				return false
+			}
+		}
+		return true
+	}
+	dictionary := false
+	for _, encoding := range c.Encodings() {
+		switch encoding {
+		case ptype.Encoding_PLAIN_DICTIONARY:
+			dictionary = true
+		case ptype.Encoding_RLE, ptype.Encoding_BIT_PACKED:
+			// Only used by the levels
+		default:
+			// RLE_DICTIONARY files may have fallen back to any encoding
+			return false
+		}
+	}
+	return dictionary
+}
@@ -0,0 +1,278 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+)
+
+func TestKeyValueMetadata(t *testing.T) {
+	metadata := NewKeyValueMetadata()
+	if metadata.ToThrift() != nil {
+		t.Errorf("empty metadata has thrift pairs")
+	}
+	metadata.Append("b", "1")
+	metadata.Append("a", "2")
+	metadata.Append("b", "3")
+	if metadata.Size() != 2 || metadata.Key(0) != "b" || metadata.Value(0) != "3" ||
+		metadata.Key(1) != "a" || metadata.FindKey("c") != -1 {
+		t.Errorf("metadata %v", metadata.ToThrift())
+	}
+	read := KeyValueMetadataFromThrift(metadata.ToThrift())
+	if value, ok := read.Get("a"); !ok || value != "2" || read.Size() != 2 {
+		t.Errorf("read a = %q, %v", value, ok)
+	}
+	if _, ok := read.Get("c"); ok {
+		t.Errorf("found a missing key")
+	}
+}
+
+const keyValueSchema = `message key_values {
+  required int32 a;
+  optional binary b;
+}
+`
+
+func TestKeyValueMetadataRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(keyValueSchema), nil)
+	writer.AddKeyValueMetadata("lineage", "a")
+	row_group := writer.AppendRowGroup(2)
+	row_group.NextColumn().WriteBatch(2, nil, nil, []int32{1, 2})
+	row_group.AddColumnKeyValueMetadata(0, "first", "0")
+	row_group.NextColumn().WriteBatch(2, []int16{1, 0}, nil, []ptype.ByteArray{ptype.ByteArray("x")})
+	row_group.AddColumnKeyValueMetadata(1, "second", "1")
+	row_group.AddColumnKeyValueMetadata(1, "second", "2")
+	row_group.Close()
+	writer.AddKeyValueMetadata("version", "2")
+	writer.AddKeyValueMetadata("lineage", "b")
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	footer := reader.KeyValueMetadata()
+	if footer.Size() != 2 || footer.Key(0) != "lineage" || footer.Value(0) != "b" ||
+		footer.Key(1) != "version" || footer.Value(1) != "2" {
+		t.Errorf("footer metadata %v", footer.ToThrift())
+	}
+	first := reader.RowGroup(0).ColumnKeyValueMetadata(0)
+	second := reader.RowGroup(0).ColumnKeyValueMetadata(1)
+	if value, _ := first.Get("first"); first.Size() != 1 || value != "0" {
+		t.Errorf("column 0 metadata %v", first.ToThrift())
+	}
+	if value, _ := second.Get("second"); second.Size() != 1 || value != "2" {
+		t.Errorf("column 1 metadata %v", second.ToThrift())
+	}
+}
+
+func builderPanic(build func()) (message string) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			message = fmt.Sprint(failure)
+		}
+	}()
+	build()
+	return ""
+}
+
+func TestMetaDataBuilders(t *testing.T) {
+	schema := _schema.NewSchemaDescriptor(&_schema.MustParse(keyValueSchema).Node)
+	properties := column.DefaultWriterProperties()
+	builder := NewFileMetaDataBuilderMake(schema, properties)
+	row_group := builder.AppendRowGroup(3)
+	first := row_group.NextColumnChunnk()
+	first.Finish(3, 0, 0, 4, 20, 30, false, false, nil,
+		map[ptype.Encoding]int32{ptype.Encoding_PLAIN: 2})
+	second := row_group.NextColumnChunnk()
+	if message := builderPanic(func() { row_group.Finish(50) }); message != "Column b was not finished" {
+		t.Errorf("unfinished column: %q", message)
+	}
+	second.Finish(3, 24, 0, 40, 25, 35, true, true,
+		map[ptype.Encoding]int32{ptype.Encoding_PLAIN: 1},
+		map[ptype.Encoding]int32{ptype.Encoding_PLAIN: 1, ptype.Encoding_RLE_DICTIONARY: 2})
+	if message := builderPanic(func() { row_group.NextColumnChunnk() }); message !=
+		"The schema only has 2 columns, requested metadata for column: 2" {
+		t.Errorf("extra column: %q", message)
+	}
+	row_group.Finish(45)
+	builder.AppendRowGroup(2).SetNumRows(4)
+	metadata := builder.Finish().metadata
+
+	if metadata.NumRows != 7 || len(metadata.RowGroups) != 2 || metadata.RowGroups[0].TotalByteSize != 45 {
+		t.Fatalf("file metadata rows %d, row groups %d", metadata.NumRows, len(metadata.RowGroups))
+	}
+	chunk := metadata.RowGroups[0].Columns[0]
+	if chunk.FileOffset != 4 || chunk.MetaData.DictionaryPageOffset != nil ||
+		chunk.MetaData.IndexPageOffset != nil || fmt.Sprint(chunk.MetaData.Encodings) != "[RLE PLAIN]" {
+		t.Errorf("column a offset %d, encodings %v", chunk.FileOffset, chunk.MetaData.Encodings)
+	}
+	chunk = metadata.RowGroups[0].Columns[1]
+	if chunk.FileOffset != 24 || *chunk.MetaData.DictionaryPageOffset != 24 ||
+		chunk.MetaData.DataPageOffset != 40 || chunk.MetaData.TotalCompressedSize != 25 {
+		t.Errorf("column b offsets %d, %d", chunk.FileOffset, chunk.MetaData.DataPageOffset)
+	}
+	if encodings := fmt.Sprint(chunk.MetaData.Encodings); encodings != "[RLE PLAIN_DICTIONARY PLAIN]" {
+		t.Errorf("column b encodings %s", encodings)
+	}
+	var stats []string
+	for _, entry := range chunk.MetaData.EncodingStats {
+		stats = append(stats, fmt.Sprintf("%v %v %d", entry.PageType, entry.Encoding, entry.Count))
+	}
+	if fmt.Sprint(stats) != "[DICTIONARY_PAGE PLAIN 1 DATA_PAGE PLAIN 1 DATA_PAGE RLE_DICTIONARY 2]" {
+		t.Errorf("column b encoding stats %v", stats)
+	}
+	if metadata.Schema[0].GetNumChildren() != 2 || metadata.CreatedBy == nil ||
+		metadata.RowGroups[1].NumRows != 4 {
+		t.Errorf("file metadata %v", metadata)
+	}
+}
+
+func TestRowGroupRowsWritten(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(keyValueSchema), nil)
+	row_group := writer.AppendRowGroup(2)
+	row_group.NextColumn().WriteBatch(1, nil, nil, []int32{1})
+	if message := builderPanic(func() { row_group.NextColumn() }); message != "Column a has 1 rows, expected 2" {
+		t.Errorf("short column: %q", message)
+	}
+}
+
+func TestMetaDataAccessors(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(keyValueSchema), nil)
+	writer.AddKeyValueMetadata("lineage", "a")
+	row_group := writer.AppendRowGroup(3)
+	row_group.NextColumn().WriteBatch(3, nil, nil, []int32{5, -1, 3})
+	row_group.NextColumn().WriteBatch(3, []int16{1, 1, 0}, nil,
+		[]ptype.ByteArray{ptype.ByteArray("x"), ptype.ByteArray("x")})
+	row_group.Close()
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	metadata := reader.Metadata()
+	if metadata.NumRows() != 3 || metadata.NumRowGroups() != 1 || metadata.NumColumns() != 2 ||
+		metadata.Version() != 1 || metadata.CreatedBy() == "" {
+		t.Errorf("file metadata rows %d, row groups %d, columns %d",
+			metadata.NumRows(), metadata.NumRowGroups(), metadata.NumColumns())
+	}
+	if value, _ := metadata.KeyValueMetadata().Get("lineage"); value != "a" {
+		t.Errorf("lineage %q", value)
+	}
+	rows := metadata.RowGroup(0)
+	if rows.NumRows() != 3 || rows.NumColumns() != 2 ||
+		rows.TotalCompressedSize() != rows.ColumnChunk(0).TotalCompressedSize()+rows.ColumnChunk(1).TotalCompressedSize() {
+		t.Errorf("row group rows %d, size %d", rows.NumRows(), rows.TotalCompressedSize())
+	}
+	a := rows.ColumnChunk(0)
+	if a.Type() != ptype.Type_INT32 || a.PathInSchema().ToDotString() != "a" || a.NumValues() != 3 ||
+		a.Codec() != ptype.Compression_UNCOMPRESSED || a.HasIndexPage() || !a.IsStatsSet() {
+		t.Errorf("column a type %v, path %s, values %d", a.Type(), a.PathInSchema().ToDotString(), a.NumValues())
+	}
+	statistics := a.Statistics()
+	if statistics == nil || statistics.Min() != int32(-1) || statistics.Max() != int32(5) {
+		t.Errorf("column a statistics %v", statistics)
+	}
+	b := rows.ColumnChunk(1)
+	if !b.HasDictionaryPage() || b.ChunkRange().Offset != b.DictionaryPageOffset() ||
+		b.ChunkRange().Length != b.TotalCompressedSize() || b.DataPageOffset() <= b.DictionaryPageOffset() {
+		t.Errorf("column b dictionary %d, data %d", b.DictionaryPageOffset(), b.DataPageOffset())
+	}
+	if b.Statistics().NullCount() != 1 || reader.RowGroup(0).ColumnStatistics(1).NullCount() != 1 {
+		t.Errorf("column b null count %d", b.Statistics().NullCount())
+	}
+	if message := builderPanic(func() { metadata.RowGroup(1) }); message !=
+		"The file only has 1 row groups, requested metadata for row group: 1" {
+		t.Errorf("missing row group: %q", message)
+	}
+	if message := builderPanic(func() { rows.ColumnChunk(2) }); message !=
+		"The row group only has 2 columns, requested metadata for column: 2" {
+		t.Errorf("missing column: %q", message)
+	}
+
+	var footer bytes.Buffer
+	metadata.WriteTo(&footer)
+	var read FileMetaData
+	if _, err := read.ReadFrom(&footer); err != nil {
+		t.Fatal(err)
+	}
+	if read.NumColumns() != 2 || read.Schema().Column(1).Path().ToDotString() != "b" || read.RowGroup(0).ColumnChunk(1).DataPageOffset() != b.DataPageOffset() {
+		t.Errorf("footer round trip")
+	}
+}
+
+func TestEncodingStats(t *testing.T) {
+	properties := column.NewWriterPropertiesBuilder().
+		DictionaryPagesizeLimit(64).
+		WriteBatchSize(16).
+		DataPagesize(64).
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(keyValueSchema), properties)
+	row_group := writer.AppendRowGroup(100)
+	a := make([]int32, 100)
+	for i := range a {
+		a[i] = int32(i)
+	}
+	row_group.NextColumn().WriteBatch(100, nil, nil, a)
+	b := make([]ptype.ByteArray, 100)
+	def_levels := make([]int16, 100)
+	for i := range b {
+		b[i] = ptype.ByteArray("x")
+		def_levels[i] = 1
+	}
+	row_group.NextColumn().WriteBatch(100, def_levels, nil, b)
+	row_group.Close()
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	fallback := reader.Metadata().RowGroup(0).ColumnChunk(0)
+	stats := fallback.EncodingStats()
+	if len(stats) != 3 || stats[0].PageType != thrift.PageType_DICTIONARY_PAGE ||
+		stats[1].Encoding != ptype.Encoding_PLAIN || stats[2].Encoding != ptype.Encoding_PLAIN_DICTIONARY {
+		t.Errorf("column a encoding stats %v", stats)
+	}
+	if fallback.IsFullyDictionaryEncoded() {
+		t.Errorf("column a fell back to PLAIN")
+	}
+	dictionary := reader.Metadata().RowGroup(0).ColumnChunk(1)
+	stats = dictionary.EncodingStats()
+	if len(stats) != 2 || stats[1].PageType != thrift.PageType_DATA_PAGE || stats[1].Count < 1 {
+		t.Errorf("column b encoding stats %v", stats)
+	}
+	if !dictionary.IsFullyDictionaryEncoded() {
+		t.Errorf("column b is dictionary encoded")
+	}
+}
+
+func TestFullyDictionaryEncodedWithoutStats(t *testing.T) {
+	schema := _schema.NewSchemaDescriptor(&_schema.MustParse(keyValueSchema).Node)
+	for _, test := range []struct {
+		encodings []ptype.Encoding
+		expected  bool
+	}{
+		{[]ptype.Encoding{ptype.Encoding_PLAIN_DICTIONARY, ptype.Encoding_RLE}, true},
+		{[]ptype.Encoding{ptype.Encoding_PLAIN_DICTIONARY, ptype.Encoding_RLE, ptype.Encoding_PLAIN}, false},
+		{[]ptype.Encoding{ptype.Encoding_RLE_DICTIONARY, ptype.Encoding_RLE}, false},
+		{[]ptype.Encoding{ptype.Encoding_RLE}, false},
+	} {
+		builder := NewFileMetaDataBuilderMake(schema, column.DefaultWriterProperties())
+		row_group := builder.AppendRowGroup(1)
+		row_group.NextColumnChunnk().Finish(1, 0, 0, 4, 10, 10, false, false, nil, nil)
+		row_group.NextColumnChunnk().Finish(1, 14, 0, 24, 20, 20, true, false, nil, nil)
+		row_group.Finish(30)
+		metadata := builder.Finish().metadata
+		encodings := make([]thrift.Encoding, len(test.encodings))
+		for i, encoding := range test.encodings {
+			encodings[i] = encoding.ToThrift()
+		}
+		metadata.RowGroups[0].Columns[1].MetaData.Encodings = encodings
+		chunk := NewFileMetaData(metadata).RowGroup(0).ColumnChunk(1)
+		if actual := chunk.IsFullyDictionaryEncoded(); actual != test.expected {
+			t.Errorf("%v: fully dictionary encoded %v", test.encodings, actual)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package file
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/filter"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"sort"
+	"unsafe"
+)
+
+// The page index of a column chunk is made of a ColumnIndex, holding the
+// min / max and null count of each data page, and an OffsetIndex, holding
+// the location and first row of each data page. Both are written after the
+// row groups, before the footer, and are referenced by the ColumnChunk.
+
+// Collects the statistics of the data pages of a column chunk
+type ColumnIndexBuilder struct {
+	descr *_schema.ColumnDescriptor
+	index *thrift.ColumnIndex
+	// Typed min / max of the non null pages, for the boundary order
+	mins []interface{}
+	maxs []interface{}
+	// Pages without the statistics needed invalidate the column index
+	valid bool
+}
+
+func NewColumnIndexBuilder(descr *_schema.ColumnDescriptor) *ColumnIndexBuilder {
+	return &ColumnIndexBuilder{
+		descr: descr,
+		index: thrift.NewColumnIndex(),
+		valid: true,
+	}
+}
+
+func (b *ColumnIndexBuilder) AddPage(statistics *column.EncodedStatistics, num_values int32) {
+	if !b.valid {
+		return
+	}
+	if !statistics.HasNullCount {
+		b.index.NullCounts = nil
+	} else if len(b.index.NullCounts) == len(b.index.NullPages) {
+		b.index.NullCounts = append(b.index.NullCounts, statistics.NullCount)
+	}
+	if statistics.HasNullCount && statistics.NullCount == int64(num_values) {
+		b.index.NullPages = append(b.index.NullPages, true)
+		b.index.MinValues = append(b.index.MinValues, []byte{})
+		b.index.MaxValues = append(b.index.MaxValues, []byte{})
+		return
+	}
+	typed := column.NewStatisticsFromEncoded(b.descr, statistics, int64(num_values))
+	if !typed.HasMinMax() {
+		b.valid = false
+		return
+	}
+	b.index.NullPages = append(b.index.NullPages, false)
+	b.index.MinValues = append(b.index.MinValues, statistics.Min())
+	b.index.MaxValues = append(b.index.MaxValues, statistics.Max())
+	b.mins = append(b.mins, typed.Min())
+	b.maxs = append(b.maxs, typed.Max())
+}
+
+// The column index, nil if a page had no statistics
+func (b *ColumnIndexBuilder) Build() *thrift.ColumnIndex {
+	if !b.valid || len(b.index.NullPages) == 0 {
+		return nil
+	}
+	if len(b.index.NullCounts) != len(b.index.NullPages) {
+		b.index.NullCounts = nil
+	}
+	b.index.BoundaryOrder = b.boundaryOrder()
+	return b.index
+}
+
+func (b *ColumnIndexBuilder) boundaryOrder() thrift.BoundaryOrder {
+	stats := column.NewStatistics(b.descr, false)
+	ascending, descending := true, true
+	for i := 1; i < len(b.mins); i++ {
+		to_min := stats.Compare(b.mins[i-1], b.mins[i])
+		to_max := stats.Compare(b.maxs[i-1], b.maxs[i])
+		if to_min > 0 || to_max > 0 {
+			ascending = false
+		}
+		if to_min < 0 || to_max < 0 {
+			descending = false
+		}
+	}
+	switch {
+	case ascending:
+		return thrift.BoundaryOrder_ASCENDING
+	case descending:
+		return thrift.BoundaryOrder_DESCENDING
+	}
+	return thrift.BoundaryOrder_UNORDERED
+}
+
+// Collects the locations of the data pages of a column chunk
+type OffsetIndexBuilder struct {
+	index   *thrift.OffsetIndex
+	numRows int64
+}
+
+func NewOffsetIndexBuilder() *OffsetIndexBuilder {
+	return &OffsetIndexBuilder{index: thrift.NewOffsetIndex()}
+}
+
+// A data page of num_rows rows, written at offset with its header
+func (b *OffsetIndexBuilder) AddPage(offset int64, compressed_page_size int32, num_rows int64) {
+	location := thrift.NewPageLocation()
+	location.Offset = offset
+	location.CompressedPageSize = compressed_page_size
+	location.FirstRowIndex = b.numRows
+	b.index.PageLocations = append(b.index.PageLocations, location)
+	b.numRows += num_rows
+}
+
+// Move the pages by offset bytes, for chunks written to a buffer first
+func (b *OffsetIndexBuilder) Shift(offset int64) {
+	for _, location := range b.index.PageLocations {
+		location.Offset += offset
+	}
+}
+
+func (b *OffsetIndexBuilder) Build() *thrift.OffsetIndex {
+	return b.index
+}
+
+type pageIndexEntry struct {
+	metadata     *ColumnChunkMetaDataBuilder
+	column_index *thrift.ColumnIndex
+	offset_index *thrift.OffsetIndex
+}
+
+type bloomFilterEntry struct {
+	metadata     *ColumnChunkMetaDataBuilder
+	bloom_filter *bloom.BlockSplitBloomFilter
+}
+
+// Holds the Bloom filters and page indexes of the column chunks written
+// until they are serialized before the footer
+type PageIndexWriter struct {
+	bloomFilters []bloomFilterEntry
+	entries      []pageIndexEntry
+}
+
+func NewPageIndexWriter() *PageIndexWriter {
+	return &PageIndexWriter{}
+}
+
+// column_index may be nil, the column chunk then only gets an offset index
+func (p *PageIndexWriter) AddColumnChunk(metadata *ColumnChunkMetaDataBuilder,
+	column_index *thrift.ColumnIndex, offset_index *thrift.OffsetIndex) {
+	p.entries = append(p.entries, pageIndexEntry{metadata, column_index, offset_index})
+}
+
+func (p *PageIndexWriter) AddBloomFilter(metadata *ColumnChunkMetaDataBuilder,
+	bloom_filter *bloom.BlockSplitBloomFilter) {
+	p.bloomFilters = append(p.bloomFilters, bloomFilterEntry{metadata, bloom_filter})
+}
+
+// Write all the Bloom filters, then all the column indexes, then all the
+// offset indexes, and record their location in the column chunk metadata
+func (p *PageIndexWriter) WriteTo(sink OutputStream) {
+	for _, entry := range p.bloomFilters {
+		start_pos := sink.Tell()
+		entry.bloom_filter.WriteTo(sink)
+		entry.metadata.SetBloomFilterLocation(start_pos, int32(sink.Tell()-start_pos))
+	}
+	for _, entry := range p.entries {
+		if entry.column_index == nil {
+			continue
+		}
+		start_pos := sink.Tell()
+		thrift.SerializeTriftMsg(entry.column_index, int(unsafe.Sizeof(*entry.column_index)), sink)
+		entry.metadata.SetColumnIndexLocation(start_pos, int32(sink.Tell()-start_pos))
+	}
+	for _, entry := range p.entries {
+		start_pos := sink.Tell()
+		thrift.SerializeTriftMsg(entry.offset_index, int(unsafe.Sizeof(*entry.offset_index)), sink)
+		entry.metadata.SetOffsetIndexLocation(start_pos, int32(sink.Tell()-start_pos))
+	}
+	p.bloomFilters = nil
+	p.entries = nil
+}
+
+// The rows [Start, End) of a row group
+type RowRange struct {
+	Start int64
+	End   int64
+}
+
+// The rows of each data page of a column chunk
+func pageRowRanges(offset_index *thrift.OffsetIndex, num_rows int64) []RowRange {
+	locations := offset_index.PageLocations
+	ranges := make([]RowRange, len(locations))
+	for i, location := range locations {
+		ranges[i].Start = location.FirstRowIndex
+		ranges[i].End = num_rows
+		if i+1 < len(locations) {
+			ranges[i].End = locations[i+1].FirstRowIndex
+		}
+	}
+	return ranges
+}
+
+// The statistics of the pages holding a range of rows, the range never
+// spans more than one page of a column
+type pageRangeStatistics struct {
+	rowGroup      *RowGroupReader
+	columnIndexes []*thrift.ColumnIndex
+	pageRows      [][]RowRange
+	rows          RowRange
+}
+
+func (p *pageRangeStatistics) Schema() *_schema.SchemaDescriptor {
+	return p.rowGroup.Schema()
+}
+
+func (p *pageRangeStatistics) NumRows() int64 {
+	return p.rows.End - p.rows.Start
+}
+
+func (p *pageRangeStatistics) ColumnStatistics(i int) *column.Statistics {
+	index := p.columnIndexes[i]
+	if index == nil {
+		return nil
+	}
+	pages := p.pageRows[i]
+	page := sort.Search(len(pages), func(j int) bool {
+		return pages[j].End > p.rows.Start
+	})
+	page_rows := pages[page].End - pages[page].Start
+	descr := p.rowGroup.Schema().Column(i)
+	encoded := &column.EncodedStatistics{}
+	num_values := int64(0)
+	if index.IsSetNullCounts() {
+		encoded.SetNullCount(index.NullCounts[page])
+		num_values = index.NullCounts[page]
+	}
+	if index.NullPages[page] {
+		if !index.IsSetNullCounts() {
+			encoded.SetNullCount(page_rows)
+			num_values = page_rows
+		}
+	} else {
+		encoded.SetMin(index.MinValues[page]).SetMax(index.MaxValues[page])
+		if descr.PhysicalType() == ptype.Type_BYTE_ARRAY ||
+			descr.PhysicalType() == ptype.Type_FIXED_LEN_BYTE_ARRAY {
+			// Byte arrays may have been truncated to bounds
+			encoded.SetExact(false, false)
+		}
+		// Only tells the page has non null values
+		num_values += page_rows
+	}
+	return column.NewStatisticsFromEncoded(descr, encoded, num_values)
+}
+
+// The ranges of rows of the row group that may match the predicate, in
+// order, found with the page indexes of its columns. Without a predicate
+// or page indexes, the whole row group.
+func (r *RowGroupReader) CandidateRowRanges(predicate filter.Predicate) []RowRange {
+	num_rows := r.NumRows()
+	if num_rows == 0 {
+		return nil
+	}
+	if predicate == nil {
+		return []RowRange{{0, num_rows}}
+	}
+	num_columns := r.NumColumns()
+	statistics := &pageRangeStatistics{
+		rowGroup:      r,
+		columnIndexes: make([]*thrift.ColumnIndex, num_columns),
+		pageRows:      make([][]RowRange, num_columns),
+	}
+	// The ranges are cut at the first row of every page
+	starts := map[int64]bool{0: true}
+	for i := 0; i < num_columns; i++ {
+		column_index := r.ColumnIndex(i)
+		if column_index == nil {
+			continue
+		}
+		offset_index := r.OffsetIndex(i)
+		if offset_index == nil ||
+			len(offset_index.PageLocations) != len(column_index.NullPages) {
+			continue
+		}
+		statistics.columnIndexes[i] = column_index
+		statistics.pageRows[i] = pageRowRanges(offset_index, num_rows)
+		for _, location := range offset_index.PageLocations {
+			starts[location.FirstRowIndex] = true
+		}
+	}
+	boundaries := make([]int64, 0, len(starts)+1)
+	for start := range starts {
+		boundaries = append(boundaries, start)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+	boundaries = append(boundaries, num_rows)
+
+	var ranges []RowRange
+	for i := 0; i+1 < len(boundaries); i++ {
+		statistics.rows = RowRange{boundaries[i], boundaries[i+1]}
+		if !filter.CanMatch(predicate, statistics) {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End == statistics.rows.Start {
+			ranges[n-1].End = statistics.rows.End
+		} else {
+			ranges = append(ranges, statistics.rows)
+		}
+	}
+	return ranges
+}
+
+// A reader of column i over only its data pages holding rows of the
+// ranges, and the rows of those pages. Panics if the column has no offset
+// index.
+func (r *RowGroupReader) ColumnRanges(i int, ranges []RowRange) (*column.ColumnReader, []RowRange) {
+	offset_index := r.OffsetIndex(i)
+	if offset_index == nil {
+		panic(fmt.Errorf("Column %d has no offset index", i))
+	}
+	var pages []int
+	var page_rows []RowRange
+	j := 0
+	for page, rows := range pageRowRanges(offset_index, r.NumRows()) {
+		for j < len(ranges) && ranges[j].End <= rows.Start {
+			j++
+		}
+		if j < len(ranges) && ranges[j].Start < rows.End {
+			pages = append(pages, page)
+			page_rows = append(page_rows, rows)
+		}
+	}
+	descr := r.Contents.Schema().Column(i)
+	return column.NewColumnReader(descr, r.Contents.GetColumnPagesReader(i, pages)), page_rows
+}
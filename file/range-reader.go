@@ -0,0 +1,172 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Sources with a high latency per read, like object storage, are read by
+// planning the byte ranges of the column chunks needed, coalescing the
+// ranges close to each other and fetching them concurrently.
+
+const (
+	DEFAULT_MAX_GAP        = 8 * 1024
+	DEFAULT_MAX_RANGE_SIZE = 32 * 1024 * 1024
+	DEFAULT_CONCURRENCY    = 8
+)
+
+// The bytes [Offset, Offset + Length) of a source
+type ByteRange struct {
+	Offset int64
+	Length int64
+}
+
+func (r ByteRange) End() int64 {
+	return r.Offset + r.Length
+}
+
+// A source read by byte ranges. Implementations must be safe for
+// concurrent use. Sources that are only an io.ReaderAt are read with
+// ReadAt.
+type RangeReader interface {
+	ReadRange(offset int64, length int64) ([]byte, error)
+}
+
+type ReadOptions struct {
+	// Ranges separated by at most MaxGap bytes are read at once
+	MaxGap int64
+	// Ranges are not coalesced past MaxRangeSize bytes
+	MaxRangeSize int64
+	// Number of ranges read concurrently
+	Concurrency int
+}
+
+func DefaultReadOptions() *ReadOptions {
+	return &ReadOptions{
+		MaxGap:       DEFAULT_MAX_GAP,
+		MaxRangeSize: DEFAULT_MAX_RANGE_SIZE,
+		Concurrency:  DEFAULT_CONCURRENCY,
+	}
+}
+
+// Merge the overlapping ranges and those separated by at most maxGap bytes,
+// as long as the merged range is at most maxRangeSize bytes. Returns the
+// ranges sorted by offset.
+func CoalesceRanges(ranges []ByteRange, maxGap int64, maxRangeSize int64) []ByteRange {
+	sorted := make([]ByteRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Length > 0 {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	var coalesced []ByteRange
+	for _, r := range sorted {
+		if n := len(coalesced); n > 0 {
+			last := &coalesced[n-1]
+			end := r.End()
+			if end < last.End() {
+				end = last.End()
+			}
+			if r.Offset < last.End() ||
+				r.Offset-last.End() <= maxGap && end-last.Offset <= maxRangeSize {
+				last.Length = end - last.Offset
+				continue
+			}
+		}
+		coalesced = append(coalesced, r)
+	}
+	return coalesced
+}
+
+type readerAtRanges struct {
+	source io.ReaderAt
+}
+
+func (r *readerAtRanges) ReadRange(offset int64, length int64) ([]byte, error) {
+	buffer := make([]byte, length)
+	n, err := r.source.ReadAt(buffer, offset)
+	if n == len(buffer) {
+		err = nil
+	}
+	return buffer[:n], err
+}
+
+// The source as a RangeReader
+func NewRangeReader(source io.ReaderAt) RangeReader {
+	if ranges, ok := source.(RangeReader); ok {
+		return ranges
+	}
+	return &readerAtRanges{source: source}
+}
+
+// Read the ranges with up to concurrency reads at a time, panics on the
+// first error
+func FetchRanges(source RangeReader, ranges []ByteRange, concurrency int) [][]byte {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	buffers := make([][]byte, len(ranges))
+	errors := make([]error, len(ranges))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(ranges); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				buffers[i], errors[i] = source.ReadRange(ranges[i].Offset, ranges[i].Length)
+				if errors[i] == nil && int64(len(buffers[i])) != ranges[i].Length {
+					errors[i] = io.ErrUnexpectedEOF
+				}
+			}
+		}()
+	}
+	for i := range ranges {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for i, err := range errors {
+		if err != nil {
+			panic(fmt.Errorf("Could not read %d bytes at offset %d: %v", ranges[i].Length,
+				ranges[i].Offset, err))
+		}
+	}
+	return buffers
+}
+
+// Serves the reads within the ranges fetched up front from memory, and the
+// others from the source
+type PrefetchedSource struct {
+	source  io.ReaderAt
+	ranges  []ByteRange
+	buffers [][]byte
+}
+
+// Coalesce the ranges and fetch them concurrently as options tell, nil
+// options are the defaults
+func NewPrefetchedSource(source io.ReaderAt, ranges []ByteRange,
+	options *ReadOptions) *PrefetchedSource {
+	if options == nil {
+		options = DefaultReadOptions()
+	}
+	coalesced := CoalesceRanges(ranges, options.MaxGap, options.MaxRangeSize)
+	return &PrefetchedSource{
+		source:  source,
+		ranges:  coalesced,
+		buffers: FetchRanges(NewRangeReader(source), coalesced, options.Concurrency),
+	}
+}
+
+func (p *PrefetchedSource) ReadAt(buffer []byte, offset int64) (int, error) {
+	i := sort.Search(len(p.ranges), func(i int) bool { return p.ranges[i].End() > offset })
+	if i < len(p.ranges) && p.ranges[i].Offset <= offset &&
+		offset+int64(len(buffer)) <= p.ranges[i].End() {
+		start := offset - p.ranges[i].Offset
+		return copy(buffer, p.buffers[i][start:]), nil
+	}
+	return p.source.ReadAt(buffer, offset)
+}
@@ -0,0 +1,117 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoalesceRanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		ranges       []ByteRange
+		maxGap       int64
+		maxRangeSize int64
+		expected     []ByteRange
+	}{
+		{"empty", nil, 10, 100, nil},
+		{"gap within limit", []ByteRange{{0, 10}, {15, 10}}, 5, 100, []ByteRange{{0, 25}}},
+		{"gap over limit", []ByteRange{{0, 10}, {15, 10}}, 4, 100,
+			[]ByteRange{{0, 10}, {15, 10}}},
+		{"adjacent", []ByteRange{{0, 10}, {10, 10}}, 0, 100, []ByteRange{{0, 20}}},
+		{"max size", []ByteRange{{0, 10}, {10, 10}, {20, 10}}, 0, 20,
+			[]ByteRange{{0, 20}, {20, 10}}},
+		{"gap counts in max size", []ByteRange{{0, 10}, {15, 10}}, 5, 24,
+			[]ByteRange{{0, 10}, {15, 10}}},
+		{"contained", []ByteRange{{0, 30}, {10, 5}}, 0, 10, []ByteRange{{0, 30}}},
+		// Overlapping ranges are merged past the max size
+		{"overlapping", []ByteRange{{0, 20}, {10, 20}}, 0, 10, []ByteRange{{0, 30}}},
+		{"unsorted", []ByteRange{{50, 5}, {0, 5}, {20, 5}}, 0, 100,
+			[]ByteRange{{0, 5}, {20, 5}, {50, 5}}},
+		{"empty ranges dropped", []ByteRange{{0, 5}, {5, 0}, {40, 0}}, 0, 100,
+			[]ByteRange{{0, 5}}},
+	}
+	for _, test := range tests {
+		coalesced := CoalesceRanges(test.ranges, test.maxGap, test.maxRangeSize)
+		if !reflect.DeepEqual(coalesced, test.expected) {
+			t.Errorf("%s: %v, want %v", test.name, coalesced, test.expected)
+		}
+	}
+}
+
+// Fails the reads at an offset, and shortens those at another
+type faultyRanges struct {
+	data        []byte
+	failOffset  int64
+	shortOffset int64
+}
+
+func (f *faultyRanges) ReadRange(offset int64, length int64) ([]byte, error) {
+	switch offset {
+	case f.failOffset:
+		return nil, errors.New("read failed")
+	case f.shortOffset:
+		length--
+	}
+	return f.data[offset : offset+length], nil
+}
+
+func fetchPanic(source RangeReader, ranges []ByteRange) (message string) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			message = fmt.Sprint(failure)
+		}
+	}()
+	FetchRanges(source, ranges, 3)
+	return ""
+}
+
+func TestFetchRanges(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	ranges := []ByteRange{{0, 10}, {100, 50}, {300, 1}, {500, 200}, {990, 10}}
+	source := &faultyRanges{data: data, failOffset: -1, shortOffset: -1}
+	buffers := FetchRanges(source, ranges, 3)
+	for i, r := range ranges {
+		if !bytes.Equal(buffers[i], data[r.Offset:r.End()]) {
+			t.Errorf("range %v read %v", r, buffers[i])
+		}
+	}
+
+	source.failOffset = 300
+	if message := fetchPanic(source, ranges); !strings.Contains(message, "read failed") ||
+		!strings.Contains(message, "offset 300") {
+		t.Errorf("failed read: got panic %q", message)
+	}
+	source.failOffset, source.shortOffset = -1, 500
+	if message := fetchPanic(source, ranges); !strings.Contains(message, io.ErrUnexpectedEOF.Error()) ||
+		!strings.Contains(message, "offset 500") {
+		t.Errorf("short read: got panic %q", message)
+	}
+}
+
+func TestPrefetchedSource(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	source := NewPrefetchedSource(bytes.NewReader(data), []ByteRange{{100, 50}, {160, 40}},
+		&ReadOptions{MaxGap: 10, MaxRangeSize: 1000, Concurrency: 2})
+	if !reflect.DeepEqual(source.ranges, []ByteRange{{100, 100}}) {
+		t.Errorf("ranges %v", source.ranges)
+	}
+	// Within the prefetched range, and across its end
+	for _, r := range []ByteRange{{120, 60}, {190, 20}, {0, 10}} {
+		buffer := make([]byte, r.Length)
+		if n, err := source.ReadAt(buffer, r.Offset); n != len(buffer) || err != nil ||
+			!bytes.Equal(buffer, data[r.Offset:r.End()]) {
+			t.Errorf("read %v: %d, %v", r, n, err)
+		}
+	}
+}
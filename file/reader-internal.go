@@ -0,0 +1,370 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"io"
+	"math"
+)
+
+// 16 MB is the default maximum page header size
+const DEFAULT_MAX_PAGE_HEADER_SIZE = 16 * 1024 * 1024
+
+const FOOTER_SIZE = 8
+
+// This subclass delimits pages appearing in a serialized stream, each preceded
+// by a serialized Thrift format::PageHeader indicating the type of each page
+// and the page metadata.
+type SerializedPageReader struct {
+	column.PageReader
+	// The column chunk, from the first page header to the end of the last page
+	Stream []byte
+	// Compression codec to use.
+	Decompressor *Codec
+	// Number of values of the column chunk, pages past it are not read
+	TotalNumValues    int64
+	SeenNumValues     int64
+	MaxPageHeaderSize int
+}
+
+func (s *SerializedPageReader) NextPage() column.ColumnPage {
+	// Loop here because there may be unhandled page types that we skip until
+	// finding a page that we do know what to do with
+	for s.SeenNumValues < s.TotalNumValues && len(s.Stream) > 0 {
+		page_header, header_size := readPageHeader(s.Stream, s.MaxPageHeaderSize)
+		s.Stream = s.Stream[header_size:]
+
+		compressed_len := int(page_header.CompressedPageSize)
+		uncompressed_len := int(page_header.UncompressedPageSize)
+		if compressed_len < 0 || compressed_len > len(s.Stream) {
+			panic(fmt.Errorf("Page was smaller (%d) than expected (%d)",
+				len(s.Stream), compressed_len))
+		}
+		page_buffer := s.Stream[:compressed_len]
+		s.Stream = s.Stream[compressed_len:]
+
+		// Uncompress it if we need to
+		if s.Decompressor != nil {
+			page_buffer = s.Decompressor.Decompress(page_buffer, uncompressed_len)
+		}
+
+		switch page_header.Type {
+		case thrift.PageType_DICTIONARY_PAGE:
+			dict_header := page_header.GetDictionaryPageHeader()
+			if dict_header == nil {
+				panic(fmt.Errorf("Dictionary page header is missing"))
+			}
+			return column.NewDictionaryPage(bytes.NewBuffer(page_buffer),
+				dict_header.NumValues, ptype.Encoding(dict_header.Encoding),
+				dict_header.GetIsSorted())
+		case thrift.PageType_DATA_PAGE:
+			data_header := page_header.GetDataPageHeader()
+			if data_header == nil {
+				panic(fmt.Errorf("Data page header is missing"))
+			}
+			s.SeenNumValues += int64(data_header.NumValues)
+			var page_statistics column.EncodedStatistics
+			if data_header.IsSetStatistics() {
+				page_statistics = *column.EncodedStatisticsFromThrift(data_header.Statistics)
+			}
+			return column.NewDataPage(bytes.NewBuffer(page_buffer), data_header.NumValues,
+				ptype.Encoding(data_header.Encoding),
+				ptype.Encoding(data_header.DefinitionLevelEncoding),
+				ptype.Encoding(data_header.RepetitionLevelEncoding), page_statistics)
+		case thrift.PageType_DATA_PAGE_V2:
+			panic(fmt.Errorf("DATA_PAGE_V2 pages are not supported"))
+		default:
+			// We don't know what this page type is. We're allowed to skip
+			// non-data pages.
+			continue
+		}
+	}
+	return nil
+}
+
+// Deserialize the page header at the start of stream, and return its size
+func readPageHeader(stream []byte, max_header_size int) (*thrift.PageHeader, int) {
+	page_header := thrift.NewPageHeader()
+	header_len := len(stream)
+	if header_len > max_header_size {
+		header_len = max_header_size
+	}
+	remaining := thrift.DeserializeThriftMsg(stream[:header_len], header_len, page_header)
+	return page_header, header_len - int(remaining)
+}
+
+// The headers of the pages of a column chunk, up to the page holding the
+// last of its total_num_values values
+func ReadPageHeaders(stream []byte, total_num_values int64) []*thrift.PageHeader {
+	var headers []*thrift.PageHeader
+	seen_num_values := int64(0)
+	for seen_num_values < total_num_values && len(stream) > 0 {
+		page_header, header_size := readPageHeader(stream, DEFAULT_MAX_PAGE_HEADER_SIZE)
+		compressed_len := int(page_header.CompressedPageSize)
+		if compressed_len < 0 || header_size+compressed_len > len(stream) {
+			panic(fmt.Errorf("Page was smaller (%d) than expected (%d)",
+				len(stream)-header_size, compressed_len))
+		}
+		stream = stream[header_size+compressed_len:]
+		if data_header := page_header.GetDataPageHeader(); data_header != nil {
+			seen_num_values += int64(data_header.NumValues)
+		}
+		if data_header := page_header.GetDataPageHeaderV2(); data_header != nil {
+			seen_num_values += int64(data_header.NumValues)
+		}
+		headers = append(headers, page_header)
+	}
+	return headers
+}
+
+func NewSerializedPageReader(stream []byte, total_num_values int64,
+	codec ptype.Compression) *SerializedPageReader {
+	return &SerializedPageReader{
+		Stream:            stream,
+		Decompressor:      NewCodec(codec),
+		TotalNumValues:    total_num_values,
+		MaxPageHeaderSize: DEFAULT_MAX_PAGE_HEADER_SIZE,
+	}
+}
+
+type SerializedRowGroup struct {
+	RowGroupReaderContents
+	Source   io.ReaderAt
+	Metadata *RowGroupMetaData
+}
+
+func (r *SerializedRowGroup) NumColumns() int {
+	return r.Metadata.NumColumns()
+}
+
+func (r *SerializedRowGroup) NumRows() int64 {
+	return r.Metadata.NumRows()
+}
+
+func (r *SerializedRowGroup) Schema() *_schema.SchemaDescriptor {
+	return r.Metadata.Schema()
+}
+
+func (r *SerializedRowGroup) RowGroupMetaData() *RowGroupMetaData {
+	return r.Metadata
+}
+
+func (r *SerializedRowGroup) GetColumnPageReader(i int) column.PageReader {
+	col := r.Metadata.ColumnChunk(i)
+	return NewSerializedPageReader(r.GetColumnChunk(i), col.NumValues(), col.Codec())
+}
+
+// The pages of column i as they are stored
+func (r *SerializedRowGroup) GetColumnChunk(i int) []byte {
+	chunk := r.Metadata.ColumnChunk(i).ChunkRange()
+	col_start, col_length := chunk.Offset, chunk.Length
+
+	stream := make([]byte, col_length)
+	if _, err := r.Source.ReadAt(stream, col_start); err != nil {
+		panic(fmt.Errorf("Could not read column chunk %d at offset %d: %v", i,
+			col_start, err))
+	}
+	return stream
+}
+
+// A page reader over the dictionary page, if any, and the data pages of
+// column i at the given positions of its offset index
+func (r *SerializedRowGroup) GetColumnPagesReader(i int, pages []int) column.PageReader {
+	col := r.Metadata.ColumnChunk(i)
+	offset_index := r.GetOffsetIndex(i)
+	if offset_index == nil {
+		panic(fmt.Errorf("Column %d has no offset index", i))
+	}
+	var stream []byte
+	read := func(start int64, length int64) {
+		buffer := make([]byte, length)
+		if _, err := r.Source.ReadAt(buffer, start); err != nil {
+			panic(fmt.Errorf("Could not read pages of column %d at offset %d: %v", i,
+				start, err))
+		}
+		stream = append(stream, buffer...)
+	}
+	if col.HasDictionaryPage() && col.DictionaryPageOffset() < col.DataPageOffset() {
+		read(col.DictionaryPageOffset(), col.DataPageOffset()-col.DictionaryPageOffset())
+	}
+	// Pages next to each other are read at once
+	for j := 0; j < len(pages); {
+		location := offset_index.PageLocations[pages[j]]
+		start := location.Offset
+		end := start + int64(location.CompressedPageSize)
+		for j++; j < len(pages) && offset_index.PageLocations[pages[j]].Offset == end; j++ {
+			end += int64(offset_index.PageLocations[pages[j]].CompressedPageSize)
+		}
+		read(start, end-start)
+	}
+	// The stream ends after the selected pages, whatever their values
+	return NewSerializedPageReader(stream, math.MaxInt64, col.Codec())
+}
+
+// The dictionary page of column i read on its own, nil if it has none
+func (r *SerializedRowGroup) GetDictionaryPage(i int) *column.DictionaryPage {
+	col := r.Metadata.ColumnChunk(i)
+	if !col.HasDictionaryPage() || col.DictionaryPageOffset() >= col.DataPageOffset() {
+		return nil
+	}
+	offset := col.DictionaryPageOffset()
+	stream := make([]byte, col.DataPageOffset()-offset)
+	if _, err := r.Source.ReadAt(stream, offset); err != nil {
+		panic(fmt.Errorf("Could not read the dictionary page of column %d at offset %d: %v",
+			i, offset, err))
+	}
+	page, ok := NewSerializedPageReader(stream, math.MaxInt64, col.Codec()).NextPage().(*column.DictionaryPage)
+	if !ok {
+		panic(fmt.Errorf("No dictionary page in column %d at offset %d", i, offset))
+	}
+	return page
+}
+
+// The column index of column i, nil if it was not written
+func (r *SerializedRowGroup) GetColumnIndex(i int) *thrift.ColumnIndex {
+	offset, length, ok := r.Metadata.ColumnChunk(i).ColumnIndexLocation()
+	if !ok {
+		return nil
+	}
+	index := thrift.NewColumnIndex()
+	r.readIndex(offset, length, index)
+	return index
+}
+
+// The offset index of column i, nil if it was not written
+func (r *SerializedRowGroup) GetOffsetIndex(i int) *thrift.OffsetIndex {
+	offset, length, ok := r.Metadata.ColumnChunk(i).OffsetIndexLocation()
+	if !ok {
+		return nil
+	}
+	index := thrift.NewOffsetIndex()
+	r.readIndex(offset, length, index)
+	return index
+}
+
+// The Bloom filter of column i, nil if it was not written
+func (r *SerializedRowGroup) GetBloomFilter(i int) *bloom.BlockSplitBloomFilter {
+	col := r.Metadata.ColumnChunk(i)
+	if !col.HasBloomFilter() {
+		return nil
+	}
+	offset := col.BloomFilterOffset()
+	length := int64(col.BloomFilterLength())
+	if !col.HasBloomFilterLength() {
+		// Older writers only give the offset, the header tells the size
+		header := make([]byte, bloom.HEADER_SIZE_GUESS)
+		n, err := r.Source.ReadAt(header, offset)
+		if n == 0 && err != nil {
+			panic(fmt.Errorf("Could not read the Bloom filter at offset %d: %v", offset, err))
+		}
+		header_size, num_bytes := bloom.ReadHeader(header[:n])
+		length = int64(header_size + num_bytes)
+	}
+	buffer := make([]byte, length)
+	if _, err := r.Source.ReadAt(buffer, offset); err != nil {
+		panic(fmt.Errorf("Could not read the Bloom filter at offset %d: %v", offset, err))
+	}
+	return bloom.NewBlockSplitBloomFilterFromBuffer(buffer)
+}
+
+func (r *SerializedRowGroup) readIndex(offset int64, length int32, index thrift.T) {
+	buffer := make([]byte, length)
+	if _, err := r.Source.ReadAt(buffer, offset); err != nil {
+		panic(fmt.Errorf("Could not read the page index at offset %d: %v", offset, err))
+	}
+	thrift.DeserializeThriftMsg(buffer, int(length), index)
+}
+
+func NewSerializedRowGroup(source io.ReaderAt, metadata *RowGroupMetaData) *SerializedRowGroup {
+	return &SerializedRowGroup{
+		Source:   source,
+		Metadata: metadata,
+	}
+}
+
+// This class is only required for the reader. It holds the footer metadata
+// and creates the row group readers.
+type SerializedFile struct {
+	ParquetFileReaderContents
+	Source   io.ReaderAt
+	Size     int64
+	Metadata *FileMetaData
+}
+
+func (f *SerializedFile) Close() {
+	if closer, ok := f.Source.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (f *SerializedFile) GetRowGroup(i int) *RowGroupReader {
+	return NewRowGroupReader(NewSerializedRowGroup(f.Source, f.Metadata.RowGroup(i)))
+}
+
+// A reader of row group i whose chunks of the columns were fetched up
+// front, coalescing their ranges as options tell
+func (f *SerializedFile) PrefetchRowGroup(i int, columns []int,
+	options *ReadOptions) *RowGroupReader {
+	row_group := NewSerializedRowGroup(f.Source, f.Metadata.RowGroup(i))
+	ranges := make([]ByteRange, len(columns))
+	for j, column := range columns {
+		ranges[j] = row_group.Metadata.ColumnChunk(column).ChunkRange()
+	}
+	row_group.Source = NewPrefetchedSource(f.Source, ranges, options)
+	return NewRowGroupReader(row_group)
+}
+
+func (f *SerializedFile) NumRows() int64 {
+	return f.Metadata.NumRows()
+}
+
+func (f *SerializedFile) NumRowGroups() int {
+	return f.Metadata.NumRowGroups()
+}
+
+func (f *SerializedFile) Schema() *_schema.SchemaDescriptor {
+	return f.Metadata.Schema()
+}
+
+func (f *SerializedFile) FileMetaData() *FileMetaData {
+	return f.Metadata
+}
+
+func (f *SerializedFile) ParseMetaData() {
+	if f.Size < FOOTER_SIZE+int64(len(PARQUET_MAGIC)) {
+		panic(fmt.Errorf("Corrupted file, smaller than file header"))
+	}
+
+	footer_buffer := make([]byte, FOOTER_SIZE)
+	if _, err := f.Source.ReadAt(footer_buffer, f.Size-FOOTER_SIZE); err != nil {
+		panic(fmt.Errorf("Could not read the file footer: %v", err))
+	}
+	if !bytes.Equal(footer_buffer[4:], PARQUET_MAGIC) {
+		panic(fmt.Errorf("Invalid parquet file. Corrupt footer."))
+	}
+
+	metadata_len := int64(binary.LittleEndian.Uint32(footer_buffer))
+	metadata_start := f.Size - FOOTER_SIZE - metadata_len
+	if metadata_start < int64(len(PARQUET_MAGIC)) {
+		panic(fmt.Errorf("Invalid parquet file. File is less than file metadata size."))
+	}
+
+	metadata_buffer := make([]byte, metadata_len)
+	if _, err := f.Source.ReadAt(metadata_buffer, metadata_start); err != nil {
+		panic(fmt.Errorf("Could not read the file metadata: %v", err))
+	}
+	f.Metadata = NewFileMetaDataFromBuffer(metadata_buffer)
+}
+
+// Open the file. If no exception is thrown, returns a valid SerializedFile
+func NewSerializedFile(source io.ReaderAt, size int64) *SerializedFile {
+	f := &SerializedFile{Source: source, Size: size}
+	f.ParseMetaData()
+	return f
+}
@@ -0,0 +1,296 @@
+package file
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/filter"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"github.com/zenixls2/goparquet/thrift"
+	"io"
+	"os"
+)
+
+type RowGroupReaderContents interface {
+	NumColumns() int
+	NumRows() int64
+	Schema() *_schema.SchemaDescriptor
+	GetColumnPageReader(i int) column.PageReader
+	GetColumnChunk(i int) []byte
+	RowGroupMetaData() *RowGroupMetaData
+	GetColumnPagesReader(i int, pages []int) column.PageReader
+	GetColumnIndex(i int) *thrift.ColumnIndex
+	GetOffsetIndex(i int) *thrift.OffsetIndex
+	GetBloomFilter(i int) *bloom.BlockSplitBloomFilter
+	GetDictionaryPage(i int) *column.DictionaryPage
+}
+
+type RowGroupReader struct {
+	Contents RowGroupReaderContents
+}
+
+func (r *RowGroupReader) NumColumns() int {
+	return r.Contents.NumColumns()
+}
+
+func (r *RowGroupReader) NumRows() int64 {
+	return r.Contents.NumRows()
+}
+
+func (r *RowGroupReader) Schema() *_schema.SchemaDescriptor {
+	return r.Contents.Schema()
+}
+
+// Construct a ColumnReader for the indicated row group-relative
+// column. Ownership is shared with the RowGroupReader.
+func (r *RowGroupReader) Column(i int) *column.ColumnReader {
+	descr := r.Contents.Schema().Column(i)
+	return column.NewColumnReader(descr, r.Contents.GetColumnPageReader(i))
+}
+
+func (r *RowGroupReader) GetColumnPageReader(i int) column.PageReader {
+	return r.Contents.GetColumnPageReader(i)
+}
+
+// The pages of column i as they are stored in the file, compressed
+func (r *RowGroupReader) ReadColumnChunk(i int) []byte {
+	return r.Contents.GetColumnChunk(i)
+}
+
+// The headers of the pages of column i, in order
+func (r *RowGroupReader) PageHeaders(i int) []*thrift.PageHeader {
+	return ReadPageHeaders(r.Contents.GetColumnChunk(i),
+		r.Contents.RowGroupMetaData().ColumnChunk(i).NumValues())
+}
+
+// The statistics of a column chunk decoded for its type, nil if the writer
+// did not store any
+func (r *RowGroupReader) ColumnStatistics(i int) *column.Statistics {
+	return r.Contents.RowGroupMetaData().ColumnChunk(i).Statistics()
+}
+
+func (r *RowGroupReader) Metadata() *RowGroupMetaData {
+	return r.Contents.RowGroupMetaData()
+}
+
+// The column index of column i, nil if the writer did not store one
+func (r *RowGroupReader) ColumnIndex(i int) *thrift.ColumnIndex {
+	return r.Contents.GetColumnIndex(i)
+}
+
+// The offset index of column i, nil if the writer did not store one
+func (r *RowGroupReader) OffsetIndex(i int) *thrift.OffsetIndex {
+	return r.Contents.GetOffsetIndex(i)
+}
+
+// The Bloom filter of column i, nil if the writer did not store one
+func (r *RowGroupReader) ColumnBloomFilter(i int) *bloom.BlockSplitBloomFilter {
+	return r.Contents.GetBloomFilter(i)
+}
+
+// The values of the dictionary page of column i, in a slice of the column
+// type, nil if it has none. Only the dictionary page is read.
+func (r *RowGroupReader) ReadDictionary(i int) interface{} {
+	page := r.Contents.GetDictionaryPage(i)
+	if page == nil {
+		return nil
+	}
+	return column.DecodeDictionaryPage(r.Contents.Schema().Column(i), page)
+}
+
+// The columns the rows are sorted by, empty if the writer did not declare
+// any
+func (r *RowGroupReader) SortingColumns() []column.SortingColumn {
+	return r.Contents.RowGroupMetaData().SortingColumns()
+}
+
+// The key / value metadata of column chunk i, empty without any
+func (r *RowGroupReader) ColumnKeyValueMetadata(i int) *KeyValueMetadata {
+	return r.Contents.RowGroupMetaData().ColumnChunk(i).KeyValueMetadata()
+}
+
+func NewRowGroupReader(contents RowGroupReaderContents) *RowGroupReader {
+	return &RowGroupReader{Contents: contents}
+}
+
+// A row group whose fully dictionary encoded column chunks are also ruled
+// out by equality predicates on their dictionary
+type dictionaryFilterSource struct {
+	*RowGroupReader
+}
+
+func (d dictionaryFilterSource) ColumnDictionary(i int) interface{} {
+	if !d.Metadata().ColumnChunk(i).IsFullyDictionaryEncoded() {
+		return nil
+	}
+	return d.ReadDictionary(i)
+}
+
+type ParquetFileReaderContents interface {
+	Close()
+	GetRowGroup(i int) *RowGroupReader
+	PrefetchRowGroup(i int, columns []int, options *ReadOptions) *RowGroupReader
+	NumRows() int64
+	NumRowGroups() int
+	Schema() *_schema.SchemaDescriptor
+	FileMetaData() *FileMetaData
+}
+
+type ParquetFileReader struct {
+	Contents ParquetFileReaderContents
+	// Row groups the filter rules out are skipped, nil reads all of them
+	Filter filter.Predicate
+	// The columns the row level readers read, nil for all of them
+	Projection *_schema.Projection
+	// How the column chunks of a row group are fetched, nil for the defaults
+	ReadOptions *ReadOptions
+	// Whether the filter also reads the dictionary pages of the fully
+	// dictionary encoded column chunks
+	DictionaryFilter bool
+}
+
+// Open a parquet file from an existing source of the given size
+func NewParquetFileReaderOpen(source io.ReaderAt, size int64) *ParquetFileReader {
+	return &ParquetFileReader{Contents: NewSerializedFile(source, size)}
+}
+
+// API convenience to open a serialized Parquet file on disk
+func OpenFile(path string) *ParquetFileReader {
+	file, err := os.Open(path)
+	if err != nil {
+		panic(fmt.Errorf("Could not open %s: %v", path, err))
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		panic(fmt.Errorf("Could not stat %s: %v", path, err))
+	}
+	return NewParquetFileReaderOpen(file, info.Size())
+}
+
+func (p *ParquetFileReader) Close() {
+	if p.Contents != nil {
+		p.Contents.Close()
+		p.Contents = nil
+	}
+}
+
+// The RowGroupReader is owned by the FileReader
+func (p *ParquetFileReader) RowGroup(i int) *RowGroupReader {
+	if i < 0 || i >= p.NumRowGroups() {
+		panic(fmt.Errorf("The file only has %d row groups, requested reader for: %d",
+			p.NumRowGroups(), i))
+	}
+	return p.Contents.GetRowGroup(i)
+}
+
+// A reader of row group i whose chunks of the columns are read at once,
+// with the ranges close to each other coalesced and fetched concurrently
+func (p *ParquetFileReader) PrefetchRowGroup(i int, columns []int) *RowGroupReader {
+	if i < 0 || i >= p.NumRowGroups() {
+		panic(fmt.Errorf("The file only has %d row groups, requested reader for: %d",
+			p.NumRowGroups(), i))
+	}
+	return p.Contents.PrefetchRowGroup(i, columns, p.ReadOptions)
+}
+
+func (p *ParquetFileReader) SetReadOptions(options *ReadOptions) {
+	p.ReadOptions = options
+}
+
+// Only read the row groups that may hold rows matching the predicate, nil
+// reads all of them. The rows of the row groups read are not filtered.
+func (p *ParquetFileReader) SetFilter(predicate filter.Predicate) {
+	if predicate != nil {
+		predicate.Validate(p.Schema())
+	}
+	p.Filter = predicate
+}
+
+// Also rule out the row groups whose column chunks are fully dictionary
+// encoded without any value an eq, in or neq predicate of the filter
+// accepts. The dictionary page of the column chunks is read, before any
+// data page.
+func (p *ParquetFileReader) SetDictionaryFilter(enabled bool) {
+	p.DictionaryFilter = enabled
+}
+
+// Whether the statistics of row group i, and its dictionaries with
+// DictionaryFilter, allow rows matching the filter
+func (p *ParquetFileReader) RowGroupMatches(i int) bool {
+	if p.Filter == nil {
+		return true
+	}
+	if p.DictionaryFilter {
+		return filter.CanMatch(p.Filter, dictionaryFilterSource{p.RowGroup(i)})
+	}
+	return filter.CanMatch(p.Filter, p.RowGroup(i))
+}
+
+// The row groups that may hold rows matching the filter, all of them
+// without a filter
+func (p *ParquetFileReader) CandidateRowGroups() []int {
+	var candidates []int
+	for i := 0; i < p.NumRowGroups(); i++ {
+		if p.RowGroupMatches(i) {
+			candidates = append(candidates, i)
+		}
+	}
+	return candidates
+}
+
+// Only read the columns at the paths, or under them for groups
+func (p *ParquetFileReader) SetProjection(paths ...*_schema.ColumnPath) {
+	p.Projection = _schema.ProjectColumns(p.Schema(), paths)
+}
+
+// Only read the columns of a pruned copy of the file schema
+func (p *ParquetFileReader) SetProjectionSchema(node *_schema.GroupNode) {
+	p.Projection = _schema.ProjectSchema(p.Schema(), node)
+}
+
+// The schema of the projected columns, the file schema without a projection
+func (p *ParquetFileReader) ProjectedSchema() *_schema.SchemaDescriptor {
+	if p.Projection == nil {
+		return p.Schema()
+	}
+	return p.Projection.Schema()
+}
+
+// The file column of column i of the projected schema
+func (p *ParquetFileReader) ProjectedColumn(i int) int {
+	if p.Projection == nil {
+		return i
+	}
+	return p.Projection.Column(i)
+}
+
+func (p *ParquetFileReader) NumRows() int64 {
+	return p.Contents.NumRows()
+}
+
+func (p *ParquetFileReader) NumRowGroups() int {
+	return p.Contents.NumRowGroups()
+}
+
+func (p *ParquetFileReader) NumColumns() int {
+	return p.Contents.Schema().NumColumns()
+}
+
+func (p *ParquetFileReader) Schema() *_schema.SchemaDescriptor {
+	return p.Contents.Schema()
+}
+
+func (p *ParquetFileReader) Descr(i int) *_schema.ColumnDescriptor {
+	return p.Contents.Schema().Column(i)
+}
+
+// The footer metadata of the file
+func (p *ParquetFileReader) Metadata() *FileMetaData {
+	return p.Contents.FileMetaData()
+}
+
+// The key / value metadata of the footer, empty without any
+func (p *ParquetFileReader) KeyValueMetadata() *KeyValueMetadata {
+	return p.Metadata().KeyValueMetadata()
+}
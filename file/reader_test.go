@@ -0,0 +1,76 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/filter"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+const dictionarySchema = `message dictionaries {
+  required int32 a;
+  required binary b (UTF8);
+}
+`
+
+// Row groups of the values of b, without statistics so that only the
+// dictionaries rule them out
+func writeDictionaryFile(row_groups ...[]string) *ParquetFileReader {
+	properties := column.NewWriterPropertiesBuilder().DisableStatistics().Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(dictionarySchema), properties)
+	for _, values := range row_groups {
+		a := make([]int32, len(values))
+		b := make([]ptype.ByteArray, len(values))
+		for i, value := range values {
+			a[i] = int32(i)
+			b[i] = ptype.ByteArray(value)
+		}
+		row_group := writer.AppendRowGroup(int64(len(values)))
+		row_group.NextColumn().WriteBatch(int64(len(values)), nil, nil, a)
+		row_group.NextColumn().WriteBatch(int64(len(values)), nil, nil, b)
+		row_group.Close()
+	}
+	writer.Close()
+	return NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+}
+
+func TestReadDictionary(t *testing.T) {
+	reader := writeDictionaryFile([]string{"x", "y", "x"})
+	if dictionary := fmt.Sprintf("%s", reader.RowGroup(0).ReadDictionary(1)); dictionary != "[x y]" {
+		t.Errorf("dictionary %s", dictionary)
+	}
+	if dictionary := reader.RowGroup(0).ReadDictionary(0).([]int32); len(dictionary) != 3 {
+		t.Errorf("dictionary %v", dictionary)
+	}
+}
+
+func TestDictionaryFilter(t *testing.T) {
+	reader := writeDictionaryFile([]string{"x", "y", "x"}, []string{"z"}, []string{"x", "z"})
+	tests := []struct {
+		predicate  filter.Predicate
+		candidates string
+	}{
+		{filter.Eq("b", "z"), "[1 2]"},
+		{filter.Eq("b", "w"), "[]"},
+		{filter.Neq("b", "z"), "[0 2]"},
+		{filter.In("b", "y", "w"), "[0]"},
+		{filter.And(filter.Eq("b", "x"), filter.Eq("a", 2)), "[0]"},
+		{filter.Lt("b", "y"), "[0 1 2]"},
+	}
+	for _, test := range tests {
+		reader.SetFilter(test.predicate)
+		reader.SetDictionaryFilter(false)
+		if candidates := fmt.Sprint(reader.CandidateRowGroups()); candidates != "[0 1 2]" {
+			t.Errorf("%v without the dictionaries: %s", test.predicate, candidates)
+		}
+		reader.SetDictionaryFilter(true)
+		if candidates := fmt.Sprint(reader.CandidateRowGroups()); candidates != test.candidates {
+			t.Errorf("%v: %s, want %s", test.predicate, candidates, test.candidates)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package file
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	_schema "github.com/zenixls2/goparquet/schema"
+	"io"
+)
+
+// What Rewrite does with a column
+type ColumnAction int
+
+const (
+	// Copy the column chunks as they are
+	ColumnAction_KEEP ColumnAction = 0
+	ColumnAction_DROP ColumnAction = 1
+	// Decode the values and encode them again with the properties of the
+	// column, e.g. to change its codec or encoding
+	ColumnAction_REWRITE ColumnAction = 2
+	// Replace all the values by nulls, a required column becomes optional
+	ColumnAction_MASK ColumnAction = 3
+)
+
+// How Rewrite changes a column
+type ColumnRewrite struct {
+	Action ColumnAction
+	// The new name of the leaf, empty keeps it
+	Name string
+}
+
+// Write the row groups of source to sink with its columns changed by plan,
+// by dot path. The columns not in the plan are kept. Kept columns, renamed
+// or not, are copied as they are with their page indexes and Bloom filters,
+// only the rewritten and masked ones are decoded and written with the
+// properties of their new path. The key / value metadata of the footer and
+// of the column chunks is kept, as are the sorting columns up to the first
+// dropped or masked one. A nil properties uses DefaultWriterProperties.
+func Rewrite(sink io.Writer, source *ParquetFileReader, plan map[string]ColumnRewrite,
+	properties *column.WriterProperties) {
+	descr := source.Schema()
+	actions := make([]ColumnAction, descr.NumColumns())
+	rewrites := make(map[int]_schema.LeafRewrite)
+	for path, rewrite := range plan {
+		i := descr.ColumnIndex(path)
+		if i < 0 {
+			panic(fmt.Errorf("Column %s is not in the schema", path))
+		}
+		actions[i] = rewrite.Action
+		rewrites[i] = _schema.LeafRewrite{
+			Drop:     rewrite.Action == ColumnAction_DROP,
+			Name:     rewrite.Name,
+			Optional: rewrite.Action == ColumnAction_MASK,
+		}
+	}
+	writer := NewParquetFileWriterOpen(sink, _schema.RewriteSchema(descr, rewrites), properties)
+	// The column of the new schema of each column, -1 if dropped
+	columns := make([]int, descr.NumColumns())
+	next := 0
+	for i := range columns {
+		columns[i] = -1
+		if actions[i] != ColumnAction_DROP {
+			columns[i] = next
+			next++
+		}
+	}
+	for i := 0; i < source.NumRowGroups(); i++ {
+		row_group := source.RowGroup(i)
+		rg_writer := writer.AppendRowGroup(row_group.NumRows())
+		var sorting_columns []column.SortingColumn
+		for _, sorting := range row_group.SortingColumns() {
+			action := actions[sorting.ColumnIdx]
+			if action == ColumnAction_DROP || action == ColumnAction_MASK {
+				break
+			}
+			sorting.ColumnIdx = columns[sorting.ColumnIdx]
+			sorting_columns = append(sorting_columns, sorting)
+		}
+		rg_writer.SetSortingColumns(sorting_columns)
+		for j := 0; j < descr.NumColumns(); j++ {
+			switch actions[j] {
+			case ColumnAction_DROP:
+				continue
+			case ColumnAction_KEEP:
+				rg_writer.CopyColumn(row_group, j)
+				continue
+			}
+			newRowSplitter(row_group.Column(j), actions[j] == ColumnAction_MASK).CopyRows(
+				rg_writer.NextColumn(), row_group.NumRows())
+			metadata := row_group.ColumnKeyValueMetadata(j)
+			for k := 0; k < metadata.Size(); k++ {
+				rg_writer.AddColumnKeyValueMetadata(columns[j], metadata.Key(k),
+					metadata.Value(k))
+			}
+		}
+		rg_writer.Close()
+	}
+	metadata := source.KeyValueMetadata()
+	for i := 0; i < metadata.Size(); i++ {
+		writer.AddKeyValueMetadata(metadata.Key(i), metadata.Value(i))
+	}
+	writer.Close()
+}
@@ -0,0 +1,207 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+const rewriteSchema = `message rewrite {
+  required int32 a;
+  optional binary b (UTF8);
+  required int64 c;
+}
+`
+
+// Two row groups sorted by a then c, b being null on odd rows
+func writeRewriteInput() *ParquetFileReader {
+	properties := column.NewWriterPropertiesBuilder().
+		SortingColumns(column.SortingColumn{ColumnIdx: 0}, column.SortingColumn{ColumnIdx: 2}).
+		EnablePageIndex().
+		DataPagesize(64).
+		WriteBatchSize(10).
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(rewriteSchema), properties)
+	for rg := 0; rg < 2; rg++ {
+		row_group := writer.AppendRowGroup(50)
+		a := sequence(int32(rg*50), 50)
+		def_levels := make([]int16, 50)
+		var b []ptype.ByteArray
+		c := make([]int64, 50)
+		for i := range a {
+			if i%2 == 0 {
+				def_levels[i] = 1
+				b = append(b, ptype.ByteArray(fmt.Sprint("b", a[i])))
+			}
+			c[i] = int64(a[i]) * 10
+		}
+		row_group.NextColumn().WriteBatch(50, nil, nil, a)
+		row_group.NextColumn().WriteBatch(50, def_levels, nil, b)
+		row_group.AddColumnKeyValueMetadata(1, "column", "b")
+		row_group.NextColumn().WriteBatch(50, nil, nil, c)
+		row_group.Close()
+	}
+	writer.AddKeyValueMetadata("source", "input")
+	writer.Close()
+	return NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+}
+
+// The values of column i of a row group, nil for nulls
+func readRewrittenColumn(row_group *RowGroupReader, i int) []interface{} {
+	reader := row_group.Column(i)
+	max_level := reader.Descr().MaxDefinitionLevel()
+	var values []interface{}
+	for reader.HasNext() {
+		def_levels := make([]int16, 16)
+		batch := make([]interface{}, 16)
+		var levels, n int64
+		switch reader.Type() {
+		case ptype.Type_INT32:
+			read := make([]int32, 16)
+			levels, n = reader.ReadBatch(16, def_levels, nil, read)
+			for k := range read[:n] {
+				batch[k] = read[k]
+			}
+		case ptype.Type_INT64:
+			read := make([]int64, 16)
+			levels, n = reader.ReadBatch(16, def_levels, nil, read)
+			for k := range read[:n] {
+				batch[k] = read[k]
+			}
+		case ptype.Type_BYTE_ARRAY:
+			read := make([]ptype.ByteArray, 16)
+			levels, n = reader.ReadBatch(16, def_levels, nil, read)
+			for k := range read[:n] {
+				batch[k] = string(read[k])
+			}
+		}
+		j := 0
+		for k := int64(0); k < levels; k++ {
+			if max_level == 0 || def_levels[k] == max_level {
+				values = append(values, batch[j])
+				j++
+			} else {
+				values = append(values, nil)
+			}
+		}
+	}
+	return values
+}
+
+func TestRewrite(t *testing.T) {
+	source := writeRewriteInput()
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().
+		CompressionFor("total", ptype.Compression_SNAPPY).
+		Build()
+	Rewrite(&buffer, source, map[string]ColumnRewrite{
+		"a": {Action: ColumnAction_MASK},
+		"b": {Action: ColumnAction_KEEP, Name: "name"},
+		"c": {Action: ColumnAction_REWRITE, Name: "total"},
+	}, properties)
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+
+	expected := `message rewrite {
+  optional int32 a;
+  optional binary name (UTF8);
+  required int64 total;
+}
+`
+	if printed := _schema.Print(reader.Schema().SchemaRoot()); printed != expected {
+		t.Errorf("rewritten schema\n%s\nwant\n%s", printed, expected)
+	}
+	if reader.NumRowGroups() != 2 || reader.NumRows() != 100 {
+		t.Fatalf("%d row groups of %d rows", reader.NumRowGroups(), reader.NumRows())
+	}
+	for rg := 0; rg < 2; rg++ {
+		row_group := reader.RowGroup(rg)
+		a := readRewrittenColumn(row_group, 0)
+		names := readRewrittenColumn(row_group, 1)
+		totals := readRewrittenColumn(row_group, 2)
+		if len(a) != 50 || a[0] != nil || a[49] != nil || row_group.ColumnStatistics(0).NullCount() != 50 {
+			t.Errorf("row group %d: a is not masked %v", rg, a)
+		}
+		if len(names) != 50 || names[0] != fmt.Sprint("b", rg*50) || names[1] != nil ||
+			names[48] != fmt.Sprint("b", rg*50+48) {
+			t.Errorf("row group %d: names %v", rg, names)
+		}
+		if len(totals) != 50 || totals[49] != int64(rg*50+49)*10 {
+			t.Errorf("row group %d: totals %v", rg, totals)
+		}
+		metadata := row_group.Metadata()
+		if metadata.ColumnChunk(2).Codec() != ptype.Compression_SNAPPY ||
+			metadata.ColumnChunk(1).Codec() != ptype.Compression_UNCOMPRESSED {
+			t.Errorf("row group %d codecs %v %v", rg, metadata.ColumnChunk(1).Codec(),
+				metadata.ColumnChunk(2).Codec())
+		}
+		if value, _ := row_group.ColumnKeyValueMetadata(1).Get("column"); value != "b" {
+			t.Errorf("row group %d lost the metadata of b", rg)
+		}
+		if index := row_group.OffsetIndex(1); index == nil ||
+			index.PageLocations[0].Offset != metadata.ColumnChunk(1).DataPageOffset() {
+			t.Errorf("row group %d offset index of name %v", rg, index)
+		}
+		// The rows are no longer sorted by the masked a
+		if sorting := row_group.SortingColumns(); len(sorting) != 0 {
+			t.Errorf("row group %d sorting columns %v", rg, sorting)
+		}
+	}
+	if value, _ := reader.KeyValueMetadata().Get("source"); value != "input" {
+		t.Errorf("lost the footer metadata")
+	}
+}
+
+func TestRewriteDrop(t *testing.T) {
+	var buffer bytes.Buffer
+	Rewrite(&buffer, writeRewriteInput(), map[string]ColumnRewrite{
+		"b": {Action: ColumnAction_DROP},
+	}, nil)
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if reader.NumColumns() != 2 || reader.Schema().Column(1).Path().ToDotString() != "c" {
+		t.Fatalf("%d columns", reader.NumColumns())
+	}
+	row_group := reader.RowGroup(1)
+	if c := readRewrittenColumn(row_group, 1); len(c) != 50 || c[0] != int64(500) {
+		t.Errorf("c %v", c)
+	}
+	// Sorted by a then c, the new index of c
+	if sorting := fmt.Sprint(row_group.SortingColumns()); sorting != "[{0 false false} {1 false false}]" {
+		t.Errorf("sorting columns %s", sorting)
+	}
+	if message := builderPanic(func() {
+		Rewrite(&bytes.Buffer{}, writeRewriteInput(), map[string]ColumnRewrite{"d": {}}, nil)
+	}); message != "Column d is not in the schema" {
+		t.Errorf("missing column: %q", message)
+	}
+}
+
+func TestRewriteMaskRepeated(t *testing.T) {
+	source := writeRepeatedInput()
+	def_levels, rep_levels, _ := readRepeatedLevels(source.RowGroup(0))
+	var buffer bytes.Buffer
+	Rewrite(&buffer, source, map[string]ColumnRewrite{
+		"tags.list.element": {Action: ColumnAction_MASK},
+	}, nil)
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	row_group := reader.RowGroup(0)
+	defs, reps, values := readRepeatedLevels(row_group)
+	// The lists keep their length, with only null elements
+	for i, level := range def_levels {
+		if level > 2 {
+			def_levels[i] = 2
+		}
+	}
+	if fmt.Sprint(defs) != fmt.Sprint(def_levels) || fmt.Sprint(reps) != fmt.Sprint(rep_levels) ||
+		len(values) != 0 {
+		t.Errorf("masked tags have %d levels and %d values, want %d levels", len(defs),
+			len(values), len(def_levels))
+	}
+	if row_group.NumRows() != 12 || row_group.ColumnStatistics(1).NullCount() != int64(len(defs)) {
+		t.Errorf("%d rows, %d nulls", row_group.NumRows(), row_group.ColumnStatistics(1).NullCount())
+	}
+}
@@ -0,0 +1,210 @@
+package file
+
+import (
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/encoding"
+	"io"
+	"reflect"
+)
+
+// Write the rows of source to several files with its schema, opening file
+// i with open, and return the number of files written. With a target_size
+// of 0 each file gets a row group of source. Otherwise consecutive row
+// groups are copied to a file until their column chunks would exceed
+// target_size bytes, and the rows of a larger row group are split into
+// files of their own. Copied row groups keep their column chunks as they
+// are, split ones are decoded and written with properties. The key / value
+// metadata of the footer and the sorting columns are kept. A nil properties
+// uses DefaultWriterProperties. The writers are closed if they are
+// io.Closers.
+func Split(open func(i int) io.Writer, source *ParquetFileReader, target_size int64,
+	properties *column.WriterProperties) int {
+	num_files := 0
+	var writer *ParquetFileWriter
+	var size int64
+	newFile := func() *ParquetFileWriter {
+		file_writer := NewParquetFileWriterOpen(open(num_files), source.Schema().GroupNode(),
+			properties)
+		num_files++
+		metadata := source.KeyValueMetadata()
+		for i := 0; i < metadata.Size(); i++ {
+			file_writer.AddKeyValueMetadata(metadata.Key(i), metadata.Value(i))
+		}
+		return file_writer
+	}
+	closeFile := func() {
+		if writer != nil {
+			writer.Close()
+			writer = nil
+		}
+	}
+	for i := 0; i < source.NumRowGroups(); i++ {
+		row_group := source.RowGroup(i)
+		row_group_size := row_group.Metadata().TotalCompressedSize()
+		if target_size > 0 && row_group_size > target_size {
+			closeFile()
+			num_pieces := (row_group_size + target_size - 1) / target_size
+			num_rows := row_group.NumRows()
+			piece_rows := (num_rows + num_pieces - 1) / num_pieces
+			splitters := make([]*rowSplitter, row_group.NumColumns())
+			for j := range splitters {
+				splitters[j] = newRowSplitter(row_group.Column(j), false)
+			}
+			for start := int64(0); start < num_rows; start += piece_rows {
+				rows := piece_rows
+				if start+rows > num_rows {
+					rows = num_rows - start
+				}
+				piece := newFile()
+				rg_writer := piece.AppendRowGroup(rows)
+				rg_writer.SetSortingColumns(row_group.SortingColumns())
+				for j, splitter := range splitters {
+					splitter.CopyRows(rg_writer.NextColumn(), rows)
+					metadata := row_group.ColumnKeyValueMetadata(j)
+					for k := 0; k < metadata.Size(); k++ {
+						rg_writer.AddColumnKeyValueMetadata(j, metadata.Key(k), metadata.Value(k))
+					}
+				}
+				piece.Close()
+			}
+			continue
+		}
+		if writer != nil && (target_size <= 0 || size+row_group_size > target_size) {
+			closeFile()
+		}
+		if writer == nil {
+			writer = newFile()
+			size = 0
+		}
+		writer.CopyRowGroup(row_group)
+		size += row_group_size
+	}
+	closeFile()
+	return num_files
+}
+
+// Copies the levels and values of a column a number of rows at a time. Only
+// whole rows are written at once, since the column writer may cut a page
+// after any batch and pages of repeated columns must start with a row.
+type rowSplitter struct {
+	reader    *column.ColumnReader
+	defLevels []int16
+	repLevels []int16
+	values    interface{}
+	// Write nulls instead of the values, see Rewrite
+	mask bool
+	// The levels and values read, and the first level and value not copied
+	// yet
+	numLevels int
+	numValues int
+	levelPos  int
+	valuePos  int
+}
+
+func newRowSplitter(reader *column.ColumnReader, mask bool) *rowSplitter {
+	batch_size := column.DEFAULT_WRITE_BATCH_SIZE
+	return &rowSplitter{
+		reader:    reader,
+		defLevels: make([]int16, batch_size),
+		repLevels: make([]int16, batch_size),
+		values:    encoding.MakeValues(reader.Descr().PhysicalType(), batch_size),
+		mask:      mask,
+	}
+}
+
+// Write the next num_rows rows of the column with writer
+func (s *rowSplitter) CopyRows(writer *column.ColumnWriter, num_rows int64) {
+	max_def_level := s.reader.Descr().MaxDefinitionLevel()
+	rows := int64(0)
+	// The levels and values scanned, and where the last row seen starts
+	end, values := s.levelPos, s.valuePos
+	row_start, row_values := end, values
+	for {
+		if end == s.numLevels {
+			// The last row may go on in the next batch
+			s.write(writer, row_start, row_values)
+			end, values = end-s.levelPos, values-s.valuePos
+			row_start, row_values = 0, 0
+			if !s.readBatch() {
+				break
+			}
+		}
+		if s.repLevels[end] == 0 {
+			if rows == num_rows {
+				break
+			}
+			rows++
+			row_start, row_values = end, values
+		}
+		if s.defLevels[end] == max_def_level {
+			values++
+		}
+		end++
+	}
+	s.write(writer, end, values)
+}
+
+// Write the levels and values not copied yet up to end and end_value
+func (s *rowSplitter) write(writer *column.ColumnWriter, end int, end_value int) {
+	if end == s.levelPos {
+		return
+	}
+	def_levels := s.defLevels[s.levelPos:end]
+	values := encoding.SliceValues(s.values, s.valuePos, end_value)
+	if s.mask {
+		// The definition level of a null leaf in the written column
+		null_level := writer.Descr().MaxDefinitionLevel() - 1
+		for i, level := range def_levels {
+			if level > null_level {
+				def_levels[i] = null_level
+			}
+		}
+		values = encoding.SliceValues(s.values, 0, 0)
+	}
+	writer.WriteBatch(int64(end-s.levelPos), def_levels, s.repLevels[s.levelPos:end], values)
+	s.levelPos, s.valuePos = end, end_value
+}
+
+// Read the next batch after the levels and values not copied yet, which are
+// moved to the front, false at the end of the column
+func (s *rowSplitter) readBatch() bool {
+	batch_size := column.DEFAULT_WRITE_BATCH_SIZE
+	pending_levels := s.numLevels - s.levelPos
+	pending_values := s.numValues - s.valuePos
+	def_levels, rep_levels, values := s.defLevels, s.repLevels, s.values
+	if pending_levels+batch_size > len(s.defLevels) {
+		// A row longer than the buffers
+		size := 2 * (pending_levels + batch_size)
+		def_levels, rep_levels = make([]int16, size), make([]int16, size)
+		values = encoding.MakeValues(s.reader.Descr().PhysicalType(), size)
+	}
+	copy(def_levels, s.defLevels[s.levelPos:s.numLevels])
+	copy(rep_levels, s.repLevels[s.levelPos:s.numLevels])
+	reflect.Copy(reflect.ValueOf(values),
+		reflect.ValueOf(encoding.SliceValues(s.values, s.valuePos, s.numValues)))
+	s.defLevels, s.repLevels, s.values = def_levels, rep_levels, values
+	s.numLevels, s.numValues = pending_levels, pending_values
+	s.levelPos, s.valuePos = 0, 0
+	levels_read, values_read := s.reader.ReadBatch(int64(batch_size),
+		s.defLevels[s.numLevels:], s.repLevels[s.numLevels:],
+		encoding.SliceValues(s.values, s.numValues, encoding.ValuesLen(s.values)))
+	if levels_read == 0 {
+		return false
+	}
+	descr := s.reader.Descr()
+	read := s.numLevels + int(levels_read)
+	// Required columns have no levels, and flat ones no repetition levels
+	if descr.MaxDefinitionLevel() == 0 {
+		for i := range s.defLevels[s.numLevels:read] {
+			s.defLevels[s.numLevels+i] = 0
+		}
+	}
+	if descr.MaxRepetitionLevel() == 0 {
+		for i := range s.repLevels[s.numLevels:read] {
+			s.repLevels[s.numLevels+i] = 0
+		}
+	}
+	s.numLevels = read
+	s.numValues += int(values_read)
+	return true
+}
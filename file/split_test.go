@@ -0,0 +1,199 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+// Split source and read the files back
+func splitFiles(source *ParquetFileReader, target_size int64) []*ParquetFileReader {
+	var buffers []*bytes.Buffer
+	num_files := Split(func(i int) io.Writer {
+		buffers = append(buffers, &bytes.Buffer{})
+		return buffers[i]
+	}, source, target_size, nil)
+	if num_files != len(buffers) {
+		panic(fmt.Errorf("%d files written, %d opened", num_files, len(buffers)))
+	}
+	readers := make([]*ParquetFileReader, num_files)
+	for i, buffer := range buffers {
+		readers[i] = NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	}
+	return readers
+}
+
+// The values of column c of the files, and the rows of each of their row
+// groups
+func readSplitFiles(readers []*ParquetFileReader) ([]interface{}, [][]int64) {
+	var values []interface{}
+	rows := make([][]int64, len(readers))
+	for i, reader := range readers {
+		for j := 0; j < reader.NumRowGroups(); j++ {
+			values = append(values, readRewrittenColumn(reader.RowGroup(j), 2)...)
+			rows[i] = append(rows[i], reader.RowGroup(j).NumRows())
+		}
+	}
+	return values, rows
+}
+
+func TestSplitByRowGroup(t *testing.T) {
+	source := writeRewriteInput()
+	readers := splitFiles(source, 0)
+	values, rows := readSplitFiles(readers)
+	if fmt.Sprint(rows) != "[[50] [50]]" || len(values) != 100 || values[99] != int64(990) {
+		t.Errorf("row groups of %v rows", rows)
+	}
+	for i, reader := range readers {
+		if value, _ := reader.KeyValueMetadata().Get("source"); value != "input" {
+			t.Errorf("file %d lost the footer metadata", i)
+		}
+		if len(reader.RowGroup(0).SortingColumns()) != 2 {
+			t.Errorf("file %d lost the sorting columns", i)
+		}
+	}
+
+	// Both row groups fit in a file
+	size := source.Metadata().RowGroup(0).TotalCompressedSize() + source.Metadata().RowGroup(1).TotalCompressedSize()
+	if _, rows := readSplitFiles(splitFiles(source, size)); fmt.Sprint(rows) != "[[50 50]]" {
+		t.Errorf("row groups of %v rows", rows)
+	}
+}
+
+func TestSplitRowGroups(t *testing.T) {
+	source := writeRewriteInput()
+	size := source.Metadata().RowGroup(0).TotalCompressedSize()
+	readers := splitFiles(source, size/3)
+	values, rows := readSplitFiles(readers)
+	if len(readers) < 6 || len(values) != 100 {
+		t.Fatalf("row groups of %v rows", rows)
+	}
+	for i, value := range values {
+		if value != int64(i*10) {
+			t.Fatalf("row %d: %v", i, value)
+		}
+	}
+	for i, reader := range readers {
+		row_group := reader.RowGroup(0)
+		if reader.NumRowGroups() != 1 || row_group.NumRows() > 50/3+1 {
+			t.Errorf("file %d: row groups of %v rows", i, rows[i])
+		}
+		if value, _ := row_group.ColumnKeyValueMetadata(1).Get("column"); value != "b" {
+			t.Errorf("file %d lost the metadata of b", i)
+		}
+		names := readRewrittenColumn(row_group, 1)
+		a := readRewrittenColumn(row_group, 0)
+		if (a[0].(int32)%2 == 0) != (names[0] != nil) {
+			t.Errorf("file %d: a %v, b %v", i, a[0], names[0])
+		}
+	}
+}
+
+const repeatedSchema = `message repeated {
+  required int32 id;
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (UTF8);
+    }
+  }
+}
+`
+
+// A row group of rows longer than the read and write batches, with null and
+// empty lists and null elements
+func writeRepeatedInput() *ParquetFileReader {
+	properties := column.NewWriterPropertiesBuilder().
+		EnablePageIndex().
+		DataPagesize(256).
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(repeatedSchema), properties)
+	row_group := writer.AppendRowGroup(12)
+	var def_levels, rep_levels []int16
+	var tags []ptype.ByteArray
+	for i := 0; i < 12; i++ {
+		switch {
+		case i%5 == 4:
+			def_levels, rep_levels = append(def_levels, 0), append(rep_levels, 0)
+		case i == 0:
+			def_levels, rep_levels = append(def_levels, 1), append(rep_levels, 0)
+		}
+		if i%5 == 4 || i == 0 {
+			continue
+		}
+		for k := 0; k < i*150; k++ {
+			rep_level := int16(1)
+			if k == 0 {
+				rep_level = 0
+			}
+			rep_levels = append(rep_levels, rep_level)
+			if k%3 == 0 {
+				def_levels = append(def_levels, 2)
+			} else {
+				def_levels = append(def_levels, 3)
+				tags = append(tags, ptype.ByteArray(fmt.Sprint("e", i, ".", k)))
+			}
+		}
+	}
+	row_group.NextColumn().WriteBatch(12, nil, nil, sequence(0, 12))
+	row_group.NextColumn().WriteBatch(int64(len(def_levels)), def_levels, rep_levels, tags)
+	row_group.Close()
+	writer.Close()
+	return NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+}
+
+// The levels and values of the tags column of a row group
+func readRepeatedLevels(row_group *RowGroupReader) ([]int16, []int16, []string) {
+	reader := row_group.Column(1)
+	var def_levels, rep_levels []int16
+	var values []string
+	for reader.HasNext() {
+		defs, reps := make([]int16, 100), make([]int16, 100)
+		read := make([]ptype.ByteArray, 100)
+		levels, n := reader.ReadBatch(100, defs, reps, read)
+		def_levels = append(def_levels, defs[:levels]...)
+		rep_levels = append(rep_levels, reps[:levels]...)
+		for _, value := range read[:n] {
+			values = append(values, string(value))
+		}
+	}
+	return def_levels, rep_levels, values
+}
+
+func TestSplitRepeatedRows(t *testing.T) {
+	source := writeRepeatedInput()
+	def_levels, rep_levels, values := readRepeatedLevels(source.RowGroup(0))
+	readers := splitFiles(source, source.Metadata().RowGroup(0).TotalCompressedSize()/4)
+	if len(readers) < 4 {
+		t.Fatalf("split in %d files", len(readers))
+	}
+	var split_defs, split_reps []int16
+	var split_values []string
+	for i, reader := range readers {
+		row_group := reader.RowGroup(0)
+		defs, reps, read := readRepeatedLevels(row_group)
+		rows := int64(0)
+		for _, level := range reps {
+			if level == 0 {
+				rows++
+			}
+		}
+		if reps[0] != 0 || rows != row_group.NumRows() {
+			t.Errorf("file %d: %d rows, %d in the tags", i, row_group.NumRows(), rows)
+		}
+		split_defs = append(split_defs, defs...)
+		split_reps = append(split_reps, reps...)
+		split_values = append(split_values, read...)
+	}
+	if fmt.Sprint(split_defs) != fmt.Sprint(def_levels) ||
+		fmt.Sprint(split_reps) != fmt.Sprint(rep_levels) ||
+		fmt.Sprint(split_values) != fmt.Sprint(values) {
+		t.Errorf("the split files have %d levels and %d values, want %d and %d",
+			len(split_defs), len(split_values), len(def_levels), len(values))
+	}
+}
@@ -0,0 +1,63 @@
+package file
+
+import (
+	"bytes"
+	"io"
+)
+
+// Sink of a parquet file. Tell is needed to record the page and column chunk
+// offsets in the file metadata.
+type OutputStream interface {
+	io.Writer
+	Close() error
+	// Return the current position in the output stream relative to the start
+	Tell() int64
+}
+
+// An OutputStream over any io.Writer, tracking the number of bytes written.
+// Close closes the underlying writer if it is an io.Closer.
+type WriterOutputStream struct {
+	sink     io.Writer
+	position int64
+}
+
+func NewOutputStream(sink io.Writer) OutputStream {
+	if stream, ok := sink.(OutputStream); ok {
+		return stream
+	}
+	return &WriterOutputStream{sink: sink}
+}
+
+func (w *WriterOutputStream) Write(data []byte) (int, error) {
+	n, err := w.sink.Write(data)
+	w.position += int64(n)
+	return n, err
+}
+
+func (w *WriterOutputStream) Tell() int64 {
+	return w.position
+}
+
+func (w *WriterOutputStream) Close() error {
+	if closer, ok := w.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// An in-memory OutputStream
+type InMemoryOutputStream struct {
+	bytes.Buffer
+}
+
+func NewInMemoryOutputStream() *InMemoryOutputStream {
+	return &InMemoryOutputStream{}
+}
+
+func (i *InMemoryOutputStream) Tell() int64 {
+	return int64(i.Len())
+}
+
+func (i *InMemoryOutputStream) Close() error {
+	return nil
+}
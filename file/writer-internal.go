@@ -3,19 +3,20 @@ package file
 import (
 	"bytes"
 	"encoding/binary"
-	"github.com/zenixls2/goparquet"
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
 	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
 	_schema "github.com/zenixls2/goparquet/schema"
 	"github.com/zenixls2/goparquet/thrift"
-	"io"
 	"unsafe"
 )
 
 var PARQUET_MAGIC = []byte{'P', 'A', 'R', '1'}
 
 type SerializedPageWriter struct {
-	PageWriter
-	Sink                  io.Writer
+	column.PageWriter
+	Sink                  OutputStream
 	Metadata              *ColumnChunkMetaDataBuilder
 	NumValues             int64
 	DictionaryPageOffset  int64
@@ -23,67 +24,83 @@ type SerializedPageWriter struct {
 	TotalUncompressedSize int64
 	TotalCompressedSize   int64
 	Compressor            *Codec
+	// Gets the Bloom filter and page index of the column chunk
+	PageIndex *PageIndexWriter
+	// nil when the page index of the column is not written
+	ColumnIndex *ColumnIndexBuilder
+	OffsetIndex *OffsetIndexBuilder
+	// Number of pages written with each encoding
+	DictEncodingStats map[ptype.Encoding]int32
+	DataEncodingStats map[ptype.Encoding]int32
 }
 
 func (s *SerializedPageWriter) WriteDataPage(page *column.CompressedDataPage) int64 {
 	uncompressed_size := page.UncompressedSize()
 	compressed_data := page.Buffer()
-	data_page_header := _schema.DataPageHeader{
+	data_page_header := thrift.DataPageHeader{
 		NumValues:               page.NumValues(),
 		Encoding:                page.Encoding().ToThrift(),
 		DefinitionLevelEncoding: page.DefinitionLevelEncoding().ToThrift(),
 		RepetitionLevelEncoding: page.RepetitionLevelEncoding().ToThrift(),
 		Statistics:              page.Statistics().ToThrift(),
 	}
-	page_header := _schema.PageHeader{
-		Type:                 goparquet.PageType_DATA_PAGE,
+	page_header := thrift.PageHeader{
+		Type:                 thrift.PageType_DATA_PAGE,
 		UncompressedPageSize: uncompressed_size,
-		CompressedPageSize:   compressed_data.Len(),
-		DataPageHeader:       data_page_header,
+		CompressedPageSize:   int32(compressed_data.Len()),
+		DataPageHeader:       &data_page_header,
 	}
 	// TODO(PARQUET-594) crc checksum
 
-	start_pos := s.Sink.(*bytes.Buffer).Len()
-	if s.DataPageOffset == 0 {
+	start_pos := s.Sink.Tell()
+	// The first page of a buffered column chunk is at 0
+	if s.DataPageOffset == 0 && s.NumValues == 0 {
 		s.DataPageOffset = start_pos
 	}
-	thrift.SerializeThrfitMsg(&page_header, unsafe.Sizeof(page_header), s.Sink)
-	header_size := s.Sink.(*bytes.Buffer).Len() - start_pos
+	thrift.SerializeTriftMsg(&page_header, int(unsafe.Sizeof(page_header)), s.Sink)
+	header_size := s.Sink.Tell() - start_pos
 	s.Sink.Write(compressed_data.Bytes())
-	s.TotalUncompressedSize += uncompressed_size + header_size
-	s.TotalCompressedSize += compressed_data.Len() + header_size
-	s.NumValues += page.NumValues()
+	s.TotalUncompressedSize += int64(uncompressed_size) + header_size
+	s.TotalCompressedSize += int64(compressed_data.Len()) + header_size
+	s.NumValues += int64(page.NumValues())
+	s.DataEncodingStats[page.Encoding()]++
+	if s.OffsetIndex != nil {
+		s.OffsetIndex.AddPage(start_pos, int32(s.Sink.Tell()-start_pos), int64(page.NumRows()))
+		s.ColumnIndex.AddPage(page.Statistics(), page.NumValues())
+	}
 
-	return s.Sink.(*bytes.Buffer).Len() - start_pos
+	return s.Sink.Tell() - start_pos
 }
 
-func (s *SerializedPageWriter) WriteDictionaryPage(page *DictionaryPage) int64 {
+func (s *SerializedPageWriter) WriteDictionaryPage(page *column.DictionaryPage) int64 {
 	uncompressed_size := page.Size()
 	compressed_data := s.Compress(page.Buffer())
-	dict_page_header := _schema.DictionaryPageHeader{
+	is_sorted := page.IsSorted()
+	dict_page_header := thrift.DictionaryPageHeader{
 		NumValues: page.NumValues(),
-		Encoding:  page.Encoding().ToThrfit(),
-		IsSorted:  page.IsSorted(),
+		Encoding:  page.Encoding().ToThrift(),
+		IsSorted:  &is_sorted,
 	}
-	page_header := _schema.PageHeader{
-		Type:                 goparquet.PageType_DICTIONARY_PAGE,
+	page_header := thrift.PageHeader{
+		Type:                 thrift.PageType_DICTIONARY_PAGE,
 		UncompressedPageSize: uncompressed_size,
-		CompressedPageSize:   compressed_data.Len(),
-		DictionaryPageHeader: dict_page_header,
+		CompressedPageSize:   int32(compressed_data.Len()),
+		DictionaryPageHeader: &dict_page_header,
 	}
 	// TODO(PARQUET-594) crc checksum
 
-	start_pos := s.Sink.(*bytes.Buffer).Len()
+	start_pos := s.Sink.Tell()
 	if s.DictionaryPageOffset == 0 {
 		s.DictionaryPageOffset = start_pos
 	}
-	thrift.SerializeThrfitMsg(&page_header, unsafe.Sizeof(page_header), s.Sink)
-	header_size := s.Sink.(*bytes.Buffer).Len() - start_pos
+	thrift.SerializeTriftMsg(&page_header, int(unsafe.Sizeof(page_header)), s.Sink)
+	header_size := s.Sink.Tell() - start_pos
 	s.Sink.Write(compressed_data.Bytes())
-	s.TotalUncompressedSize += uncompressed_size + header_size
-	s.TotalCompressedSize += compressed_data.Len() + header_size
+	s.TotalUncompressedSize += int64(uncompressed_size) + header_size
+	s.TotalCompressedSize += int64(compressed_data.Len()) + header_size
+	s.DictEncodingStats[page.Encoding()]++
 
-	return s.Sink.(*bytes.Buffer).Len() - start_pos
+	return s.Sink.Tell() - start_pos
 }
 
 func (s *SerializedPageWriter) Compress(buffer *bytes.Buffer) *bytes.Buffer {
@@ -96,14 +113,25 @@ func (s *SerializedPageWriter) Compress(buffer *bytes.Buffer) *bytes.Buffer {
 	return s.Compressor.Compress(buffer)
 }
 
-func (s *SerializedPageWriter) Close(has_dictionary bool, fallback bool) {
+func (s *SerializedPageWriter) Close(has_dictionary bool, fallback bool,
+	statistics *column.EncodedStatistics, bloom_filter *bloom.BlockSplitBloomFilter) {
+	if statistics != nil && statistics.IsSet() {
+		s.Metadata.SetStatistics(statistics)
+	}
 	// index_page_offset = 0 since they are not supported
 	// TODO: Remove default fallback = 'false' when implemented
 	s.Metadata.Finish(s.NumValues, s.DictionaryPageOffset, 0, s.DataPageOffset,
-		s.TotalCompressedSize, s.TotalUncompressedSize, has_dictionary, fallback)
+		s.TotalCompressedSize, s.TotalUncompressedSize, has_dictionary, fallback, s.DictEncodingStats,
+		s.DataEncodingStats)
+	if bloom_filter != nil {
+		s.PageIndex.AddBloomFilter(s.Metadata, bloom_filter)
+	}
+	if s.OffsetIndex != nil {
+		s.PageIndex.AddColumnChunk(s.Metadata, s.ColumnIndex.Build(), s.OffsetIndex.Build())
+	}
 }
 
-func NewSerializedPageWriter(sink io.Writer, codec *Compression, metadata *ColumnChunkMetaDataBuilder, allocator *MemoryAllocator) *SerializedPageWriter {
+func NewSerializedPageWriter(sink OutputStream, codec ptype.Compression, metadata *ColumnChunkMetaDataBuilder) *SerializedPageWriter {
 	return &SerializedPageWriter{
 		Sink:                  sink,
 		Metadata:              metadata,
@@ -113,24 +141,74 @@ func NewSerializedPageWriter(sink io.Writer, codec *Compression, metadata *Colum
 		TotalUncompressedSize: 0,
 		TotalCompressedSize:   0,
 		Compressor:            NewCodec(codec),
+		DictEncodingStats:     make(map[ptype.Encoding]int32),
+		DataEncodingStats:     make(map[ptype.Encoding]int32),
 	}
 }
 
-func (e *EncodedStatistics) ToThrift() _schema.Statistics {
-	statistics := new(_schema.Statistics)
-	if e.HasMin {
-		statistics.SetMin(e.Min())
+// Writes the pages of a column chunk to memory, so that the columns of a
+// row group can be encoded at the same time. The chunk is copied to the sink
+// by Flush, and only then finished with its offsets in the file.
+type BufferedPageWriter struct {
+	column.PageWriter
+	Pager  *SerializedPageWriter
+	buffer *InMemoryOutputStream
+	// The arguments of Close, applied by Flush
+	closed        bool
+	hasDictionary bool
+	fallback      bool
+	statistics    *column.EncodedStatistics
+	bloomFilter   *bloom.BlockSplitBloomFilter
+}
+
+func (b *BufferedPageWriter) WriteDataPage(page *column.CompressedDataPage) int64 {
+	return b.Pager.WriteDataPage(page)
+}
+
+func (b *BufferedPageWriter) WriteDictionaryPage(page *column.DictionaryPage) int64 {
+	return b.Pager.WriteDictionaryPage(page)
+}
+
+func (b *BufferedPageWriter) Compress(buffer *bytes.Buffer) *bytes.Buffer {
+	return b.Pager.Compress(buffer)
+}
+
+func (b *BufferedPageWriter) Close(has_dictionary bool, fallback bool,
+	statistics *column.EncodedStatistics, bloom_filter *bloom.BlockSplitBloomFilter) {
+	b.closed = true
+	b.hasDictionary = has_dictionary
+	b.fallback = fallback
+	b.statistics = statistics
+	b.bloomFilter = bloom_filter
+}
+
+// Copy the closed column chunk to the sink and finish its metadata
+func (b *BufferedPageWriter) Flush(sink OutputStream) {
+	if !b.closed {
+		panic(fmt.Errorf("Column chunk %s was not closed",
+			b.Pager.Metadata.Descr().Path().ToDotString()))
 	}
-	if e.HasMax {
-		statistics.SetMax(e.Max())
+	base := sink.Tell()
+	sink.Write(b.buffer.Bytes())
+	b.buffer = nil
+	pager := b.Pager
+	pager.Sink = sink
+	if b.hasDictionary {
+		pager.DictionaryPageOffset += base
 	}
-	if e.HasNullCount {
-		statistics.SetNullCount(e.NullCount)
+	pager.DataPageOffset += base
+	if pager.OffsetIndex != nil {
+		pager.OffsetIndex.Shift(base)
 	}
-	if e.HasDistinctCount {
-		statistics.SetDistinctCount(e.DistinctCount)
+	pager.Close(b.hasDictionary, b.fallback, b.statistics, b.bloomFilter)
+}
+
+func NewBufferedPageWriter(codec ptype.Compression, metadata *ColumnChunkMetaDataBuilder) *BufferedPageWriter {
+	buffer := NewInMemoryOutputStream()
+	return &BufferedPageWriter{
+		Pager:  NewSerializedPageWriter(buffer, codec, metadata),
+		buffer: buffer,
 	}
-	return statistics
 }
 
 // -----------------------------------------------------------------
@@ -138,59 +216,271 @@ func (e *EncodedStatistics) ToThrift() _schema.Statistics {
 
 type RowGroupSerializer struct {
 	RowGroupWriterContents
-	NumRows             int
-	Sink                io.Writer
+	numRows             int64
+	Sink                OutputStream
 	Metadata            *RowGroupMetaDataBuilder
-	Properties          *WriterProperties
+	Properties          *column.WriterProperties
 	TotalBytesWritten   int64
 	Closed              bool
-	CurrentColumnWriter *ColumnWriter
+	CurrentColumnWriter *column.ColumnWriter
+	PageIndex           *PageIndexWriter
+	// Buffered row groups open the writers of all their columns at once,
+	// and write the column chunks to the sink on Close
+	Buffered       bool
+	ColumnWriters  []*column.ColumnWriter
+	BufferedPagers []*BufferedPageWriter
+	// The key / value metadata of each column chunk, nil without any
+	ColumnKeyValueMetadata []*KeyValueMetadata
+	columnMetadata         []*ColumnChunkMetaDataBuilder
+	// The SortComparisons of the sorting columns by column, when validated
+	sortComparisons [][]int8
 }
 
 func (r *RowGroupSerializer) NumColumns() int {
 	return r.Metadata.NumColumns()
 }
 
+// The rows of a buffered row group are those written to its columns
 func (r *RowGroupSerializer) NumRows() int64 {
-	return r.NumRows
+	if r.Buffered && !r.Closed && len(r.ColumnWriters) > 0 {
+		return r.ColumnWriters[0].RowsWritten()
+	}
+	return r.numRows
+}
+
+// Estimated size of the column chunks of a buffered row group
+func (r *RowGroupSerializer) EstimatedSize() int64 {
+	size := int64(0)
+	for _, writer := range r.ColumnWriters {
+		size += writer.EstimatedSize()
+	}
+	return size
 }
 
-func (r *RowGroupSerializer) NextColumn() *ColumnWriter {
+func (r *RowGroupSerializer) NextColumn() *column.ColumnWriter {
+	if r.Buffered {
+		panic(fmt.Errorf("The columns of a buffered row group are written with Column"))
+	}
 	// Throws an error if more columns are being written
 	col_meta := r.Metadata.NextColumnChunnk()
+	r.columnMetadata = append(r.columnMetadata, col_meta)
 	if r.CurrentColumnWriter != nil {
-		r.TotalBytesWritten += r.CurrentColumnWriter.Close()
+		r.closeColumn(len(r.columnMetadata)-2, r.CurrentColumnWriter)
 	}
 	column_descr := col_meta.Descr()
-	pager := NewPageWriter(NewSerializedPageWriter(
-		r.Sink, r.Properties.Compression(column_descr.Path()),
-		col_meta, r.Properties.Allocator()))
-	r.CurrentColumnWriter = NewColumnWriterMake(col_meta, pager, r.NumRows,
-		r.Properties)
+	pager := NewSerializedPageWriter(
+		r.Sink, r.Properties.Compression(column_descr.Path()), col_meta)
+	r.initPageIndex(pager)
+	r.CurrentColumnWriter = column.NewColumnWriterMake(column_descr, pager,
+		r.numRows, r.Properties)
+	r.trackSortOrder(len(r.columnMetadata)-1, r.CurrentColumnWriter)
 	return r.CurrentColumnWriter
 }
 
+// Copy column i of a row group of another file as the next column, with
+// its pages as they are. The columns must have the same type and levels.
+func (r *RowGroupSerializer) CopyColumn(row_group *RowGroupReader, i int) {
+	if r.Buffered {
+		panic(fmt.Errorf("The columns of a buffered row group are written with Column"))
+	}
+	col_meta := r.Metadata.NextColumnChunnk()
+	r.columnMetadata = append(r.columnMetadata, col_meta)
+	if r.CurrentColumnWriter != nil {
+		r.closeColumn(len(r.columnMetadata)-2, r.CurrentColumnWriter)
+		r.CurrentColumnWriter = nil
+	}
+	column_descr := col_meta.Descr()
+	source_descr := row_group.Schema().Column(i)
+	if column_descr.PhysicalType() != source_descr.PhysicalType() ||
+		column_descr.MaxDefinitionLevel() != source_descr.MaxDefinitionLevel() ||
+		column_descr.MaxRepetitionLevel() != source_descr.MaxRepetitionLevel() {
+		panic(fmt.Errorf("Cannot copy column %s to column %s of a different type",
+			source_descr.Path().ToDotString(), column_descr.Path().ToDotString()))
+	}
+	if row_group.NumRows() != r.numRows {
+		panic(fmt.Errorf("Column %s has %d rows, expected %d",
+			source_descr.Path().ToDotString(), row_group.NumRows(), r.numRows))
+	}
+	copyColumnChunk(r.Sink, r.PageIndex, col_meta, row_group, i)
+	r.TotalBytesWritten += row_group.Metadata().ColumnChunk(i).TotalCompressedSize()
+}
+
+func (r *RowGroupSerializer) closeColumn(i int, writer *column.ColumnWriter) {
+	if r.sortComparisons != nil {
+		r.sortComparisons[i] = writer.SortComparisons()
+		r.checkSortOrder()
+	}
+	r.TotalBytesWritten += writer.Close()
+	r.checkRowsWritten(writer)
+}
+
+// Compare the rows of column i if the order of the sorting columns is
+// validated and it is one of them
+func (r *RowGroupSerializer) trackSortOrder(i int, writer *column.ColumnWriter) {
+	if r.sortComparisons == nil {
+		return
+	}
+	for _, sorting := range r.Properties.SortingColumns() {
+		if sorting.ColumnIdx == i {
+			writer.TrackSortOrder(sorting)
+		}
+	}
+}
+
+// Throws an error if the rows are not in the order of the sorting columns
+// compared so far. Checked as each column is closed, before its chunk is
+// written, since the rows out of order in the first sorting columns are out
+// of order whatever the next ones hold.
+func (r *RowGroupSerializer) checkSortOrder() {
+	if r.sortComparisons == nil {
+		return
+	}
+	var comparisons [][]int8
+	for _, sorting := range r.Properties.SortingColumns() {
+		// The rows of copied column chunks are not compared
+		if r.sortComparisons[sorting.ColumnIdx] == nil {
+			break
+		}
+		comparisons = append(comparisons, r.sortComparisons[sorting.ColumnIdx])
+	}
+	if row := column.FindUnsortedRow(comparisons); row >= 0 {
+		panic(fmt.Errorf("Row %d is out of the order of the sorting columns", row))
+	}
+}
+
+// Throws an error if the column did not get the rows of the row group
+func (r *RowGroupSerializer) checkRowsWritten(writer *column.ColumnWriter) {
+	if writer.RowsWritten() != r.numRows {
+		panic(fmt.Errorf("Column %s has %d rows, expected %d",
+			writer.Descr().Path().ToDotString(), writer.RowsWritten(), r.numRows))
+	}
+}
+
+func (r *RowGroupSerializer) initPageIndex(pager *SerializedPageWriter) {
+	column_descr := pager.Metadata.Descr()
+	pager.PageIndex = r.PageIndex
+	if r.Properties.PageIndexEnabled(column_descr.Path()) {
+		pager.ColumnIndex = NewColumnIndexBuilder(column_descr)
+		pager.OffsetIndex = NewOffsetIndexBuilder()
+	}
+}
+
+// Open the writers of all the columns, each one writing to its own buffer
+func (r *RowGroupSerializer) initColumnWriters() {
+	for i := 0; i < r.NumColumns(); i++ {
+		col_meta := r.Metadata.NextColumnChunnk()
+		r.columnMetadata = append(r.columnMetadata, col_meta)
+		column_descr := col_meta.Descr()
+		pager := NewBufferedPageWriter(r.Properties.Compression(column_descr.Path()), col_meta)
+		r.initPageIndex(pager.Pager)
+		r.BufferedPagers = append(r.BufferedPagers, pager)
+		r.ColumnWriters = append(r.ColumnWriters, column.NewColumnWriterMake(column_descr,
+			pager, r.numRows, r.Properties))
+		r.trackSortOrder(i, r.ColumnWriters[i])
+	}
+}
+
+// The writer of column i of a buffered row group
+func (r *RowGroupSerializer) Column(i int) *column.ColumnWriter {
+	if !r.Buffered {
+		panic(fmt.Errorf("Only the columns of buffered row groups are written with Column"))
+	}
+	if i < 0 || i >= len(r.ColumnWriters) {
+		panic(fmt.Errorf("The row group only has %d columns, requested writer for column: %d",
+			len(r.ColumnWriters), i))
+	}
+	return r.ColumnWriters[i]
+}
+
+// Add a key / value pair to the metadata of column chunk i, written when
+// the row group is closed
+func (r *RowGroupSerializer) AddColumnKeyValueMetadata(i int, key string, value string) {
+	if i < 0 || i >= r.NumColumns() {
+		panic(fmt.Errorf("The row group only has %d columns, requested metadata for column: %d",
+			r.NumColumns(), i))
+	}
+	if r.Closed {
+		panic(fmt.Errorf("The row group is closed"))
+	}
+	if r.ColumnKeyValueMetadata[i] == nil {
+		r.ColumnKeyValueMetadata[i] = NewKeyValueMetadata()
+	}
+	r.ColumnKeyValueMetadata[i].Append(key, value)
+}
+
+// Declare the sorting columns of the row group, those of the properties by
+// default. The order of the rows is not validated.
+func (r *RowGroupSerializer) SetSortingColumns(sorting_columns []column.SortingColumn) {
+	if r.Closed {
+		panic(fmt.Errorf("The row group is closed"))
+	}
+	for _, sorting := range sorting_columns {
+		if sorting.ColumnIdx < 0 || sorting.ColumnIdx >= r.NumColumns() {
+			panic(fmt.Errorf("The row group only has %d columns, requested sorting by column: %d",
+				r.NumColumns(), sorting.ColumnIdx))
+		}
+	}
+	r.Metadata.SetSortingColumns(sorting_columns)
+	r.sortComparisons = nil
+}
+
 func (r *RowGroupSerializer) Close() {
 	if !r.Closed {
+		if r.Buffered {
+			r.numRows = r.NumRows()
+			for i, writer := range r.ColumnWriters {
+				r.checkRowsWritten(writer)
+				if r.sortComparisons != nil {
+					r.sortComparisons[i] = writer.SortComparisons()
+				}
+			}
+			// Before any column chunk is written
+			r.checkSortOrder()
+			r.Metadata.SetNumRows(r.numRows)
+		}
 		r.Closed = true
+		// The buffered column chunks are written in schema order
+		for i, writer := range r.ColumnWriters {
+			r.closeColumn(i, writer)
+			r.BufferedPagers[i].Flush(r.Sink)
+		}
+		r.ColumnWriters = nil
+		r.BufferedPagers = nil
 		if r.CurrentColumnWriter != nil {
-			r.TotalBytesWritten += r.CurrentColumnWriter.Close()
+			r.closeColumn(len(r.columnMetadata)-1, r.CurrentColumnWriter)
 			r.CurrentColumnWriter = nil
 		}
+		for i, metadata := range r.ColumnKeyValueMetadata {
+			if metadata != nil && i < len(r.columnMetadata) {
+				r.columnMetadata[i].SetKeyValueMetadata(metadata)
+			}
+		}
 		// Ensure all columns have been written
 		r.Metadata.Finish(r.TotalBytesWritten)
 	}
 }
 
-func NewRowGroupSerializer(num_rows int64, sink io.Writer, metadata *RowGroupMetaDataBuilder, properties *WriterProperties) *RowGroupSerializer {
-	return &RowGroupSerializer{
-		NumRows:           num_rows,
+// The rows of a buffered row group are only known on Close, num_rows is
+// then the number of rows expected, or 0 if they are not limited
+func NewRowGroupSerializer(num_rows int64, sink OutputStream, metadata *RowGroupMetaDataBuilder, properties *column.WriterProperties, page_index *PageIndexWriter, buffered bool) *RowGroupSerializer {
+	r := &RowGroupSerializer{
+		numRows:           num_rows,
 		Sink:              sink,
 		Metadata:          metadata,
 		Properties:        properties,
 		TotalBytesWritten: 0,
 		Closed:            false,
+		PageIndex:         page_index,
+		Buffered:          buffered,
+	}
+	r.ColumnKeyValueMetadata = make([]*KeyValueMetadata, r.NumColumns())
+	if properties.SortingValidation() && len(properties.SortingColumns()) > 0 {
+		r.sortComparisons = make([][]int8, r.NumColumns())
 	}
+	if buffered {
+		r.initColumnWriters()
+	}
+	return r
 }
 
 // -----------------------------------------------------------------
@@ -198,23 +488,27 @@ func NewRowGroupSerializer(num_rows int64, sink io.Writer, metadata *RowGroupMet
 
 type FileSerializer struct {
 	ParquetFileWriterContents
-	Sink           io.Writer
+	Sink           OutputStream
 	IsOpen         bool
-	Properties     *WriterProperties
-	NumRowGroups   int
-	NumRows        int64
+	schema         _schema.SchemaDescriptor
+	properties     *column.WriterProperties
+	numRowGroups   int
+	numRows        int64
 	Metadata       *FileMetaDataBuilder
 	RowGroupWriter *RowGroupWriter
+	PageIndex      *PageIndexWriter
+	// The contents of RowGroupWriter, which it drops once closed
+	rowGroupContents RowGroupWriterContents
+	// The key / value metadata of the footer
+	KeyValueMetadata *KeyValueMetadata
 }
 
 func (f *FileSerializer) Close() {
 	if f.IsOpen {
-		if f.RowGroupWriter != nil {
-			f.RowGroupWriter.Close()
-			f.RowGroupWriter = nil
-		}
+		f.closeRowGroup()
 
-		// Write magic bytes and metadata
+		// Write the Bloom filters, page indexes, magic bytes and metadata
+		f.PageIndex.WriteTo(f.Sink)
 		f.WriteMetaData()
 		f.Sink.Close()
 		f.IsOpen = false
@@ -222,32 +516,103 @@ func (f *FileSerializer) Close() {
 }
 
 func (f *FileSerializer) AppendRowGroup(num_rows int64) *RowGroupWriter {
+	return f.appendRowGroup(num_rows, false)
+}
+
+func (f *FileSerializer) AppendBufferedRowGroup(num_rows int64) *RowGroupWriter {
+	return f.appendRowGroup(num_rows, true)
+}
+
+func (f *FileSerializer) appendRowGroup(num_rows int64, buffered bool) *RowGroupWriter {
+	f.closeRowGroup()
+	f.numRowGroups++
+	rg_metadata := f.Metadata.AppendRowGroup(num_rows)
+	f.rowGroupContents = NewRowGroupSerializer(num_rows, f.Sink, rg_metadata, f.properties,
+		f.PageIndex, buffered)
+	f.RowGroupWriter = NewRowGroupWriter(f.rowGroupContents)
+	return f.RowGroupWriter
+}
+
+// Append a row group of another file with the same schema, whose column
+// chunks are copied as they are along with their page indexes and Bloom
+// filters
+func (f *FileSerializer) CopyRowGroup(row_group *RowGroupReader) {
+	source := row_group.Metadata()
+	if !f.schema.Equals(source.Schema()) {
+		panic(fmt.Errorf("Cannot copy a row group of a different schema"))
+	}
+	f.closeRowGroup()
+	f.numRowGroups++
+	rg_metadata := f.Metadata.AppendRowGroup(source.NumRows())
+	rg_metadata.SetSortingColumns(source.SortingColumns())
+	for i := 0; i < source.NumColumns(); i++ {
+		copyColumnChunk(f.Sink, f.PageIndex, rg_metadata.NextColumnChunnk(), row_group, i)
+	}
+	rg_metadata.Finish(source.TotalByteSize())
+	f.numRows += source.NumRows()
+}
+
+// Write column chunk i of row_group to sink as it is, its Bloom filter and
+// page indexes going to page_index
+func copyColumnChunk(sink OutputStream, page_index *PageIndexWriter,
+	col_meta *ColumnChunkMetaDataBuilder, row_group *RowGroupReader, i int) {
+	col := row_group.Metadata().ColumnChunk(i)
+	delta := sink.Tell() - col.ChunkRange().Offset
+	sink.Write(row_group.ReadColumnChunk(i))
+	col_meta.FinishCopy(col, delta)
+	if bloom_filter := row_group.ColumnBloomFilter(i); bloom_filter != nil {
+		page_index.AddBloomFilter(col_meta, bloom_filter)
+	}
+	if offset_index := row_group.OffsetIndex(i); offset_index != nil {
+		for _, location := range offset_index.PageLocations {
+			location.Offset += delta
+		}
+		page_index.AddColumnChunk(col_meta, row_group.ColumnIndex(i), offset_index)
+	}
+}
+
+// Close the current row group and count its rows, which buffered row
+// groups only know once closed
+func (f *FileSerializer) closeRowGroup() {
 	if f.RowGroupWriter != nil {
 		f.RowGroupWriter.Close()
+		f.RowGroupWriter = nil
 	}
-	f.NumRows += num_rows
-	f.NumRowGroups++
-	rg_metadata := r.Metadata.AppendRowGroup(num_rows)
-	var contents RowGroupWriterContents
-	contents = NewRowGroupSerializer(num_rows, f.Sink, rg_metadata, f.Properties)
-	f.RowGroupWriter = NewRowGroupWriter(contents)
-	return f.RowGroupWriter
+	if f.rowGroupContents != nil {
+		f.numRows += f.rowGroupContents.NumRows()
+		f.rowGroupContents = nil
+	}
+}
+
+// Add a key / value pair to the footer, until the file is closed
+func (f *FileSerializer) AddKeyValueMetadata(key string, value string) {
+	if !f.IsOpen {
+		panic(fmt.Errorf("The file is closed"))
+	}
+	f.KeyValueMetadata.Append(key, value)
+}
+
+func (f *FileSerializer) Properties() *column.WriterProperties {
+	return f.properties
 }
 
-func (f *FileSerializer) Properties() *WriterProperties {
-	return f.Properties
+func (f *FileSerializer) Schema() *_schema.SchemaDescriptor {
+	return &f.schema
 }
 
 func (f *FileSerializer) NumColumns() int {
-	return f.Schema.NumColumns()
+	return f.schema.NumColumns()
 }
 
 func (f *FileSerializer) NumRowGroups() int {
-	return f.NumRowGroups
+	return f.numRowGroups
 }
 
 func (f *FileSerializer) NumRows() int64 {
-	return f.NumRows
+	if f.rowGroupContents != nil {
+		return f.numRows + f.rowGroupContents.NumRows()
+	}
+	return f.numRows
 }
 
 func (f *FileSerializer) StartFile() {
@@ -256,33 +621,36 @@ func (f *FileSerializer) StartFile() {
 
 func (f *FileSerializer) WriteMetaData() {
 	// Write MetaData
-	metadata_len := f.Sink.(*bytes.Buffer).Len()
+	metadata_len := f.Sink.Tell()
 
 	// Get a FileMetaData
+	f.Metadata.SetKeyValueMetadata(f.KeyValueMetadata)
 	metadata := f.Metadata.Finish()
 	metadata.WriteTo(f.Sink)
-	metadata_len = f.Sink.(*bytes.Buffer).Len() - metadata_len
+	metadata_len = f.Sink.Tell() - metadata_len
 
 	// Write Footer
-	metadata_len_reinterpret := *(*int)(unsafe.Pointer(&metadata_len))
-	binary.Write(f.Sink, binary.LittleEndian, metadata_len_reinterpret)
+	binary.Write(f.Sink, binary.LittleEndian, uint32(metadata_len))
 	f.Sink.Write(PARQUET_MAGIC)
 }
 
-func NewFileSerializerOpen(sink io.Writer, schema *_schema.GroupNode, properties *WriterProperties) ParquetFileWriterContents {
+func NewFileSerializerOpen(sink OutputStream, schema *_schema.GroupNode, properties *column.WriterProperties) ParquetFileWriterContents {
 	return NewFileSerializer(sink, schema, properties)
 }
 
-func NewFileSerializer(sink io.Writer, schema *_schema.GroupNode, properties *WriterProperties) *FileSerializer {
+func NewFileSerializer(sink OutputStream, schema *_schema.GroupNode, properties *column.WriterProperties) *FileSerializer {
 	f := FileSerializer{
 		Sink:         sink,
 		IsOpen:       true,
-		Properties:   properties,
-		NumRowGroups: 0,
-		NumRows:      0,
+		properties:   properties,
+		numRowGroups: 0,
+		numRows:      0,
+		PageIndex:    NewPageIndexWriter(),
+
+		KeyValueMetadata: NewKeyValueMetadata(),
 	}
-	f.Schema.Init(schema)
-	f.Metadata = NewFileMetaDataBuilderMake(schema, properties)
+	f.schema.Init(&schema.Node)
+	f.Metadata = NewFileMetaDataBuilderMake(&f.schema, properties)
 	f.StartFile()
 	return &f
 }
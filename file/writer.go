@@ -1,13 +1,20 @@
 package file
 
 import (
+	"github.com/zenixls2/goparquet/column"
+	_schema "github.com/zenixls2/goparquet/schema"
 	"io"
+	"sync"
 )
 
 type RowGroupWriterContents interface {
 	NumColumns() int
 	NumRows() int64
-	NextColumn() *ColumnWriter
+	NextColumn() *column.ColumnWriter
+	Column(i int) *column.ColumnWriter
+	CopyColumn(row_group *RowGroupReader, i int)
+	AddColumnKeyValueMetadata(i int, key string, value string)
+	SetSortingColumns(sorting_columns []column.SortingColumn)
 	Close()
 }
 
@@ -15,10 +22,88 @@ type RowGroupWriter struct {
 	Contents RowGroupWriterContents
 }
 
-func (r *RowGroupWriter) NextColumn() *ColumnWriter {
+// Construct a ColumnWriter for the indicated row group-relative column.
+//
+// Ownership is solely within the RowGroupWriter. The ColumnWriter is only valid
+// until the next call to NextColumn or Close. As the contents are directly
+// written to the sink, once a new column is started, the contents of the
+// previous one cannot be modified anymore.
+func (r *RowGroupWriter) NextColumn() *column.ColumnWriter {
 	return r.Contents.NextColumn()
 }
 
+// The ColumnWriter of column i of a buffered row group. Each column is
+// written to its own buffer, so different columns can be written from
+// different goroutines. The column chunks are written to the sink in schema
+// order on Close.
+func (r *RowGroupWriter) Column(i int) *column.ColumnWriter {
+	return r.Contents.Column(i)
+}
+
+// Copy column i of a row group of another file as the next column, without
+// decoding its pages. The columns must have the same type and levels, not
+// the same name.
+func (r *RowGroupWriter) CopyColumn(row_group *RowGroupReader, i int) {
+	r.Contents.CopyColumn(row_group, i)
+}
+
+// Write the columns of a buffered row group with up to concurrency
+// goroutines. write is called once for each column, whose writer is closed
+// once it returns. A panic of write is raised again once all the goroutines
+// are done.
+func (r *RowGroupWriter) WriteColumns(concurrency int,
+	write func(i int, writer *column.ColumnWriter)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	num_columns := r.NumColumns()
+	writers := make([]*column.ColumnWriter, num_columns)
+	for i := range writers {
+		writers[i] = r.Column(i)
+	}
+	next := make(chan int)
+	failures := make(chan interface{}, num_columns)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < num_columns; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				func() {
+					defer func() {
+						if failure := recover(); failure != nil {
+							failures <- failure
+						}
+					}()
+					write(i, writers[i])
+					writers[i].Close()
+				}()
+			}
+		}()
+	}
+	for i := 0; i < num_columns; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	close(failures)
+	if failure, ok := <-failures; ok {
+		panic(failure)
+	}
+}
+
+// Add a key / value pair to the metadata of column chunk i, at any time
+// before the row group is closed
+func (r *RowGroupWriter) AddColumnKeyValueMetadata(i int, key string, value string) {
+	r.Contents.AddColumnKeyValueMetadata(i, key, value)
+}
+
+// Declare the columns the rows are sorted by, instead of those of the
+// properties, without validating the order of the rows
+func (r *RowGroupWriter) SetSortingColumns(sorting_columns []column.SortingColumn) {
+	r.Contents.SetSortingColumns(sorting_columns)
+}
+
 func (r *RowGroupWriter) Close() {
 	if r.Contents != nil {
 		r.Contents.Close()
@@ -26,7 +111,12 @@ func (r *RowGroupWriter) Close() {
 	}
 }
 
+func (r *RowGroupWriter) NumColumns() int {
+	return r.Contents.NumColumns()
+}
+
 func (r *RowGroupWriter) NumRows() int64 {
+	return r.Contents.NumRows()
 }
 
 func NewRowGroupWriter(contents RowGroupWriterContents) *RowGroupWriter {
@@ -36,11 +126,14 @@ func NewRowGroupWriter(contents RowGroupWriterContents) *RowGroupWriter {
 type ParquetFileWriterContents interface {
 	Close()
 	AppendRowGroup(int64) *RowGroupWriter
+	AppendBufferedRowGroup(int64) *RowGroupWriter
+	CopyRowGroup(*RowGroupReader)
+	AddKeyValueMetadata(key string, value string)
 	NumRows() int64
 	NumColumns() int
 	NumRowGroups() int
-	Properties() *WriterProperties
-	Schema() *SchemaDescriptor
+	Properties() *column.WriterProperties
+	Schema() *_schema.SchemaDescriptor
 }
 
 type ParquetFileWriter struct {
@@ -62,21 +155,44 @@ func (p *ParquetFileWriter) AppendRowGroup(num_rows int64) *RowGroupWriter {
 	return p.Contents.AppendRowGroup(num_rows)
 }
 
+// A row group whose columns are all open at once, see RowGroupWriter.Column.
+// num_rows limits the rows written, 0 leaves them unlimited.
+func (p *ParquetFileWriter) AppendBufferedRowGroup(num_rows int64) *RowGroupWriter {
+	return p.Contents.AppendBufferedRowGroup(num_rows)
+}
+
+// Append a row group of another file with the same schema, copying its
+// column chunks as they are, without decoding them
+func (p *ParquetFileWriter) CopyRowGroup(row_group *RowGroupReader) {
+	p.Contents.CopyRowGroup(row_group)
+}
+
+// Add a key / value pair to the footer, at any time before Close
+func (p *ParquetFileWriter) AddKeyValueMetadata(key string, value string) {
+	p.Contents.AddKeyValueMetadata(key, value)
+}
+
 func (p *ParquetFileWriter) NumColumns() int {
+	return p.Contents.NumColumns()
 }
 
 func (p *ParquetFileWriter) NumRowGroups() int {
+	return p.Contents.NumRowGroups()
 }
 
-func (p *ParquetFileWriter) Properties() *WriterProperties {
+func (p *ParquetFileWriter) NumRows() int64 {
+	return p.Contents.NumRows()
+}
+
+func (p *ParquetFileWriter) Properties() *column.WriterProperties {
 	return p.Contents.Properties()
 }
 
-func (p *ParquetFileWriter) Schema() *SchemaDescriptor {
+func (p *ParquetFileWriter) Schema() *_schema.SchemaDescriptor {
 	return p.Contents.Schema()
 }
 
-func (p *ParquetFileWriter) Descr(i int) *ColumnDescriptor {
+func (p *ParquetFileWriter) Descr(i int) *_schema.ColumnDescriptor {
 	return p.Contents.Schema().Column(i)
 }
 
@@ -84,9 +200,13 @@ func NewParquetFileWriter() *ParquetFileWriter {
 	return new(ParquetFileWriter)
 }
 
-func NewParquetFileWriterOpen(sink io.Writer, schema GroupNode,
-	properties WriterProperties) *ParquetFileWriter {
-	contents := NewFileSerializerOpen(sink, schema, properties)
+// Open a writer on sink. A nil properties uses DefaultWriterProperties.
+func NewParquetFileWriterOpen(sink io.Writer, schema *_schema.GroupNode,
+	properties *column.WriterProperties) *ParquetFileWriter {
+	if properties == nil {
+		properties = column.DefaultWriterProperties()
+	}
+	contents := NewFileSerializerOpen(NewOutputStream(sink), schema, properties)
 	result := new(ParquetFileWriter)
 	result.Open(contents)
 	return result
@@ -0,0 +1,172 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	_schema "github.com/zenixls2/goparquet/schema"
+)
+
+const sortingSchema = `message sorting {
+  required int32 a;
+  optional binary b (UTF8);
+  repeated int32 c;
+}
+`
+
+// A row group of the values of a and b, "" for a null b
+func writeSortedRowGroup(writer *ParquetFileWriter, buffered bool, a []int32, b []string) {
+	var row_group *RowGroupWriter
+	next := func() *column.ColumnWriter { return row_group.NextColumn() }
+	if buffered {
+		row_group = writer.AppendBufferedRowGroup(int64(len(a)))
+		i := -1
+		next = func() *column.ColumnWriter {
+			i++
+			return row_group.Column(i)
+		}
+	} else {
+		row_group = writer.AppendRowGroup(int64(len(a)))
+	}
+	def_levels := make([]int16, len(b))
+	var values []ptype.ByteArray
+	for i, value := range b {
+		if value != "" {
+			def_levels[i] = 1
+			values = append(values, ptype.ByteArray(value))
+		}
+	}
+	next().WriteBatch(int64(len(a)), nil, nil, a)
+	next().WriteBatch(int64(len(b)), def_levels, nil, values)
+	next().WriteBatch(int64(len(a)), make([]int16, len(a)), make([]int16, len(a)), []int32{})
+	row_group.Close()
+}
+
+func TestSortingColumns(t *testing.T) {
+	sorting := []column.SortingColumn{{ColumnIdx: 1, NullsFirst: true}, {ColumnIdx: 0, Descending: true}}
+	properties := column.NewWriterPropertiesBuilder().
+		SortingColumns(sorting...).
+		EnableSortingValidation().
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(sortingSchema), properties)
+	writeSortedRowGroup(writer, false, []int32{1, 3, 2, 2}, []string{"", "x", "x", "y"})
+	writeSortedRowGroup(writer, true, []int32{5, 4}, []string{"", ""})
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	for i := 0; i < reader.NumRowGroups(); i++ {
+		if actual := fmt.Sprint(reader.RowGroup(i).SortingColumns()); actual != fmt.Sprint(sorting) {
+			t.Errorf("row group %d sorting columns %s", i, actual)
+		}
+	}
+	if actual := reader.Metadata().RowGroup(0).SortingColumns(); len(actual) != 2 || !actual[0].NullsFirst {
+		t.Errorf("metadata sorting columns %v", actual)
+	}
+}
+
+func TestSortingValidation(t *testing.T) {
+	tests := []struct {
+		sorting  []column.SortingColumn
+		buffered bool
+		a        []int32
+		b        []string
+		message  string
+	}{
+		{[]column.SortingColumn{{ColumnIdx: 0}}, false, []int32{1, 2, 2, 1}, []string{"", "", "", ""},
+			"Row 3 is out of the order of the sorting columns"},
+		{[]column.SortingColumn{{ColumnIdx: 0}}, true, []int32{1, 0}, []string{"", ""},
+			"Row 1 is out of the order of the sorting columns"},
+		// The nulls sort last by default
+		{[]column.SortingColumn{{ColumnIdx: 1}}, false, []int32{1, 2}, []string{"", "x"},
+			"Row 1 is out of the order of the sorting columns"},
+		{[]column.SortingColumn{{ColumnIdx: 1}, {ColumnIdx: 0}}, false, []int32{1, 2, 1}, []string{"x", "x", "y"}, ""},
+		{[]column.SortingColumn{{ColumnIdx: 1}, {ColumnIdx: 0}}, false, []int32{2, 1}, []string{"x", "x"},
+			"Row 1 is out of the order of the sorting columns"},
+		{[]column.SortingColumn{{ColumnIdx: 2}}, false, []int32{1}, []string{""},
+			"Column c cannot be sorted"},
+	}
+	for _, test := range tests {
+		properties := column.NewWriterPropertiesBuilder().
+			SortingColumns(test.sorting...).
+			EnableSortingValidation().
+			Build()
+		var buffer bytes.Buffer
+		message := builderPanic(func() {
+			writer := NewParquetFileWriterOpen(&buffer, _schema.MustParse(sortingSchema), properties)
+			writeSortedRowGroup(writer, test.buffered, test.a, test.b)
+		})
+		if message != test.message {
+			t.Errorf("%v %v %v: %q, want %q", test.sorting, test.a, test.b, message, test.message)
+		}
+		// Nothing is written after the magic number when the first column
+		// written is out of order, or the row group is buffered
+		if message != "" && (test.buffered || test.sorting[0].ColumnIdx == 0) && buffer.Len() != 4 {
+			t.Errorf("%v %v %v: %d bytes written", test.sorting, test.a, test.b, buffer.Len())
+		}
+	}
+
+	// Only declared without the validation
+	properties := column.NewWriterPropertiesBuilder().
+		SortingColumns(column.SortingColumn{ColumnIdx: 0}).
+		Build()
+	writer := NewParquetFileWriterOpen(&bytes.Buffer{}, _schema.MustParse(sortingSchema), properties)
+	writeSortedRowGroup(writer, false, []int32{2, 1}, []string{"", ""})
+
+	properties = column.NewWriterPropertiesBuilder().
+		SortingColumns(column.SortingColumn{ColumnIdx: 3}).
+		Build()
+	message := builderPanic(func() {
+		NewParquetFileWriterOpen(&bytes.Buffer{}, _schema.MustParse(sortingSchema), properties)
+	})
+	if message != "The schema only has 3 columns, requested sorting by column: 3" {
+		t.Errorf("missing sorting column: %q", message)
+	}
+}
+
+func TestRepeatedPagesStartWithRows(t *testing.T) {
+	properties := column.NewWriterPropertiesBuilder().
+		EnablePageIndex().
+		DataPagesize(1).
+		WriteBatchSize(4).
+		Build()
+	var buffer bytes.Buffer
+	writer := NewParquetFileWriterOpen(&buffer,
+		_schema.MustParse("message pages { repeated int32 c; }"), properties)
+	// Rows of 5 values, longer than the write batches
+	def_levels := make([]int16, 30)
+	rep_levels := make([]int16, 30)
+	for i := range def_levels {
+		def_levels[i] = 1
+		if i%5 != 0 {
+			rep_levels[i] = 1
+		}
+	}
+	row_group := writer.AppendRowGroup(6)
+	row_group.NextColumn().WriteBatch(30, def_levels, rep_levels, sequence(0, 30))
+	row_group.Close()
+	writer.Close()
+
+	reader := NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	rg_reader := reader.RowGroup(0)
+	locations := rg_reader.OffsetIndex(0).PageLocations
+	if len(locations) != 6 {
+		t.Fatalf("%d pages, want one per row", len(locations))
+	}
+	for i, location := range locations {
+		if location.FirstRowIndex != int64(i) {
+			t.Errorf("page %d starts with row %d", i, location.FirstRowIndex)
+		}
+		// Each page read alone
+		page, _ := rg_reader.ColumnRanges(0, []RowRange{{int64(i), int64(i + 1)}})
+		reps := make([]int16, 10)
+		values := make([]int32, 10)
+		levels, n := page.ReadBatch(10, make([]int16, 10), reps, values)
+		if levels != 5 || n != 5 || reps[0] != 0 || values[0] != int32(i*5) {
+			t.Errorf("page %d: levels %v, values %v", i, reps[:levels], values[:n])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Convert a literal to the type column.Statistics keeps min / max in for
+// the column
+func literalValue(descr *schema.ColumnDescriptor, v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		if value, ok := ptype.TimeValue(t, descr.PhysicalType(), descr.LogicalType()); ok {
+			return value
+		}
+	}
+	switch descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	case ptype.Type_INT32:
+		if i, unsigned, ok := literalInt(v); ok {
+			switch descr.LogicalType() {
+			case ptype.LogicalType_UINT_8, ptype.LogicalType_UINT_16,
+				ptype.LogicalType_UINT_32:
+				if (unsigned || i >= 0) && uint64(i) <= math.MaxUint32 {
+					return int32(uint32(i))
+				}
+			default:
+				if !unsigned && i >= math.MinInt32 && i <= math.MaxInt32 ||
+					unsigned && uint64(i) <= math.MaxInt32 {
+					return int32(i)
+				}
+			}
+		}
+	case ptype.Type_INT64:
+		if i, unsigned, ok := literalInt(v); ok {
+			if descr.LogicalType() == ptype.LogicalType_UINT_64 {
+				if unsigned || i >= 0 {
+					return i
+				}
+			} else if !unsigned || i >= 0 {
+				return i
+			}
+		}
+	case ptype.Type_INT96:
+		if value, ok := v.(ptype.Int96); ok {
+			return value
+		}
+	case ptype.Type_FLOAT:
+		if f, ok := literalFloat(v); ok {
+			return float32(f)
+		}
+	case ptype.Type_DOUBLE:
+		if f, ok := literalFloat(v); ok {
+			return f
+		}
+	case ptype.Type_BYTE_ARRAY:
+		switch value := v.(type) {
+		case string:
+			return ptype.ByteArray(value)
+		case []byte:
+			return ptype.ByteArray(value)
+		}
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		var value []byte
+		switch b := v.(type) {
+		case string:
+			value = []byte(b)
+		case []byte:
+			value = b
+		}
+		if value != nil && len(value) == int(descr.TypeLength()) {
+			return ptype.FixedLenByteArray(value)
+		}
+	}
+	panic(fmt.Errorf("Cannot compare column %s of type %s (%s) to %T value %v",
+		descr.Path().ToDotString(), ptype.TypeToString(descr.PhysicalType()),
+		ptype.LogicalTypeToString(descr.LogicalType()), v, v))
+}
+
+// Integers of any Go type as their 64 bits, unsigned tells how to read them
+func literalInt(v interface{}) (int64, bool, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true, true
+	}
+	return 0, false, false
+}
+
+// NaN has no place in the order of the values
+func literalFloat(v interface{}) (float64, bool) {
+	var f float64
+	switch value := v.(type) {
+	case float32:
+		f = float64(value)
+	case float64:
+		f = value
+	default:
+		i, unsigned, ok := literalInt(v)
+		if !ok {
+			return 0, false
+		}
+		if unsigned {
+			return float64(uint64(i)), true
+		}
+		return float64(i), true
+	}
+	return f, !math.IsNaN(f)
+}
@@ -0,0 +1,542 @@
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/zenixls2/goparquet/bloom"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"strings"
+)
+
+// Predicates over the leaf columns of a schema, e.g.
+//
+//	filter.And(filter.Gte("ts", start), filter.Eq("user.country", "NL"))
+//
+// are evaluated against the statistics of the column chunks of a row group
+// to tell whether none, all or maybe some of its rows match. Columns are
+// named by their dot separated path and literals are Go values converted to
+// the column's type (integers, floats, bool, string or []byte, time.Time for
+// DATE and TIMESTAMP columns). A null value matches no comparison, only
+// IsNull. Eq and In also look their values up in the Bloom filters of the
+// column chunks, when the file has them, and Eq, In and Neq in the
+// dictionaries of the fully dictionary encoded column chunks, when the
+// reader reads them.
+//
+// Predicates on repeated columns hold for a row if they hold for any of its
+// values, so they are never known to be true for all the rows.
+
+// The outcome of evaluating a predicate on a row group, in three valued
+// logic
+type Truth int
+
+const (
+	// No row of the row group matches
+	Truth_FALSE Truth = 0
+	// Every row of the row group matches
+	Truth_TRUE Truth = 1
+	// The statistics do not tell
+	Truth_UNKNOWN Truth = 2
+)
+
+func (t Truth) Not() Truth {
+	switch t {
+	case Truth_FALSE:
+		return Truth_TRUE
+	case Truth_TRUE:
+		return Truth_FALSE
+	}
+	return Truth_UNKNOWN
+}
+
+func (t Truth) And(other Truth) Truth {
+	switch {
+	case t == Truth_FALSE || other == Truth_FALSE:
+		return Truth_FALSE
+	case t == Truth_TRUE && other == Truth_TRUE:
+		return Truth_TRUE
+	}
+	return Truth_UNKNOWN
+}
+
+func (t Truth) Or(other Truth) Truth {
+	switch {
+	case t == Truth_TRUE || other == Truth_TRUE:
+		return Truth_TRUE
+	case t == Truth_FALSE && other == Truth_FALSE:
+		return Truth_FALSE
+	}
+	return Truth_UNKNOWN
+}
+
+func TruthToString(t Truth) string {
+	switch t {
+	case Truth_FALSE:
+		return "FALSE"
+	case Truth_TRUE:
+		return "TRUE"
+	case Truth_UNKNOWN:
+		return "UNKNOWN"
+	}
+	return "UNKNOWN"
+}
+
+// What is known about a row group, file.RowGroupReader implements it
+type RowGroupStatistics interface {
+	Schema() *schema.SchemaDescriptor
+	NumRows() int64
+	// nil when the column chunk has no statistics
+	ColumnStatistics(i int) *column.Statistics
+}
+
+// Implemented by the row groups that also have Bloom filters to look up the
+// values of equality predicates in, file.RowGroupReader does
+type BloomFilterSource interface {
+	// nil when the column chunk has no Bloom filter
+	ColumnBloomFilter(i int) *bloom.BlockSplitBloomFilter
+}
+
+// Implemented by the row groups that read the dictionaries of their column
+// chunks to look the values of equality predicates up in
+type DictionarySource interface {
+	// The values of the dictionary in a slice of the column type, nil unless
+	// the column chunk is fully dictionary encoded
+	ColumnDictionary(i int) interface{}
+}
+
+type Predicate interface {
+	// Check that the columns exist in the schema and the literals fit their
+	// type, panics otherwise
+	Validate(descr *schema.SchemaDescriptor)
+	Evaluate(rowGroup RowGroupStatistics) Truth
+	String() string
+}
+
+// Whether the row group may hold rows matching the predicate, a nil
+// predicate matches everything
+func CanMatch(predicate Predicate, rowGroup RowGroupStatistics) bool {
+	return predicate == nil || predicate.Evaluate(rowGroup) != Truth_FALSE
+}
+
+type compareOp int
+
+const (
+	opEq compareOp = iota
+	opNeq
+	opLt
+	opLte
+	opGt
+	opGte
+)
+
+var compareOpNames = []string{"=", "!=", "<", "<=", ">", ">="}
+
+// column op literal
+type comparison struct {
+	op    compareOp
+	path  string
+	value interface{}
+}
+
+func Eq(path string, value interface{}) Predicate {
+	return &comparison{op: opEq, path: path, value: value}
+}
+
+func Neq(path string, value interface{}) Predicate {
+	return &comparison{op: opNeq, path: path, value: value}
+}
+
+func Lt(path string, value interface{}) Predicate {
+	return &comparison{op: opLt, path: path, value: value}
+}
+
+func Lte(path string, value interface{}) Predicate {
+	return &comparison{op: opLte, path: path, value: value}
+}
+
+func Gt(path string, value interface{}) Predicate {
+	return &comparison{op: opGt, path: path, value: value}
+}
+
+func Gte(path string, value interface{}) Predicate {
+	return &comparison{op: opGte, path: path, value: value}
+}
+
+func (c *comparison) Validate(descr *schema.SchemaDescriptor) {
+	literalValue(lookupColumn(descr, c.path), c.value)
+}
+
+func (c *comparison) Evaluate(rowGroup RowGroupStatistics) Truth {
+	descr := lookupColumn(rowGroup.Schema(), c.path)
+	i := rowGroup.Schema().ColumnIndex(c.path)
+	value := literalValue(descr, c.value)
+	result := Truth_UNKNOWN
+	if stats := rowGroup.ColumnStatistics(i); stats != nil {
+		result = compareStatistics(stats, c.op, value)
+	}
+	if c.op == opEq && result != Truth_FALSE && !bloomMayContain(rowGroup, i, value) {
+		result = Truth_FALSE
+	}
+	if (c.op == opEq || c.op == opNeq) && result != Truth_FALSE {
+		if dictionary := columnDictionary(rowGroup, i); dictionary != nil {
+			result = result.And(compareDictionary(dictionary, c.op, value))
+		}
+	}
+	return forRows(descr, result)
+}
+
+func (c *comparison) String() string {
+	return fmt.Sprintf("%s %s %v", c.path, compareOpNames[c.op], c.value)
+}
+
+// Whether the values of a column chunk compare to value. Min and max may be
+// bounds rather than the actual values, the outcome stays correct as the
+// actual values lie within them.
+func compareStatistics(stats *column.Statistics, op compareOp, value interface{}) Truth {
+	if stats.HasNullCount() && stats.NumValues() == 0 {
+		// Only nulls
+		return Truth_FALSE
+	}
+	if !stats.HasMinMax() {
+		return Truth_UNKNOWN
+	}
+	// Nulls never match, so all values match only without nulls
+	noNulls := stats.HasNullCount() && stats.NullCount() == 0
+	all := func(condition bool) Truth {
+		if condition && noNulls {
+			return Truth_TRUE
+		}
+		return Truth_UNKNOWN
+	}
+	toMin := stats.Compare(value, stats.Min())
+	toMax := stats.Compare(value, stats.Max())
+	switch op {
+	case opEq:
+		if toMin < 0 || toMax > 0 {
+			return Truth_FALSE
+		}
+		return all(toMin == 0 && toMax == 0)
+	case opNeq:
+		if toMin == 0 && toMax == 0 {
+			return Truth_FALSE
+		}
+		return all(toMin < 0 || toMax > 0)
+	case opLt:
+		if toMin <= 0 {
+			return Truth_FALSE
+		}
+		return all(toMax > 0)
+	case opLte:
+		if toMin < 0 {
+			return Truth_FALSE
+		}
+		return all(toMax >= 0)
+	case opGt:
+		if toMax >= 0 {
+			return Truth_FALSE
+		}
+		return all(toMin < 0)
+	case opGte:
+		if toMax > 0 {
+			return Truth_FALSE
+		}
+		return all(toMin <= 0)
+	}
+	panic(fmt.Errorf("Unknown comparison %d", op))
+}
+
+// column in (values...)
+type in struct {
+	path   string
+	values []interface{}
+}
+
+func In(path string, values ...interface{}) Predicate {
+	return &in{path: path, values: values}
+}
+
+func (p *in) Validate(descr *schema.SchemaDescriptor) {
+	column := lookupColumn(descr, p.path)
+	for _, value := range p.values {
+		literalValue(column, value)
+	}
+}
+
+func (p *in) Evaluate(rowGroup RowGroupStatistics) Truth {
+	descr := lookupColumn(rowGroup.Schema(), p.path)
+	i := rowGroup.Schema().ColumnIndex(p.path)
+	stats := rowGroup.ColumnStatistics(i)
+	var dictionary interface{}
+	dictionaryRead := false
+	result := Truth_FALSE
+	for _, v := range p.values {
+		value := literalValue(descr, v)
+		matches := Truth_UNKNOWN
+		if stats != nil {
+			matches = compareStatistics(stats, opEq, value)
+		}
+		if matches != Truth_FALSE && !bloomMayContain(rowGroup, i, value) {
+			matches = Truth_FALSE
+		}
+		if matches != Truth_FALSE {
+			if !dictionaryRead {
+				// Only read once a value is not ruled out otherwise
+				dictionary = columnDictionary(rowGroup, i)
+				dictionaryRead = true
+			}
+			if dictionary != nil {
+				matches = matches.And(compareDictionary(dictionary, opEq, value))
+			}
+		}
+		result = result.Or(matches)
+	}
+	return forRows(descr, result)
+}
+
+func (p *in) String() string {
+	values := make([]string, len(p.values))
+	for i, value := range p.values {
+		values[i] = fmt.Sprint(value)
+	}
+	return fmt.Sprintf("%s in (%s)", p.path, strings.Join(values, ", "))
+}
+
+// column is null, or column is not null
+type nullCheck struct {
+	path   string
+	isNull bool
+}
+
+func IsNull(path string) Predicate {
+	return &nullCheck{path: path, isNull: true}
+}
+
+func NotNull(path string) Predicate {
+	return &nullCheck{path: path, isNull: false}
+}
+
+func (p *nullCheck) Validate(descr *schema.SchemaDescriptor) {
+	lookupColumn(descr, p.path)
+}
+
+// Whether the Bloom filter of column i, if any, may hold the value. Floats
+// are not looked up, -0 and +0 are equal but do not hash the same.
+func bloomMayContain(rowGroup RowGroupStatistics, i int, value interface{}) bool {
+	source, ok := rowGroup.(BloomFilterSource)
+	if !ok {
+		return true
+	}
+	switch value.(type) {
+	case bool, float32, float64:
+		return true
+	}
+	filter := source.ColumnBloomFilter(i)
+	return filter == nil || filter.Find(value)
+}
+
+// The dictionary of column i, nil if the row group does not read them or
+// the column chunk is not fully dictionary encoded
+func columnDictionary(rowGroup RowGroupStatistics, i int) interface{} {
+	source, ok := rowGroup.(DictionarySource)
+	if !ok {
+		return nil
+	}
+	return source.ColumnDictionary(i)
+}
+
+// Whether the values of a dictionary holding all those of a column chunk
+// are, or differ from, value. An empty dictionary has only nulls.
+func compareDictionary(dictionary interface{}, op compareOp, value interface{}) Truth {
+	found, other := false, false
+	switch values := dictionary.(type) {
+	case []bool:
+		found, other = dictionaryHas(values, value.(bool))
+	case []int32:
+		found, other = dictionaryHas(values, value.(int32))
+	case []int64:
+		found, other = dictionaryHas(values, value.(int64))
+	case []ptype.Int96:
+		found, other = dictionaryHas(values, value.(ptype.Int96))
+	case []float32:
+		found, other = dictionaryHas(values, value.(float32))
+	case []float64:
+		found, other = dictionaryHas(values, value.(float64))
+	case []ptype.ByteArray:
+		target := value.(ptype.ByteArray)
+		for _, v := range values {
+			if bytes.Equal(v, target) {
+				found = true
+			} else {
+				other = true
+			}
+		}
+	case []ptype.FixedLenByteArray:
+		target := value.(ptype.FixedLenByteArray)
+		for _, v := range values {
+			if bytes.Equal(v, target) {
+				found = true
+			} else {
+				other = true
+			}
+		}
+	default:
+		return Truth_UNKNOWN
+	}
+	if op == opEq && !found || op == opNeq && !other {
+		return Truth_FALSE
+	}
+	return Truth_UNKNOWN
+}
+
+// Whether the values hold value, and any other value
+func dictionaryHas[T comparable](values []T, value T) (bool, bool) {
+	found, other := false, false
+	for _, v := range values {
+		if v == value {
+			found = true
+		} else {
+			other = true
+		}
+	}
+	return found, other
+}
+
+func (p *nullCheck) Evaluate(rowGroup RowGroupStatistics) Truth {
+	descr := lookupColumn(rowGroup.Schema(), p.path)
+	if descr.MaxDefinitionLevel() == 0 {
+		// Required all the way down
+		if p.isNull {
+			return Truth_FALSE
+		}
+		return Truth_TRUE
+	}
+	stats := rowGroup.ColumnStatistics(rowGroup.Schema().ColumnIndex(p.path))
+	if stats == nil || !stats.HasNullCount() {
+		return Truth_UNKNOWN
+	}
+	result := Truth_UNKNOWN
+	switch {
+	case stats.NullCount() == 0:
+		result = Truth_FALSE
+	case stats.NumValues() == 0:
+		result = Truth_TRUE
+	}
+	if !p.isNull {
+		result = result.Not()
+	}
+	return forRows(descr, result)
+}
+
+func (p *nullCheck) String() string {
+	if p.isNull {
+		return p.path + " is null"
+	}
+	return p.path + " is not null"
+}
+
+type and struct {
+	predicates []Predicate
+}
+
+// Matches the rows all the predicates match, everything without any
+func And(predicates ...Predicate) Predicate {
+	return &and{predicates: predicates}
+}
+
+func (p *and) Validate(descr *schema.SchemaDescriptor) {
+	for _, predicate := range p.predicates {
+		predicate.Validate(descr)
+	}
+}
+
+func (p *and) Evaluate(rowGroup RowGroupStatistics) Truth {
+	result := Truth_TRUE
+	for _, predicate := range p.predicates {
+		if result = result.And(predicate.Evaluate(rowGroup)); result == Truth_FALSE {
+			break
+		}
+	}
+	return result
+}
+
+func (p *and) String() string {
+	return joinPredicates(p.predicates, " and ", "true")
+}
+
+type or struct {
+	predicates []Predicate
+}
+
+// Matches the rows any of the predicates match, nothing without any
+func Or(predicates ...Predicate) Predicate {
+	return &or{predicates: predicates}
+}
+
+func (p *or) Validate(descr *schema.SchemaDescriptor) {
+	for _, predicate := range p.predicates {
+		predicate.Validate(descr)
+	}
+}
+
+func (p *or) Evaluate(rowGroup RowGroupStatistics) Truth {
+	result := Truth_FALSE
+	for _, predicate := range p.predicates {
+		if result = result.Or(predicate.Evaluate(rowGroup)); result == Truth_TRUE {
+			break
+		}
+	}
+	return result
+}
+
+func (p *or) String() string {
+	return joinPredicates(p.predicates, " or ", "false")
+}
+
+type not struct {
+	predicate Predicate
+}
+
+func Not(predicate Predicate) Predicate {
+	return &not{predicate: predicate}
+}
+
+func (p *not) Validate(descr *schema.SchemaDescriptor) {
+	p.predicate.Validate(descr)
+}
+
+func (p *not) Evaluate(rowGroup RowGroupStatistics) Truth {
+	return p.predicate.Evaluate(rowGroup).Not()
+}
+
+func (p *not) String() string {
+	return "not (" + p.predicate.String() + ")"
+}
+
+func joinPredicates(predicates []Predicate, separator string, empty string) string {
+	if len(predicates) == 0 {
+		return empty
+	}
+	parts := make([]string, len(predicates))
+	for i, predicate := range predicates {
+		parts[i] = "(" + predicate.String() + ")"
+	}
+	return strings.Join(parts, separator)
+}
+
+func lookupColumn(descr *schema.SchemaDescriptor, path string) *schema.ColumnDescriptor {
+	i := descr.ColumnIndex(path)
+	if i < 0 {
+		panic(fmt.Errorf("Filter on unknown column %s", path))
+	}
+	return descr.Column(i)
+}
+
+// A row of a repeated column has any number of values, those matching do
+// not tell about the others
+func forRows(descr *schema.ColumnDescriptor, result Truth) Truth {
+	if result == Truth_TRUE && descr.MaxRepetitionLevel() > 0 {
+		return Truth_UNKNOWN
+	}
+	return result
+}
@@ -0,0 +1,263 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+const predicateSchema = `message predicates {
+  required int32 id;
+  optional int64 score;
+  optional binary name (UTF8);
+  optional int32 unsigned (UINT_32);
+  optional int32 day (DATE);
+  optional int64 time (TIMESTAMP_MILLIS);
+  repeated int32 tags;
+  optional double ratio;
+}
+`
+
+// The statistics of a row group, without those of the columns left nil
+type rowGroupStatistics struct {
+	schema     *schema.SchemaDescriptor
+	statistics []*column.Statistics
+}
+
+func (r *rowGroupStatistics) Schema() *schema.SchemaDescriptor {
+	return r.schema
+}
+
+func (r *rowGroupStatistics) NumRows() int64 {
+	return 100
+}
+
+func (r *rowGroupStatistics) ColumnStatistics(i int) *column.Statistics {
+	return r.statistics[i]
+}
+
+func newRowGroupStatistics(values map[string]interface{}, nulls map[string]int64) *rowGroupStatistics {
+	descr := schema.NewSchemaDescriptor(&schema.MustParse(predicateSchema).Node)
+	r := &rowGroupStatistics{schema: descr, statistics: make([]*column.Statistics, descr.NumColumns())}
+	for path, value := range values {
+		i := descr.ColumnIndex(path)
+		r.statistics[i] = column.NewStatistics(descr.Column(i), false)
+		r.statistics[i].Update(value, nulls[path])
+	}
+	return r
+}
+
+func TestEvaluateComparisons(t *testing.T) {
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	rowGroup := newRowGroupStatistics(map[string]interface{}{
+		"id":       []int32{10, 20, 30},
+		"score":    []int64{5, 5},
+		"name":     []ptype.ByteArray{ptype.ByteArray("bob"), ptype.ByteArray("eve")},
+		"unsigned": []int32{1, -1},
+		"day":      []int32{18262, 18263},
+		"time":     []int64{day.UnixMilli()},
+		"tags":     []int32{1, 2},
+		"ratio":    []float64{},
+	}, map[string]int64{"score": 0, "name": 3, "ratio": 4})
+
+	tests := []struct {
+		predicate Predicate
+		truth     Truth
+	}{
+		{Eq("id", 5), Truth_FALSE},
+		{Eq("id", 20), Truth_UNKNOWN},
+		{Eq("id", int64(31)), Truth_FALSE},
+		{Neq("id", 20), Truth_UNKNOWN},
+		{Neq("id", 40), Truth_TRUE},
+		{Lt("id", 10), Truth_FALSE},
+		{Lt("id", 31), Truth_TRUE},
+		{Lte("id", 10), Truth_UNKNOWN},
+		{Gt("id", 30), Truth_FALSE},
+		{Gte("id", uint8(10)), Truth_TRUE},
+		{Eq("score", 5), Truth_TRUE},
+		{Neq("score", 5), Truth_FALSE},
+		// The nulls match no comparison
+		{Gte("name", "a"), Truth_UNKNOWN},
+		{Lt("name", "bob"), Truth_FALSE},
+		{Eq("name", []byte("carl")), Truth_UNKNOWN},
+		{Gt("name", "f"), Truth_FALSE},
+		// -1 is the largest UINT_32
+		{Gt("unsigned", uint32(1)<<31), Truth_UNKNOWN},
+		{Gt("unsigned", uint32(0xffffffff)), Truth_FALSE},
+		{Gte("unsigned", 1), Truth_TRUE},
+		{Lt("day", day), Truth_FALSE},
+		{Lte("day", day.AddDate(0, 0, 1)), Truth_TRUE},
+		{Eq("time", day), Truth_TRUE},
+		{Eq("time", day.Add(time.Millisecond)), Truth_FALSE},
+		// A repeated column holds for any of the values of a row
+		{Lt("tags", 3), Truth_UNKNOWN},
+		{Gt("tags", 2), Truth_FALSE},
+		// Only nulls
+		{Eq("ratio", 1.5), Truth_FALSE},
+		{IsNull("ratio"), Truth_TRUE},
+		{NotNull("ratio"), Truth_FALSE},
+		{IsNull("id"), Truth_FALSE},
+		{NotNull("id"), Truth_TRUE},
+		{IsNull("score"), Truth_FALSE},
+		{NotNull("name"), Truth_UNKNOWN},
+		{In("id", 1, 2, 3), Truth_FALSE},
+		{In("id", 1, 30), Truth_UNKNOWN},
+		{In("score", 4, 5), Truth_TRUE},
+		{And(Gte("id", 10), Eq("score", 5)), Truth_TRUE},
+		{And(Gte("id", 10), Eq("id", 2)), Truth_FALSE},
+		{And(), Truth_TRUE},
+		{Or(Eq("id", 1), Eq("id", 2)), Truth_FALSE},
+		{Or(Eq("id", 1), Eq("id", 20)), Truth_UNKNOWN},
+		{Or(Eq("id", 1), Gte("id", 0)), Truth_TRUE},
+		{Or(), Truth_FALSE},
+		{Not(Eq("id", 1)), Truth_TRUE},
+		{Not(Eq("id", 20)), Truth_UNKNOWN},
+	}
+	for _, test := range tests {
+		test.predicate.Validate(rowGroup.Schema())
+		if truth := test.predicate.Evaluate(rowGroup); truth != test.truth {
+			t.Errorf("%v: %s, want %s", test.predicate, TruthToString(truth),
+				TruthToString(test.truth))
+		}
+	}
+	if !CanMatch(nil, rowGroup) || CanMatch(Eq("id", 1), rowGroup) || !CanMatch(Eq("id", 10), rowGroup) {
+		t.Errorf("CanMatch does not follow the evaluation")
+	}
+}
+
+func TestEvaluateWithoutStatistics(t *testing.T) {
+	rowGroup := newRowGroupStatistics(map[string]interface{}{"id": []int32{1}}, nil)
+	for _, predicate := range []Predicate{Eq("score", 1), Lt("name", "x"), IsNull("score"),
+		In("day", 1), Not(Eq("score", 1))} {
+		if truth := predicate.Evaluate(rowGroup); truth != Truth_UNKNOWN {
+			t.Errorf("%v: %s without statistics", predicate, TruthToString(truth))
+		}
+	}
+}
+
+func validatePanic(predicate Predicate) (message string) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			message = fmt.Sprint(failure)
+		}
+	}()
+	predicate.Validate(schema.NewSchemaDescriptor(&schema.MustParse(predicateSchema).Node))
+	return ""
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		predicate Predicate
+		message   string
+	}{
+		{Eq("missing", 1), "unknown column missing"},
+		{Eq("id", "one"), "id"},
+		{Eq("id", int64(1)<<40), "id"},
+		{Gt("unsigned", -1), "unsigned"},
+		{Lt("name", 3), "name"},
+		{And(Eq("id", 1), IsNull("missing")), "unknown column missing"},
+		{In("id", 1, 2.5), "id"},
+	}
+	for _, test := range tests {
+		if message := validatePanic(test.predicate); !strings.Contains(message, test.message) {
+			t.Errorf("%v: %q, want %q", test.predicate, message, test.message)
+		}
+	}
+}
+
+func TestPredicateString(t *testing.T) {
+	predicate := Or(And(Gte("id", 1), Lt("id", 5)), Not(IsNull("name")), In("score", 1, 2))
+	expected := "((id >= 1) and (id < 5)) or (not (name is null)) or (score in (1, 2))"
+	if predicate.String() != expected {
+		t.Errorf("%q, want %q", predicate.String(), expected)
+	}
+}
+
+// A row group reading the dictionaries of its fully dictionary encoded
+// column chunks
+type dictionaryRowGroup struct {
+	*rowGroupStatistics
+	dictionaries map[int]interface{}
+	reads        int
+}
+
+func (d *dictionaryRowGroup) ColumnDictionary(i int) interface{} {
+	d.reads++
+	return d.dictionaries[i]
+}
+
+func TestEvaluateDictionaries(t *testing.T) {
+	rowGroup := &dictionaryRowGroup{
+		rowGroupStatistics: newRowGroupStatistics(map[string]interface{}{
+			"id": []int32{10, 20, 30},
+		}, nil),
+		dictionaries: map[int]interface{}{
+			0: []int32{10, 20, 30},
+			1: []int64{5},
+			2: []ptype.ByteArray{ptype.ByteArray("bob"), ptype.ByteArray("eve")},
+		},
+	}
+	tests := []struct {
+		predicate Predicate
+		truth     Truth
+	}{
+		{Eq("id", 15), Truth_FALSE},
+		{Eq("id", 20), Truth_UNKNOWN},
+		{Eq("score", 5), Truth_UNKNOWN},
+		{Eq("score", 6), Truth_FALSE},
+		{Neq("score", 5), Truth_FALSE},
+		{Neq("score", 6), Truth_UNKNOWN},
+		{Eq("name", "carl"), Truth_FALSE},
+		{Eq("name", "eve"), Truth_UNKNOWN},
+		{In("name", "al", "carl"), Truth_FALSE},
+		{In("name", "al", "bob"), Truth_UNKNOWN},
+		// The other columns are not fully dictionary encoded
+		{Eq("unsigned", 3), Truth_UNKNOWN},
+		// Only equality is looked up
+		{Gt("score", 5), Truth_UNKNOWN},
+	}
+	for _, test := range tests {
+		if truth := test.predicate.Evaluate(rowGroup); truth != test.truth {
+			t.Errorf("%v: %s, want %s", test.predicate, TruthToString(truth),
+				TruthToString(test.truth))
+		}
+	}
+
+	// The statistics rule the values out before the dictionary is read
+	rowGroup.reads = 0
+	if truth := In("id", 1, 2, 40).Evaluate(rowGroup); truth != Truth_FALSE || rowGroup.reads != 0 {
+		t.Errorf("in: %s after %d dictionary reads", TruthToString(truth), rowGroup.reads)
+	}
+	if truth := In("id", 1, 15, 25).Evaluate(rowGroup); truth != Truth_FALSE || rowGroup.reads != 1 {
+		t.Errorf("in: %s after %d dictionary reads", TruthToString(truth), rowGroup.reads)
+	}
+}
+
+func TestEvaluateTimestampsOutsideNanosecondRange(t *testing.T) {
+	early := time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)
+	rowGroup := newRowGroupStatistics(map[string]interface{}{
+		"time": []int64{early.UnixMilli(), late.UnixMilli()},
+	}, nil)
+	tests := []struct {
+		predicate Predicate
+		truth     Truth
+	}{
+		{Eq("time", early), Truth_UNKNOWN},
+		{Lt("time", early), Truth_FALSE},
+		{Gt("time", late), Truth_FALSE},
+		{Lte("time", late), Truth_TRUE},
+		{Gte("time", early.Add(-time.Millisecond)), Truth_TRUE},
+	}
+	for _, test := range tests {
+		if truth := test.predicate.Evaluate(rowGroup); truth != test.truth {
+			t.Errorf("%v: %s, want %s", test.predicate, TruthToString(truth),
+				TruthToString(test.truth))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ptype
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	secondsPerDay = 24 * 60 * 60
+	// Julian day of 1970-01-01, used by INT96 timestamps
+	julianUnixEpoch = 2440588
+)
+
+// The value t is stored as in a column of the given types: the days since
+// the epoch for DATE, the milliseconds or microseconds since the epoch for
+// TIMESTAMP_MILLIS / TIMESTAMP_MICROS, and the Julian day and nanoseconds of
+// the day for INT96. false for other columns, and for times out of the
+// range of the column.
+func TimeValue(t time.Time, physical Type, logical LogicalType) (interface{}, bool) {
+	seconds, days := t.Unix(), daysSinceEpoch(t)
+	switch {
+	case physical == Type_INT32 && logical == LogicalType_DATE:
+		if days >= math.MinInt32 && days <= math.MaxInt32 {
+			return int32(days), true
+		}
+	case physical == Type_INT64 && logical == LogicalType_TIMESTAMP_MILLIS:
+		if seconds > math.MinInt64/1000 && seconds < math.MaxInt64/1000 {
+			return t.UnixMilli(), true
+		}
+	case physical == Type_INT64 && logical == LogicalType_TIMESTAMP_MICROS:
+		if seconds > math.MinInt64/1000000 && seconds < math.MaxInt64/1000000 {
+			return t.UnixMicro(), true
+		}
+	case physical == Type_INT96:
+		if julian := days + julianUnixEpoch; julian >= 0 && julian <= math.MaxUint32 {
+			nanos := uint64((seconds-days*secondsPerDay)*int64(time.Second) + int64(t.Nanosecond()))
+			return Int96{uint32(nanos), uint32(nanos >> 32), uint32(julian)}, true
+		}
+	}
+	return nil, false
+}
+
+func daysSinceEpoch(t time.Time) int64 {
+	seconds := t.Unix()
+	days := seconds / secondsPerDay
+	if seconds%secondsPerDay < 0 {
+		days--
+	}
+	return days
+}
+
+// The UTC time of a DATE
+func DateToTime(days int32) time.Time {
+	return time.Unix(int64(days)*secondsPerDay, 0).UTC()
+}
+
+// The UTC time of an INT96 timestamp
+func Int96ToTime(value Int96) time.Time {
+	days := int64(value[2]) - julianUnixEpoch
+	nanos := int64(uint64(value[1])<<32 | uint64(value[0]))
+	return time.Unix(days*secondsPerDay, nanos).UTC()
+}
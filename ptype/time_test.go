@@ -0,0 +1,42 @@
+package ptype
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeValue(t *testing.T) {
+	before := time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC)
+	far := time.Date(2500, 1, 2, 3, 4, 5, 6000, time.UTC)
+	tests := []struct {
+		t        time.Time
+		physical Type
+		logical  LogicalType
+		value    interface{}
+	}{
+		{before, Type_INT32, LogicalType_DATE, int32(-1)},
+		{far, Type_INT64, LogicalType_TIMESTAMP_MILLIS, far.UnixMilli()},
+		{far, Type_INT64, LogicalType_TIMESTAMP_MICROS, far.UnixMicro()},
+		{before, Type_INT96, LogicalType_NONE, Int96{0x60966000, 0x4b4e, 2440587}},
+	}
+	for _, test := range tests {
+		if value, ok := TimeValue(test.t, test.physical, test.logical); !ok || value != test.value {
+			t.Errorf("%v as %s: %v, want %v", test.t, LogicalTypeToString(test.logical), value, test.value)
+		}
+	}
+	if _, ok := TimeValue(far, Type_INT64, LogicalType_NONE); ok {
+		t.Errorf("converted a time for a plain int64 column")
+	}
+	// Microseconds since the epoch only reach the year 294246
+	if _, ok := TimeValue(time.Date(300000, 1, 1, 0, 0, 0, 0, time.UTC), Type_INT64,
+		LogicalType_TIMESTAMP_MICROS); ok {
+		t.Errorf("converted a time out of the range of TIMESTAMP_MICROS")
+	}
+	value, _ := TimeValue(far, Type_INT96, LogicalType_NONE)
+	if back := Int96ToTime(value.(Int96)); !back.Equal(far) {
+		t.Errorf("INT96 read back as %v", back)
+	}
+	if day := DateToTime(-1); !day.Equal(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DATE -1 is %v", day)
+	}
+}
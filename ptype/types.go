@@ -71,3 +71,63 @@ const (
 	Compression_LZO          Compression = 3
 	Compression_BROTLI       Compression = 4
 )
+
+// Ordering of the values of a column, used for its min / max statistics
+type SortOrder int
+
+const (
+	SortOrder_SIGNED   SortOrder = 0
+	SortOrder_UNSIGNED SortOrder = 1
+	SortOrder_UNKNOWN  SortOrder = 2
+)
+
+// Physical value representations
+
+type Int96 [3]uint32
+
+type ByteArray []byte
+
+type FixedLenByteArray []byte
+
+// Number of bytes used by the PLAIN encoding of a fixed width type,
+// 0 for variable width types
+func TypeByteSize(t Type) int {
+	switch t {
+	case Type_BOOLEAN:
+		return 1
+	case Type_INT32, Type_FLOAT:
+		return 4
+	case Type_INT64, Type_DOUBLE:
+		return 8
+	case Type_INT96:
+		return 12
+	default:
+		return 0
+	}
+}
+
+// Sort order of the values of a column, the logical type takes precedence
+// over the physical type
+func GetSortOrder(logical LogicalType, primitive Type) SortOrder {
+	switch logical {
+	case LogicalType_NONE:
+	case LogicalType_INT_8, LogicalType_INT_16, LogicalType_INT_32, LogicalType_INT_64,
+		LogicalType_DATE, LogicalType_TIME_MILLIS, LogicalType_TIME_MICROS,
+		LogicalType_TIMESTAMP_MILLIS, LogicalType_TIMESTAMP_MICROS, LogicalType_DECIMAL:
+		return SortOrder_SIGNED
+	case LogicalType_UINT_8, LogicalType_UINT_16, LogicalType_UINT_32, LogicalType_UINT_64,
+		LogicalType_UTF8, LogicalType_ENUM, LogicalType_JSON, LogicalType_BSON:
+		return SortOrder_UNSIGNED
+	default:
+		// INTERVAL, MAP, LIST and MAP_KEY_VALUE have no defined order
+		return SortOrder_UNKNOWN
+	}
+	switch primitive {
+	case Type_BOOLEAN, Type_INT32, Type_INT64, Type_FLOAT, Type_DOUBLE:
+		return SortOrder_SIGNED
+	case Type_BYTE_ARRAY, Type_FIXED_LEN_BYTE_ARRAY:
+		return SortOrder_UNSIGNED
+	}
+	// INT96 timestamps are not ordered by their byte representation
+	return SortOrder_UNKNOWN
+}
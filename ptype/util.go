@@ -7,26 +7,157 @@ import (
 
 // Convert Thrift enums to / from parquet enums
 
-func (tp *Type) ToThrift() thrift.Type {
-	return thrift.Type(*tp)
+func (tp Type) ToThrift() thrift.Type {
+	return thrift.Type(tp)
 }
 
-func (tp *LogicalType) ToThrift() thrift.ConvertedType {
+func (tp LogicalType) ToThrift() thrift.ConvertedType {
 	// item 0 is NONE
-	if *tp == LogicalType_NONE {
+	if tp == LogicalType_NONE {
 		panic(fmt.Errorf("LogicalType::NONE cannot be convert back to thrift"))
 	}
-	return thrift.ConvertedType(*tp - 1)
+	return thrift.ConvertedType(tp - 1)
 }
 
-func (tp *Repetition) ToThrift() thrift.FieldRepetitionType {
-	return thrift.FieldRepetitionType(*tp)
+func (tp Repetition) ToThrift() thrift.FieldRepetitionType {
+	return thrift.FieldRepetitionType(tp)
 }
 
-func (tp *Encoding) ToThrift() thrift.Encoding {
-	return thrift.Encoding(*tp)
+func (tp Encoding) ToThrift() thrift.Encoding {
+	return thrift.Encoding(tp)
 }
 
-func (tp *Compression) ToThrift() thrift.CompressionCodec {
-	return thrift.CompressionCodec(*tp)
+func (tp Compression) ToThrift() thrift.CompressionCodec {
+	return thrift.CompressionCodec(tp)
+}
+
+// String representations, matching the names used by the parquet format
+
+func TypeToString(t Type) string {
+	switch t {
+	case Type_BOOLEAN:
+		return "BOOLEAN"
+	case Type_INT32:
+		return "INT32"
+	case Type_INT64:
+		return "INT64"
+	case Type_INT96:
+		return "INT96"
+	case Type_FLOAT:
+		return "FLOAT"
+	case Type_DOUBLE:
+		return "DOUBLE"
+	case Type_BYTE_ARRAY:
+		return "BYTE_ARRAY"
+	case Type_FIXED_LEN_BYTE_ARRAY:
+		return "FIXED_LEN_BYTE_ARRAY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func LogicalTypeToString(t LogicalType) string {
+	switch t {
+	case LogicalType_NONE:
+		return "NONE"
+	case LogicalType_UTF8:
+		return "UTF8"
+	case LogicalType_MAP:
+		return "MAP"
+	case LogicalType_MAP_KEY_VALUE:
+		return "MAP_KEY_VALUE"
+	case LogicalType_LIST:
+		return "LIST"
+	case LogicalType_ENUM:
+		return "ENUM"
+	case LogicalType_DECIMAL:
+		return "DECIMAL"
+	case LogicalType_DATE:
+		return "DATE"
+	case LogicalType_TIME_MILLIS:
+		return "TIME_MILLIS"
+	case LogicalType_TIME_MICROS:
+		return "TIME_MICROS"
+	case LogicalType_TIMESTAMP_MILLIS:
+		return "TIMESTAMP_MILLIS"
+	case LogicalType_TIMESTAMP_MICROS:
+		return "TIMESTAMP_MICROS"
+	case LogicalType_UINT_8:
+		return "UINT_8"
+	case LogicalType_UINT_16:
+		return "UINT_16"
+	case LogicalType_UINT_32:
+		return "UINT_32"
+	case LogicalType_UINT_64:
+		return "UINT_64"
+	case LogicalType_INT_8:
+		return "INT_8"
+	case LogicalType_INT_16:
+		return "INT_16"
+	case LogicalType_INT_32:
+		return "INT_32"
+	case LogicalType_INT_64:
+		return "INT_64"
+	case LogicalType_JSON:
+		return "JSON"
+	case LogicalType_BSON:
+		return "BSON"
+	case LogicalType_INTERVAL:
+		return "INTERVAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func RepetitionToString(r Repetition) string {
+	switch r {
+	case Repetition_REQUIRED:
+		return "REQUIRED"
+	case Repetition_OPTIONAL:
+		return "OPTIONAL"
+	case Repetition_REPEATED:
+		return "REPEATED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func EncodingToString(e Encoding) string {
+	switch e {
+	case Encoding_PLAIN:
+		return "PLAIN"
+	case Encoding_PLAIN_DICTIONARY:
+		return "PLAIN_DICTIONARY"
+	case Encoding_RLE:
+		return "RLE"
+	case Encoding_BIT_PACKED:
+		return "BIT_PACKED"
+	case Encoding_DELTA_BINARY_PACKED:
+		return "DELTA_BINARY_PACKED"
+	case Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		return "DELTA_LENGTH_BYTE_ARRAY"
+	case Encoding_DELTA_BYTE_ARRAY:
+		return "DELTA_BYTE_ARRAY"
+	case Encoding_RLE_DICTIONARY:
+		return "RLE_DICTIONARY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func CompressionToString(c Compression) string {
+	switch c {
+	case Compression_UNCOMPRESSED:
+		return "UNCOMPRESSED"
+	case Compression_SNAPPY:
+		return "SNAPPY"
+	case Compression_GZIP:
+		return "GZIP"
+	case Compression_LZO:
+		return "LZO"
+	case Compression_BROTLI:
+		return "BROTLI"
+	default:
+		return "UNKNOWN"
+	}
 }
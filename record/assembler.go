@@ -0,0 +1,301 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"reflect"
+	"unsafe"
+)
+
+// Record assembly, the inverse of shredding: the values of the leaf columns
+// are stitched back into records. The levels of the first leaf column below
+// a node tell whether the node is null (definition level below the node's)
+// and whether a repeated node has another element (repetition level equal
+// to the node's).
+//
+// An assembleNode mirrors one node of the file schema that is mapped to a
+// field of the Go type. File columns without a Go field are never read, and
+// Go fields without a file column are reset to their zero value.
+
+type assembleNode struct {
+	node *schema.Node
+	// Repetition level of the node
+	maxRep int16
+	// Definition level of the node once it is defined
+	maxDef   int16
+	column   int
+	setter   valueSetter
+	children []*assembleNode
+	// Struct field index of each child, or passThrough
+	fields []int
+	isMap  bool
+	// Struct fields without a matching column
+	missing []int
+	leaves  []int
+}
+
+type assembleBuilder struct {
+	schema *schema.SchemaDescriptor
+	// Fields of the columns left out of a projection are not required
+	projected bool
+}
+
+// Build the assembly plan of a struct type for the file schema, matching
+// fields by their column name
+func newAssemblePlan(descr *schema.SchemaDescriptor, t reflect.Type,
+	projected bool) *assembleNode {
+	builder := &assembleBuilder{schema: descr, projected: projected}
+	root := &assembleNode{node: descr.SchemaRoot(), column: -1}
+	builder.buildStruct(root, descr.GroupNode(), t)
+	return root
+}
+
+// Returns nil when no column below the node is mapped to the Go type
+func (b *assembleBuilder) build(node *schema.Node, t reflect.Type, maxRep int16,
+	maxDef int16, inList bool) *assembleNode {
+	n := &assembleNode{node: node, column: -1, maxRep: maxRep, maxDef: maxDef}
+	path := schema.ColumnPathFromNode(node).ToDotString()
+
+	switch node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		n.maxDef++
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	case ptype.Repetition_REPEATED:
+		n.maxDef++
+		n.maxRep++
+		switch {
+		case t.Kind() == reflect.Map:
+			n.isMap = true
+		case t.Kind() == reflect.Slice && !(node.IsPrimitive() &&
+			t.Elem().Kind() == reflect.Uint8):
+			t = t.Elem()
+		default:
+			panic(fmt.Errorf("Repeated column %s needs a slice or map, got %s", path, t))
+		}
+	}
+
+	if node.IsPrimitive() {
+		n.column = b.schema.ColumnIndex(path)
+		n.setter = newValueSetter(b.schema.Column(n.column), t)
+		n.leaves = []int{n.column}
+		return n
+	}
+
+	group := (*schema.GroupNode)(unsafe.Pointer(node))
+	switch {
+	case n.isMap:
+		// repeated group key_value { key; value; }
+		if group.FieldCount() != 2 {
+			panic(fmt.Errorf("Map column %s must have a key and a value", path))
+		}
+		key := b.build(group.Field(0), t.Key(), n.maxRep, n.maxDef, false)
+		value := b.build(group.Field(1), t.Elem(), n.maxRep, n.maxDef, false)
+		if key == nil || value == nil {
+			return nil
+		}
+		n.addChild(key, passThrough)
+		n.addChild(value, passThrough)
+	case node.LogicalType() == ptype.LogicalType_LIST,
+		node.LogicalType() == ptype.LogicalType_MAP:
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if group.FieldCount() != 1 || !group.Field(0).IsRepeated() {
+			panic(fmt.Errorf("%s column %s must have a single repeated child", path,
+				ptype.LogicalTypeToString(node.LogicalType())))
+		}
+		child := b.build(group.Field(0), t, n.maxRep, n.maxDef,
+			node.LogicalType() == ptype.LogicalType_LIST)
+		if child == nil {
+			return nil
+		}
+		n.addChild(child, passThrough)
+	case inList && node.IsRepeated() && group.FieldCount() == 1:
+		// The repeated group of a three level list holds the element
+		child := b.build(group.Field(0), t, n.maxRep, n.maxDef, false)
+		if child == nil {
+			return nil
+		}
+		n.addChild(child, passThrough)
+	default:
+		b.buildStruct(n, group, t)
+	}
+	if len(n.leaves) == 0 {
+		return nil
+	}
+	return n
+}
+
+func (b *assembleBuilder) buildStruct(n *assembleNode, group *schema.GroupNode,
+	t reflect.Type) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("Group %s needs a struct, got %s", group.Name(), t))
+	}
+	children := make(map[string]*schema.Node)
+	for i := 0; i < group.FieldCount(); i++ {
+		children[group.Field(i).Name()] = group.Field(i)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := schema.ParseFieldTag(field)
+		if tag.Skip {
+			continue
+		}
+		var child *assembleNode
+		if node, ok := children[tag.Name]; ok {
+			child = b.build(node, field.Type, n.maxRep, n.maxDef, false)
+		}
+		if child == nil {
+			if !isOptionalField(field, tag) && !b.projected {
+				panic(fmt.Errorf("Required field %s.%s has no column in the file", t,
+					field.Name))
+			}
+			n.missing = append(n.missing, i)
+			continue
+		}
+		n.addChild(child, i)
+	}
+}
+
+func (n *assembleNode) addChild(child *assembleNode, field int) {
+	n.children = append(n.children, child)
+	n.fields = append(n.fields, field)
+	n.leaves = append(n.leaves, child.leaves...)
+}
+
+// Fields that FromStruct maps to optional or repeated columns may be absent
+// from the file
+func isOptionalField(field reflect.StructField, tag schema.FieldTag) bool {
+	switch field.Type.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return true
+	}
+	return tag.Optional
+}
+
+// Levels of the next entry of the first leaf below the node
+func (n *assembleNode) peek(columns []*columnBuffer) (int16, int16) {
+	c := columns[n.leaves[0]]
+	if c.levelPos >= len(c.defLevels) {
+		panic(fmt.Errorf("Column %s ended before the row group",
+			c.descr.Path().ToDotString()))
+	}
+	return c.defLevels[c.levelPos], c.repLevels[c.levelPos]
+}
+
+// Whether the repeated node has another element
+func (n *assembleNode) repeats(columns []*columnBuffer) bool {
+	c := columns[n.leaves[0]]
+	return c.levelPos < len(c.repLevels) && c.repLevels[c.levelPos] == n.maxRep
+}
+
+// A null or empty value has a single entry in every leaf below
+func (n *assembleNode) skipNull(columns []*columnBuffer) {
+	for _, leaf := range n.leaves {
+		columns[leaf].levelPos++
+	}
+}
+
+// Assemble the value of this node into v, the value of the node's field
+func (n *assembleNode) read(columns []*columnBuffer, v reflect.Value) {
+	switch n.node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		if def, _ := n.peek(columns); def < n.maxDef {
+			n.skipNull(columns)
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		n.readContent(columns, v)
+	case ptype.Repetition_REPEATED:
+		empty := false
+		if def, _ := n.peek(columns); def < n.maxDef {
+			n.skipNull(columns)
+			empty = true
+		}
+		if n.isMap {
+			if !v.IsNil() {
+				for _, key := range v.MapKeys() {
+					v.SetMapIndex(key, reflect.Value{})
+				}
+			}
+			if empty {
+				return
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			for {
+				key := reflect.New(v.Type().Key()).Elem()
+				value := reflect.New(v.Type().Elem()).Elem()
+				n.children[0].read(columns, key)
+				n.children[1].read(columns, value)
+				v.SetMapIndex(key, value)
+				if !n.repeats(columns) {
+					return
+				}
+			}
+		}
+		// Reuse the backing array of the slice
+		if !v.IsNil() {
+			v.SetLen(0)
+		}
+		if empty {
+			return
+		}
+		for length := 0; ; length++ {
+			if length < v.Cap() {
+				v.SetLen(length + 1)
+			} else {
+				v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+			}
+			n.readContent(columns, v.Index(length))
+			if !n.repeats(columns) {
+				return
+			}
+		}
+	default:
+		n.readContent(columns, v)
+	}
+}
+
+func (n *assembleNode) readContent(columns []*columnBuffer, v reflect.Value) {
+	if n.column >= 0 {
+		c := columns[n.column]
+		n.setter(c, c.valuePos, v)
+		c.valuePos++
+		c.levelPos++
+		return
+	}
+	switch {
+	case v.Kind() == reflect.Slice && v.IsNil():
+		// A defined LIST is empty rather than null
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	case v.Kind() == reflect.Map && v.IsNil():
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	for _, field := range n.missing {
+		v.Field(field).Set(reflect.Zero(v.Field(field).Type()))
+	}
+	for i, child := range n.children {
+		if n.fields[i] == passThrough {
+			child.read(columns, v)
+		} else {
+			child.read(columns, v.Field(n.fields[i]))
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+// Levels and values of one leaf column, buffered until the row group is
+// written, or read from a row group until its records are assembled
+type columnBuffer struct {
+	descr     *schema.ColumnDescriptor
+	defLevels []int16
+	repLevels []int16
+
+	// Read cursor of the record assembly
+	levelPos int
+	valuePos int
+
+	boolValues   []bool
+	int32Values  []int32
+	int64Values  []int64
+	int96Values  []ptype.Int96
+	floatValues  []float32
+	doubleValues []float64
+	byteValues   []ptype.ByteArray
+	flbaValues   []ptype.FixedLenByteArray
+}
+
+func newColumnBuffer(descr *schema.ColumnDescriptor) *columnBuffer {
+	return &columnBuffer{descr: descr}
+}
+
+func (c *columnBuffer) NumLevels() int64 {
+	return int64(len(c.defLevels))
+}
+
+func (c *columnBuffer) AddLevels(defLevel int16, repLevel int16) {
+	c.defLevels = append(c.defLevels, defLevel)
+	c.repLevels = append(c.repLevels, repLevel)
+}
+
+// The typed slice of the buffered values
+func (c *columnBuffer) Values() interface{} {
+	switch c.descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		return c.boolValues
+	case ptype.Type_INT32:
+		return c.int32Values
+	case ptype.Type_INT64:
+		return c.int64Values
+	case ptype.Type_INT96:
+		return c.int96Values
+	case ptype.Type_FLOAT:
+		return c.floatValues
+	case ptype.Type_DOUBLE:
+		return c.doubleValues
+	case ptype.Type_BYTE_ARRAY:
+		return c.byteValues
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		return c.flbaValues
+	}
+	panic(fmt.Errorf("Unknown physical type: %d", c.descr.PhysicalType()))
+}
+
+// The i-th buffered value, in the type of the column
+func (c *columnBuffer) value(i int) interface{} {
+	switch c.descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		return c.boolValues[i]
+	case ptype.Type_INT32:
+		return c.int32Values[i]
+	case ptype.Type_INT64:
+		return c.int64Values[i]
+	case ptype.Type_INT96:
+		return c.int96Values[i]
+	case ptype.Type_FLOAT:
+		return c.floatValues[i]
+	case ptype.Type_DOUBLE:
+		return c.doubleValues[i]
+	case ptype.Type_BYTE_ARRAY:
+		return c.byteValues[i]
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		return c.flbaValues[i]
+	}
+	panic(fmt.Errorf("Unknown physical type: %d", c.descr.PhysicalType()))
+}
+
+// The value of each row of a non repeated column, nil for nulls
+func (c *columnBuffer) RowValues() []interface{} {
+	maxDef := c.descr.MaxDefinitionLevel()
+	values := make([]interface{}, len(c.defLevels))
+	j := 0
+	for i, level := range c.defLevels {
+		if level == maxDef {
+			values[i] = c.value(j)
+			j++
+		}
+	}
+	return values
+}
+
+// Reorder the buffered rows, row i becoming row order[i] of the buffer
+func (c *columnBuffer) PermuteRows(order []int) {
+	// The first level and value of each row, and the end of the last one
+	var levelStarts, valueStarts []int
+	numValues := 0
+	for i, level := range c.repLevels {
+		if level == 0 {
+			levelStarts = append(levelStarts, i)
+			valueStarts = append(valueStarts, numValues)
+		}
+		if c.defLevels[i] == c.descr.MaxDefinitionLevel() {
+			numValues++
+		}
+	}
+	levelStarts = append(levelStarts, len(c.repLevels))
+	valueStarts = append(valueStarts, numValues)
+
+	values := c.Values()
+	permuted := newColumnBuffer(c.descr)
+	for _, row := range order {
+		start, end := levelStarts[row], levelStarts[row+1]
+		permuted.defLevels = append(permuted.defLevels, c.defLevels[start:end]...)
+		permuted.repLevels = append(permuted.repLevels, c.repLevels[start:end]...)
+		permuted.appendValues(encoding.SliceValues(values, valueStarts[row], valueStarts[row+1]))
+	}
+	*c = *permuted
+}
+
+func (c *columnBuffer) Reset() {
+	c.levelPos = 0
+	c.valuePos = 0
+	c.defLevels = c.defLevels[:0]
+	c.repLevels = c.repLevels[:0]
+	c.boolValues = c.boolValues[:0]
+	c.int32Values = c.int32Values[:0]
+	c.int64Values = c.int64Values[:0]
+	c.int96Values = c.int96Values[:0]
+	c.floatValues = c.floatValues[:0]
+	c.doubleValues = c.doubleValues[:0]
+	c.byteValues = c.byteValues[:0]
+	c.flbaValues = c.flbaValues[:0]
+}
+
+// Write the buffered levels and values to the column writer of a row group
+func (c *columnBuffer) WriteBatch(writer *column.ColumnWriter) {
+	writer.WriteBatch(c.NumLevels(), c.defLevels, c.repLevels, c.Values())
+}
+
+// Drop the levels and values buffered after the first numLevels levels and
+// numValues values
+func (c *columnBuffer) truncate(numLevels int, numValues int) {
+	c.defLevels = c.defLevels[:numLevels]
+	c.repLevels = c.repLevels[:numLevels]
+	switch c.descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		c.boolValues = c.boolValues[:numValues]
+	case ptype.Type_INT32:
+		c.int32Values = c.int32Values[:numValues]
+	case ptype.Type_INT64:
+		c.int64Values = c.int64Values[:numValues]
+	case ptype.Type_INT96:
+		c.int96Values = c.int96Values[:numValues]
+	case ptype.Type_FLOAT:
+		c.floatValues = c.floatValues[:numValues]
+	case ptype.Type_DOUBLE:
+		c.doubleValues = c.doubleValues[:numValues]
+	case ptype.Type_BYTE_ARRAY:
+		c.byteValues = c.byteValues[:numValues]
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		c.flbaValues = c.flbaValues[:numValues]
+	}
+}
+
+// Append a value of the column's physical type
+func (c *columnBuffer) appendValue(value interface{}) {
+	switch v := value.(type) {
+	case bool:
+		c.boolValues = append(c.boolValues, v)
+	case int32:
+		c.int32Values = append(c.int32Values, v)
+	case int64:
+		c.int64Values = append(c.int64Values, v)
+	case ptype.Int96:
+		c.int96Values = append(c.int96Values, v)
+	case float32:
+		c.floatValues = append(c.floatValues, v)
+	case float64:
+		c.doubleValues = append(c.doubleValues, v)
+	case ptype.ByteArray:
+		c.byteValues = append(c.byteValues, v)
+	case ptype.FixedLenByteArray:
+		c.flbaValues = append(c.flbaValues, v)
+	}
+}
+
+func (c *columnBuffer) appendValues(values interface{}) {
+	switch v := values.(type) {
+	case []bool:
+		c.boolValues = append(c.boolValues, v...)
+	case []int32:
+		c.int32Values = append(c.int32Values, v...)
+	case []int64:
+		c.int64Values = append(c.int64Values, v...)
+	case []ptype.Int96:
+		c.int96Values = append(c.int96Values, v...)
+	case []float32:
+		c.floatValues = append(c.floatValues, v...)
+	case []float64:
+		c.doubleValues = append(c.doubleValues, v...)
+	case []ptype.ByteArray:
+		c.byteValues = append(c.byteValues, v...)
+	case []ptype.FixedLenByteArray:
+		c.flbaValues = append(c.flbaValues, v...)
+	}
+}
+
+// Read all levels and values of a column chunk
+func (c *columnBuffer) ReadBatch(reader *column.ColumnReader) {
+	c.ReadRanges(reader, nil, nil)
+}
+
+// Read the levels and values of the rows in ranges, from a reader over the
+// pages holding them with pageRows the rows of each page. Nil ranges read
+// everything.
+func (c *columnBuffer) ReadRanges(reader *column.ColumnReader, pageRows []file.RowRange,
+	ranges []file.RowRange) {
+	batchSize := int64(column.DEFAULT_WRITE_BATCH_SIZE)
+	defLevels := make([]int16, batchSize)
+	repLevels := make([]int16, batchSize)
+	values := encoding.MakeValues(c.descr.PhysicalType(), int(batchSize))
+	// The row of the current level, and the page and range it falls in
+	var row int64
+	page, current := 0, 0
+	if pageRows != nil {
+		row = pageRows[0].Start - 1
+	}
+	for {
+		levelsRead, valuesRead := reader.ReadBatch(batchSize, defLevels, repLevels, values)
+		if levelsRead == 0 {
+			return
+		}
+		// Required columns have no levels, and flat ones no repetition levels
+		if c.descr.MaxDefinitionLevel() == 0 {
+			for i := range defLevels[:levelsRead] {
+				defLevels[i] = 0
+			}
+		}
+		if c.descr.MaxRepetitionLevel() == 0 {
+			for i := range repLevels[:levelsRead] {
+				repLevels[i] = 0
+			}
+		}
+		if ranges == nil {
+			c.defLevels = append(c.defLevels, defLevels[:levelsRead]...)
+			c.repLevels = append(c.repLevels, repLevels[:levelsRead]...)
+			c.appendValues(encoding.SliceValues(values, 0, int(valuesRead)))
+			continue
+		}
+		// Kept values are appended by runs
+		valuePos, runStart := 0, -1
+		flush := func() {
+			if runStart >= 0 {
+				c.appendValues(encoding.SliceValues(values, runStart, valuePos))
+				runStart = -1
+			}
+		}
+		for i := range defLevels[:levelsRead] {
+			if repLevels[i] == 0 {
+				row++
+				if row >= pageRows[page].End {
+					page++
+					row = pageRows[page].Start
+				}
+				for current < len(ranges) && ranges[current].End <= row {
+					current++
+				}
+			}
+			keep := current < len(ranges) && ranges[current].Start <= row
+			if keep {
+				c.AddLevels(defLevels[i], repLevels[i])
+			}
+			if defLevels[i] == c.descr.MaxDefinitionLevel() {
+				if !keep {
+					flush()
+				} else if runStart < 0 {
+					runStart = valuePos
+				}
+				valuePos++
+			}
+		}
+		flush()
+	}
+}
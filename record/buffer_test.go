@@ -0,0 +1,134 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/filter"
+)
+
+type pageRow struct {
+	I int32   `parquet:"i"`
+	S string  `parquet:"s"`
+	L []int32 `parquet:"l"`
+}
+
+// Write rows in small pages, so that every column chunk has several
+func writePageRows(numRows int) []byte {
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().DisableDictionary().
+		DataPagesize(64).WriteBatchSize(5).MaxRowGroupLength(200).Build()
+	writer := NewWriter[pageRow](&buffer, properties)
+	for i := 0; i < numRows; i++ {
+		row := pageRow{I: int32(i), S: fmt.Sprintf("s%04d", i)}
+		// Rows of 0 to 6 elements
+		for j := 0; j < i%7; j++ {
+			row.L = append(row.L, int32(i*10+j))
+		}
+		writer.Write(row)
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func TestReadRangesReturnsCandidateRows(t *testing.T) {
+	data := writePageRows(500)
+	all := NewReader[pageRow](bytes.NewReader(data), int64(len(data))).ReadAll()
+	if len(all) != 500 {
+		t.Fatalf("read %d rows, want 500", len(all))
+	}
+
+	reader := file.NewParquetFileReaderOpen(bytes.NewReader(data), int64(len(data)))
+	for i := 0; i < reader.NumColumns(); i++ {
+		offsetIndex := reader.RowGroup(0).OffsetIndex(i)
+		if offsetIndex == nil || len(offsetIndex.PageLocations) < 3 {
+			t.Fatalf("column %d should have several pages", i)
+		}
+	}
+
+	tests := []struct {
+		predicate filter.Predicate
+		matches   func(row pageRow) bool
+	}{
+		{filter.And(filter.Gte("i", 120), filter.Lt("i", 140)),
+			func(row pageRow) bool { return row.I >= 120 && row.I < 140 }},
+		{filter.Eq("s", "s0321"), func(row pageRow) bool { return row.S == "s0321" }},
+		{filter.Eq("l.list.element", 4504), func(row pageRow) bool { return row.I == 450 }},
+		{filter.Or(filter.Lt("i", 3), filter.Gte("i", 490)),
+			func(row pageRow) bool { return row.I < 3 || row.I >= 490 }},
+	}
+	for _, test := range tests {
+		predicate := test.predicate
+		// The candidate ranges of each row group, from the file offset
+		var expected []pageRow
+		var offset int64
+		for i := 0; i < reader.NumRowGroups(); i++ {
+			rowGroup := reader.RowGroup(i)
+			ranges := rowGroup.CandidateRowRanges(predicate)
+			var candidates int64
+			for _, rows := range ranges {
+				expected = append(expected, all[offset+rows.Start:offset+rows.End]...)
+				candidates += rows.End - rows.Start
+			}
+			if candidates == rowGroup.NumRows() {
+				t.Errorf("%v: no page of row group %d was skipped", predicate, i)
+			}
+			offset += rowGroup.NumRows()
+		}
+		if len(expected) == 0 {
+			t.Errorf("%v: no candidate rows", predicate)
+		}
+
+		filtered := NewReader[pageRow](bytes.NewReader(data), int64(len(data)))
+		filtered.SetFilter(predicate)
+		rows := filtered.ReadAll()
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("%v: read %v, want %v", predicate, rows, expected)
+		}
+		// The candidates hold every matching row
+		read := make(map[int32]bool)
+		for _, row := range rows {
+			read[row.I] = true
+		}
+		for _, row := range all {
+			if test.matches(row) && !read[row.I] {
+				t.Errorf("%v: row %d was skipped", predicate, row.I)
+			}
+		}
+	}
+}
+
+func TestColumnRangesSelectsPages(t *testing.T) {
+	data := writePageRows(200)
+	reader := file.NewParquetFileReaderOpen(bytes.NewReader(data), int64(len(data)))
+	rowGroup := reader.RowGroup(0)
+	ranges := []file.RowRange{{Start: 50, End: 60}, {Start: 150, End: 151}}
+	for i := 0; i < rowGroup.NumColumns(); i++ {
+		offsetIndex := rowGroup.OffsetIndex(i)
+		_, pageRows := rowGroup.ColumnRanges(i, ranges)
+		// The pages overlapping a range, in order
+		var expected []file.RowRange
+		for j, location := range offsetIndex.PageLocations {
+			end := rowGroup.NumRows()
+			if j+1 < len(offsetIndex.PageLocations) {
+				end = offsetIndex.PageLocations[j+1].FirstRowIndex
+			}
+			for _, rows := range ranges {
+				if rows.Start < end && location.FirstRowIndex < rows.End {
+					expected = append(expected, file.RowRange{Start: location.FirstRowIndex, End: end})
+					break
+				}
+			}
+		}
+		if !reflect.DeepEqual(pageRows, expected) {
+			t.Errorf("column %d: pages %v, want %v", i, pageRows, expected)
+		}
+		if len(expected) == len(offsetIndex.PageLocations) {
+			t.Errorf("column %d: no page was skipped", i)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Appends a Go value to the buffer of a leaf column
+type valueAppender func(c *columnBuffer, v reflect.Value)
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+// Choose how Go values of type t are stored into the given leaf column
+func newValueAppender(descr *schema.ColumnDescriptor, t reflect.Type) valueAppender {
+	kind := t.Kind()
+	physical, logical := descr.PhysicalType(), descr.LogicalType()
+	if _, ok := ptype.TimeValue(time.Unix(0, 0), physical, logical); ok && t == timeType {
+		return func(c *columnBuffer, v reflect.Value) {
+			value, ok := ptype.TimeValue(v.Interface().(time.Time), physical, logical)
+			if !ok {
+				panic(fmt.Errorf("Time %v is out of the range of column %s", v.Interface(),
+					descr.Path().ToDotString()))
+			}
+			c.appendValue(value)
+		}
+	}
+	switch physical {
+	case ptype.Type_BOOLEAN:
+		if kind == reflect.Bool {
+			return func(c *columnBuffer, v reflect.Value) {
+				c.boolValues = append(c.boolValues, v.Bool())
+			}
+		}
+	case ptype.Type_INT32:
+		switch {
+		case isIntKind(kind):
+			return func(c *columnBuffer, v reflect.Value) {
+				c.int32Values = append(c.int32Values, int32(v.Int()))
+			}
+		case isUintKind(kind):
+			return func(c *columnBuffer, v reflect.Value) {
+				c.int32Values = append(c.int32Values, int32(uint32(v.Uint())))
+			}
+		}
+	case ptype.Type_INT64:
+		switch {
+		case isIntKind(kind):
+			return func(c *columnBuffer, v reflect.Value) {
+				c.int64Values = append(c.int64Values, v.Int())
+			}
+		case isUintKind(kind):
+			return func(c *columnBuffer, v reflect.Value) {
+				c.int64Values = append(c.int64Values, int64(v.Uint()))
+			}
+		}
+	case ptype.Type_INT96:
+		if t.ConvertibleTo(reflect.TypeOf(ptype.Int96{})) {
+			return func(c *columnBuffer, v reflect.Value) {
+				value := v.Convert(reflect.TypeOf(ptype.Int96{})).Interface().(ptype.Int96)
+				c.int96Values = append(c.int96Values, value)
+			}
+		}
+	case ptype.Type_FLOAT:
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			return func(c *columnBuffer, v reflect.Value) {
+				c.floatValues = append(c.floatValues, float32(v.Float()))
+			}
+		}
+	case ptype.Type_DOUBLE:
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			return func(c *columnBuffer, v reflect.Value) {
+				c.doubleValues = append(c.doubleValues, v.Float())
+			}
+		}
+	case ptype.Type_BYTE_ARRAY:
+		switch {
+		case kind == reflect.String:
+			return func(c *columnBuffer, v reflect.Value) {
+				c.byteValues = append(c.byteValues, ptype.ByteArray(v.String()))
+			}
+		case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return func(c *columnBuffer, v reflect.Value) {
+				// Copy, the caller may reuse the slice once Write returns
+				value := append(ptype.ByteArray(nil), v.Bytes()...)
+				c.byteValues = append(c.byteValues, value)
+			}
+		}
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		length := int(descr.TypeLength())
+		switch {
+		case kind == reflect.Array && t.Elem().Kind() == reflect.Uint8 && t.Len() == length:
+			return func(c *columnBuffer, v reflect.Value) {
+				value := make(ptype.FixedLenByteArray, length)
+				reflect.Copy(reflect.ValueOf(value), v)
+				c.flbaValues = append(c.flbaValues, value)
+			}
+		case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return func(c *columnBuffer, v reflect.Value) {
+				if v.Len() != length {
+					panic(fmt.Errorf("Column %s expects %d bytes, got %d",
+						descr.Path().ToDotString(), length, v.Len()))
+				}
+				value := append(ptype.FixedLenByteArray(nil), v.Bytes()...)
+				c.flbaValues = append(c.flbaValues, value)
+			}
+		}
+	}
+	panic(fmt.Errorf("Cannot store Go type %s in column %s of type %s (%s)", t,
+		descr.Path().ToDotString(), ptype.TypeToString(descr.PhysicalType()),
+		ptype.LogicalTypeToString(descr.LogicalType())))
+}
+
+// Stores the i-th value of a leaf column into a Go value
+type valueSetter func(c *columnBuffer, i int, v reflect.Value)
+
+// Choose how values of the given leaf column are stored into Go values of
+// type t, the inverse of newValueAppender
+func newValueSetter(descr *schema.ColumnDescriptor, t reflect.Type) valueSetter {
+	kind := t.Kind()
+	switch descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		if kind == reflect.Bool {
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetBool(c.boolValues[i])
+			}
+		}
+	case ptype.Type_INT32:
+		switch {
+		case isIntKind(kind):
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetInt(int64(c.int32Values[i]))
+			}
+		case isUintKind(kind):
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetUint(uint64(uint32(c.int32Values[i])))
+			}
+		case t == timeType && descr.LogicalType() == ptype.LogicalType_DATE:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.Set(reflect.ValueOf(ptype.DateToTime(c.int32Values[i])))
+			}
+		}
+	case ptype.Type_INT64:
+		switch {
+		case isIntKind(kind):
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetInt(c.int64Values[i])
+			}
+		case isUintKind(kind):
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetUint(uint64(c.int64Values[i]))
+			}
+		case t == timeType && descr.LogicalType() == ptype.LogicalType_TIMESTAMP_MILLIS:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.Set(reflect.ValueOf(time.UnixMilli(c.int64Values[i]).UTC()))
+			}
+		case t == timeType && descr.LogicalType() == ptype.LogicalType_TIMESTAMP_MICROS:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.Set(reflect.ValueOf(time.UnixMicro(c.int64Values[i]).UTC()))
+			}
+		}
+	case ptype.Type_INT96:
+		switch {
+		case t == timeType:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.Set(reflect.ValueOf(ptype.Int96ToTime(c.int96Values[i])))
+			}
+		case reflect.TypeOf(ptype.Int96{}).ConvertibleTo(t):
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.Set(reflect.ValueOf(c.int96Values[i]).Convert(t))
+			}
+		}
+	case ptype.Type_FLOAT:
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetFloat(float64(c.floatValues[i]))
+			}
+		}
+	case ptype.Type_DOUBLE:
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetFloat(c.doubleValues[i])
+			}
+		}
+	case ptype.Type_BYTE_ARRAY:
+		switch {
+		case kind == reflect.String:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetString(string(c.byteValues[i]))
+			}
+		case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				// Copy into the existing slice, the values alias the page buffers
+				v.SetBytes(append(v.Bytes()[:0], c.byteValues[i]...))
+			}
+		}
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		length := int(descr.TypeLength())
+		switch {
+		case kind == reflect.Array && t.Elem().Kind() == reflect.Uint8 && t.Len() == length:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				reflect.Copy(v, reflect.ValueOf(c.flbaValues[i]))
+			}
+		case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return func(c *columnBuffer, i int, v reflect.Value) {
+				v.SetBytes(append(v.Bytes()[:0], c.flbaValues[i]...))
+			}
+		}
+	}
+	panic(fmt.Errorf("Cannot read column %s of type %s (%s) into Go type %s",
+		descr.Path().ToDotString(), ptype.TypeToString(descr.PhysicalType()),
+		ptype.LogicalTypeToString(descr.LogicalType()), t))
+}
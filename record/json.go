@@ -0,0 +1,108 @@
+package record
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"io"
+)
+
+// Rows convert to JSON with encoding/json: binary values become base64
+// strings and INT96 values arrays of three numbers. Decoding reverses that
+// using the schema, numbers are converted to the column types without
+// going through float64.
+
+// RowJSONDecoder reads a stream of JSON objects as Rows of a schema
+type RowJSONDecoder struct {
+	decoder *json.Decoder
+	plan    *rowNode
+}
+
+func NewRowJSONDecoder(descr *schema.SchemaDescriptor, r io.Reader) *RowJSONDecoder {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &RowJSONDecoder{decoder: decoder, plan: newRowPlan(descr)}
+}
+
+// Whether there is another object in the stream
+func (d *RowJSONDecoder) More() bool {
+	return d.decoder.More()
+}
+
+func (d *RowJSONDecoder) Decode() Row {
+	var value interface{}
+	if err := d.decoder.Decode(&value); err != nil {
+		panic(fmt.Errorf("Invalid JSON row: %v", err))
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		panic(fmt.Errorf("JSON row must be an object, got %T", value))
+	}
+	return d.plan.contentFromJSON(value).(Row)
+}
+
+// Decode a single JSON object as a Row of the schema
+func RowFromJSON(descr *schema.SchemaDescriptor, data []byte) Row {
+	return NewRowJSONDecoder(descr, bytes.NewReader(data)).Decode()
+}
+
+func RowToJSON(row Row) []byte {
+	data, err := json.Marshal(row)
+	if err != nil {
+		panic(fmt.Errorf("Cannot convert row to JSON: %v", err))
+	}
+	return data
+}
+
+func (n *rowNode) fromJSON(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if !n.node.IsRepeated() {
+		return n.contentFromJSON(v)
+	}
+	if n.kind == rowMapEntries {
+		entries := Row{}
+		for key, value := range rowFields(n, v) {
+			entries[key] = n.children[1].fromJSON(value)
+		}
+		return entries
+	}
+	elements := rowElements(n, v)
+	result := make([]interface{}, len(elements))
+	for i, element := range elements {
+		result[i] = n.contentFromJSON(element)
+	}
+	return result
+}
+
+func (n *rowNode) contentFromJSON(v interface{}) interface{} {
+	switch n.kind {
+	case rowLeaf:
+		if text, ok := v.(string); ok && !isStringColumn(n.descr) &&
+			(n.descr.PhysicalType() == ptype.Type_BYTE_ARRAY ||
+				n.descr.PhysicalType() == ptype.Type_FIXED_LEN_BYTE_ARRAY) {
+			value, err := base64.StdEncoding.DecodeString(text)
+			if err != nil {
+				panic(fmt.Errorf("Column %s expects base64 data: %v", n.path(), err))
+			}
+			v = value
+		}
+		return rowLeafValue(n.descr, v)
+	case rowPassThrough:
+		return n.children[0].fromJSON(v)
+	default:
+		fields := rowFields(n, v)
+		row := make(Row, len(fields))
+		for key, value := range fields {
+			child := n.child(key)
+			if child == nil {
+				panic(fmt.Errorf("Group %s has no field %s", n.path(), key))
+			}
+			row[key] = child.fromJSON(value)
+		}
+		return row
+	}
+}
@@ -0,0 +1,244 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/filter"
+	"github.com/zenixls2/goparquet/schema"
+	"io"
+	"reflect"
+)
+
+// The columns of the row group being read, shared by Reader and RowReader
+type bufferedReader struct {
+	fileReader *file.ParquetFileReader
+	// Indexed by column of the projected schema, nil for the columns that
+	// are not read
+	columns  []*columnBuffer
+	rowGroup int
+	// Records of the current row group not read yet
+	numRows int64
+}
+
+func newBufferedReader(fileReader *file.ParquetFileReader, leaves []int) *bufferedReader {
+	r := &bufferedReader{fileReader: fileReader}
+	r.setColumns(leaves)
+	return r
+}
+
+func (r *bufferedReader) setColumns(leaves []int) {
+	descr := r.Schema()
+	r.columns = make([]*columnBuffer, descr.NumColumns())
+	for _, leaf := range leaves {
+		r.columns[leaf] = newColumnBuffer(descr.Column(leaf))
+	}
+}
+
+// The schema of the file, or of its projected columns
+func (r *bufferedReader) Schema() *schema.SchemaDescriptor {
+	return r.fileReader.ProjectedSchema()
+}
+
+// Total number of records in the file
+func (r *bufferedReader) NumRows() int64 {
+	return r.fileReader.NumRows()
+}
+
+// The key / value metadata of the file footer
+func (r *bufferedReader) KeyValueMetadata() *file.KeyValueMetadata {
+	return r.fileReader.KeyValueMetadata()
+}
+
+// Read the columns of the next non empty row group the filter allows
+func (r *bufferedReader) nextRowGroup() bool {
+	for r.numRows == 0 {
+		if r.rowGroup >= r.fileReader.NumRowGroups() {
+			return false
+		}
+		rowGroup := r.fileReader.RowGroup(r.rowGroup)
+		r.rowGroup++
+		if !r.fileReader.RowGroupMatches(r.rowGroup - 1) {
+			continue
+		}
+		if r.fileReader.Filter == nil || !r.hasOffsetIndexes(rowGroup) {
+			// The chunks of the columns read are fetched at once
+			var fileColumns []int
+			for i, buffer := range r.columns {
+				if buffer != nil {
+					fileColumns = append(fileColumns, r.fileReader.ProjectedColumn(i))
+				}
+			}
+			rowGroup = r.fileReader.PrefetchRowGroup(r.rowGroup-1, fileColumns)
+			r.numRows = rowGroup.NumRows()
+			for i, buffer := range r.columns {
+				if buffer != nil {
+					buffer.Reset()
+					buffer.ReadBatch(rowGroup.Column(r.fileReader.ProjectedColumn(i)))
+				}
+			}
+			continue
+		}
+		// Only read the pages that may hold matching rows
+		ranges := rowGroup.CandidateRowRanges(r.fileReader.Filter)
+		for _, rows := range ranges {
+			r.numRows += rows.End - rows.Start
+		}
+		if r.numRows == 0 {
+			continue
+		}
+		for i, buffer := range r.columns {
+			if buffer != nil {
+				buffer.Reset()
+				reader, pageRows := rowGroup.ColumnRanges(r.fileReader.ProjectedColumn(i), ranges)
+				buffer.ReadRanges(reader, pageRows, ranges)
+			}
+		}
+	}
+	return true
+}
+
+// Whether all the columns read have an offset index to select pages with
+func (r *bufferedReader) hasOffsetIndexes(rowGroup *file.RowGroupReader) bool {
+	for i, buffer := range r.columns {
+		if buffer != nil && rowGroup.OffsetIndex(r.fileReader.ProjectedColumn(i)) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Skip the row groups, and the pages when the file has page indexes, whose
+// statistics prove no row matches the predicate. The other records are
+// read, matching or not.
+func (r *bufferedReader) SetFilter(predicate filter.Predicate) {
+	r.fileReader.SetFilter(predicate)
+}
+
+// Also skip the row groups whose fully dictionary encoded column chunks
+// have no value an eq, in or neq predicate of the filter accepts, reading
+// their dictionary pages
+func (r *bufferedReader) SetDictionaryFilter(enabled bool) {
+	r.fileReader.SetDictionaryFilter(enabled)
+}
+
+// Close the file, and the source if it is an io.Closer
+func (r *bufferedReader) Close() {
+	r.fileReader.Close()
+}
+
+// Reader reads the records of a parquet file into Go structs of type T (or
+// pointers to them). Struct fields are matched to columns by name as in
+// schema.FromStruct; columns without a field are not read, and optional
+// fields without a column are left at their zero value.
+//
+// A row group is read at a time and its records are assembled on demand.
+type Reader[T any] struct {
+	*bufferedReader
+	plan *assembleNode
+}
+
+func NewReader[T any](source io.ReaderAt, size int64) *Reader[T] {
+	return NewFileReader[T](file.NewParquetFileReaderOpen(source, size))
+}
+
+func NewFileReader[T any](fileReader *file.ParquetFileReader) *Reader[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	plan := newAssemblePlan(fileReader.ProjectedSchema(), t, fileReader.Projection != nil)
+	return &Reader[T]{
+		bufferedReader: newBufferedReader(fileReader, plan.leaves),
+		plan:           plan,
+	}
+}
+
+// Only read the columns at the paths, or under them for groups, the fields
+// of the other columns are left at their zero value. Must be called before
+// reading.
+func (r *Reader[T]) SetProjection(paths ...*schema.ColumnPath) {
+	r.fileReader.SetProjection(paths...)
+	r.plan = newAssemblePlan(r.Schema(), reflect.TypeOf((*T)(nil)).Elem(), true)
+	r.setColumns(r.plan.leaves)
+}
+
+// Read up to len(rows) records into rows, reusing the memory they already
+// reference (pointers, slices and maps), and return the number of records
+// read. Returns 0 once all records have been read.
+func (r *Reader[T]) Read(rows []T) int {
+	for i := range rows {
+		if !r.nextRowGroup() {
+			return i
+		}
+		v := reflect.ValueOf(&rows[i]).Elem()
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		r.plan.readContent(r.columns, v)
+		r.numRows--
+	}
+	return len(rows)
+}
+
+// Read all remaining records
+func (r *Reader[T]) ReadAll() []T {
+	var rows []T
+	for r.nextRowGroup() {
+		start := len(rows)
+		rows = append(rows, make([]T, r.numRows)...)
+		if n := r.Read(rows[start:]); n != len(rows)-start {
+			panic(fmt.Errorf("Row group ended after %d of %d records", n, len(rows)-start))
+		}
+	}
+	return rows
+}
+
+// RowReader reads the records of a parquet file of any schema as Rows.
+type RowReader struct {
+	*bufferedReader
+	plan *rowNode
+}
+
+func NewRowReader(source io.ReaderAt, size int64) *RowReader {
+	return NewFileRowReader(file.NewParquetFileReaderOpen(source, size))
+}
+
+func NewFileRowReader(fileReader *file.ParquetFileReader) *RowReader {
+	plan := newRowPlan(fileReader.ProjectedSchema())
+	return &RowReader{
+		bufferedReader: newBufferedReader(fileReader, plan.leaves),
+		plan:           plan,
+	}
+}
+
+// Only read the columns at the paths, or under them for groups, the Rows
+// only hold their fields. Must be called before reading.
+func (r *RowReader) SetProjection(paths ...*schema.ColumnPath) {
+	r.fileReader.SetProjection(paths...)
+	r.plan = newRowPlan(r.Schema())
+	r.setColumns(r.plan.leaves)
+}
+
+// Read up to len(rows) records into rows and return the number of records
+// read. Returns 0 once all records have been read.
+func (r *RowReader) Read(rows []Row) int {
+	for i := range rows {
+		if !r.nextRowGroup() {
+			return i
+		}
+		rows[i] = r.plan.readContent(r.columns).(Row)
+		r.numRows--
+	}
+	return len(rows)
+}
+
+// Read all remaining records
+func (r *RowReader) ReadAll() []Row {
+	var rows []Row
+	for r.nextRowGroup() {
+		start := len(rows)
+		rows = append(rows, make([]Row, r.numRows)...)
+		r.Read(rows[start:])
+	}
+	return rows
+}
@@ -0,0 +1,248 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+type roundTripInner struct {
+	Name  string   `parquet:"name"`
+	Score *float64 `parquet:"score"`
+}
+
+type roundTripRecord struct {
+	Id       int64                     `parquet:"id"`
+	Flag     bool                      `parquet:"flag"`
+	Small    int8                      `parquet:"small"`
+	Unsigned uint32                    `parquet:"unsigned"`
+	Ratio    float32                   `parquet:"ratio"`
+	Text     string                    `parquet:"text"`
+	Optional *int32                    `parquet:"optional"`
+	Payload  []byte                    `parquet:"payload"`
+	Hash     [4]byte                   `parquet:"hash"`
+	Created  time.Time                 `parquet:"created"`
+	Millis   time.Time                 `parquet:"millis,logical=timestamp_millis"`
+	Day      time.Time                 `parquet:"day,logical=date"`
+	Inner    *roundTripInner           `parquet:"inner"`
+	List     []string                  `parquet:"list"`
+	Nullable []*int64                  `parquet:"nullable"`
+	Repeated []roundTripInner          `parquet:"repeated,repeated"`
+	Counts   map[string]int32          `parquet:"counts"`
+	Groups   map[int64]*roundTripInner `parquet:"groups,optional"`
+	Matrix   [][]int32                 `parquet:"matrix"`
+}
+
+func roundTripRecords(n int) []roundTripRecord {
+	base := time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC)
+	records := make([]roundTripRecord, n)
+	for i := range records {
+		score := float64(i) / 4
+		optional := int32(i)
+		r := roundTripRecord{
+			Id:       int64(i) << 33,
+			Flag:     i%2 == 0,
+			Small:    int8(-i),
+			Unsigned: uint32(4000000000 + i),
+			Ratio:    float32(i) / 8,
+			Text:     fmt.Sprintf("text %d", i),
+			Payload:  []byte{byte(i), 0, byte(i)},
+			Hash:     [4]byte{1, 2, 3, byte(i)},
+			Created:  base.Add(time.Duration(i) * time.Hour),
+			Millis:   base.Add(time.Duration(i) * time.Millisecond).Truncate(time.Millisecond),
+			Day:      time.Date(2000+i, 1, 2, 0, 0, 0, 0, time.UTC),
+		}
+		if i%3 != 0 {
+			r.Optional = &optional
+			r.Inner = &roundTripInner{Name: "inner", Score: &score}
+			r.List = []string{"a", fmt.Sprint(i)}
+			r.Nullable = []*int64{nil, &r.Id}
+			r.Repeated = []roundTripInner{{Name: "x"}, {Name: "y", Score: &score}}
+			r.Counts = map[string]int32{"one": 1, "i": int32(i)}
+			r.Groups = map[int64]*roundTripInner{int64(i): {Name: "group"}, -1: nil}
+			r.Matrix = [][]int32{{1, 2}, {}, {int32(i)}}
+		}
+		records[i] = r
+	}
+	return records
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	records := roundTripRecords(25)
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().MaxRowGroupLength(10).Build()
+	writer := NewWriter[roundTripRecord](&buffer, properties)
+	writer.WriteRows(records)
+	writer.Close()
+
+	reader := NewReader[*roundTripRecord](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if reader.NumRows() != 25 {
+		t.Errorf("%d rows, want 25", reader.NumRows())
+	}
+	read := reader.ReadAll()
+	if len(read) != len(records) {
+		t.Fatalf("read %d records, want %d", len(read), len(records))
+	}
+	for i := range records {
+		if !sameRecord(read[i], records[i]) {
+			t.Errorf("record %d:\n%s\nwant\n%s", i, recordJSON(read[i]), recordJSON(records[i]))
+		}
+	}
+}
+
+func recordJSON(record interface{}) []byte {
+	data, err := json.Marshal(record)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Compares records through their JSON form, where pointers are followed and
+// times compared by instant. Empty lists and maps are read back as nil.
+func sameRecord(a interface{}, b interface{}) bool {
+	var valueA, valueB interface{}
+	json.Unmarshal(recordJSON(a), &valueA)
+	json.Unmarshal(recordJSON(b), &valueB)
+	return reflect.DeepEqual(dropEmpty(valueA), dropEmpty(valueB))
+}
+
+func dropEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i := range v {
+			v[i] = dropEmpty(v[i])
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for key := range v {
+			v[key] = dropEmpty(v[key])
+		}
+	}
+	return value
+}
+
+// The Reader matches fields to columns by name, whatever their order
+type partialRecord struct {
+	Missing *string  `parquet:"missing"`
+	List    []string `parquet:"list"`
+	Id      int64    `parquet:"id"`
+}
+
+func TestReadIntoOtherStruct(t *testing.T) {
+	records := roundTripRecords(6)
+	var buffer bytes.Buffer
+	writer := NewWriter[roundTripRecord](&buffer, nil)
+	writer.WriteRows(records)
+	writer.Close()
+
+	reader := NewReader[partialRecord](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	rows := make([]partialRecord, 4)
+	var read []partialRecord
+	for {
+		n := reader.Read(rows)
+		if n == 0 {
+			break
+		}
+		for _, row := range rows[:n] {
+			// Read reuses the slices of rows, copy them
+			row.List = append([]string(nil), row.List...)
+			read = append(read, row)
+		}
+	}
+	if len(read) != len(records) {
+		t.Fatalf("read %d records, want %d", len(read), len(records))
+	}
+	for i, row := range read {
+		if row.Id != records[i].Id || row.Missing != nil ||
+			fmt.Sprint(row.List) != fmt.Sprint(records[i].List) {
+			t.Errorf("record %d: %+v", i, row)
+		}
+	}
+}
+
+func TestDremelRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewWriter[dremelDocument](&buffer, nil)
+	documents := dremelDocuments()
+	writer.WriteRows(documents)
+	writer.Close()
+	read := NewReader[dremelDocument](bytes.NewReader(buffer.Bytes()), int64(buffer.Len())).ReadAll()
+	if !sameRecord(read, documents) {
+		t.Errorf("read %s, want %s", recordJSON(read), recordJSON(documents))
+	}
+}
+
+func TestReadProjection(t *testing.T) {
+	records := roundTripRecords(12)
+	var buffer bytes.Buffer
+	writer := NewWriter[roundTripRecord](&buffer, nil)
+	writer.WriteRows(records)
+	writer.Close()
+
+	reader := NewReader[roundTripRecord](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	reader.SetProjection(schema.ColumnPathFromDotString("text"),
+		schema.ColumnPathFromDotString("inner"), schema.ColumnPathFromDotString("counts"))
+	if reader.Schema().NumColumns() != 5 {
+		t.Errorf("%d projected columns, want 5", reader.Schema().NumColumns())
+	}
+	for i, record := range reader.ReadAll() {
+		expected := roundTripRecord{Text: records[i].Text, Inner: records[i].Inner,
+			Counts: records[i].Counts}
+		if !sameRecord(record, expected) {
+			t.Errorf("record %d: %s, want %s", i, recordJSON(record), recordJSON(expected))
+		}
+	}
+
+	rowReader := NewRowReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	rowReader.SetProjection(schema.ColumnPathFromDotString("id"),
+		schema.ColumnPathFromDotString("inner.name"))
+	for i, row := range rowReader.ReadAll() {
+		expected := Row{"id": records[i].Id, "inner": nil}
+		if records[i].Inner != nil {
+			expected["inner"] = Row{"name": records[i].Inner.Name}
+		}
+		if !reflect.DeepEqual(row, expected) {
+			t.Errorf("row %d: %v, want %v", i, row, expected)
+		}
+	}
+}
+
+type farTimes struct {
+	Nanos  time.Time `parquet:"nanos"`
+	Millis time.Time `parquet:"millis,logical=timestamp_millis"`
+	Micros time.Time `parquet:"micros,logical=timestamp_micros"`
+}
+
+func TestTimestampsOutsideNanosecondRange(t *testing.T) {
+	var records []farTimes
+	for _, year := range []int{1500, 1677, 1970, 2263, 2500} {
+		at := time.Date(year, 6, 7, 8, 9, 10, 123456000, time.UTC)
+		records = append(records, farTimes{Nanos: at, Millis: at.Truncate(time.Millisecond), Micros: at})
+	}
+	var buffer bytes.Buffer
+	writer := NewWriter[farTimes](&buffer, nil)
+	writer.WriteRows(records)
+	writer.Close()
+	read := NewReader[farTimes](bytes.NewReader(buffer.Bytes()), int64(buffer.Len())).ReadAll()
+	if len(read) != len(records) {
+		t.Fatalf("read %d records", len(read))
+	}
+	for i, record := range records {
+		if !read[i].Nanos.Equal(record.Nanos) || !read[i].Millis.Equal(record.Millis) ||
+			!read[i].Micros.Equal(record.Micros) {
+			t.Errorf("read %v, want %v", read[i], record)
+		}
+	}
+}
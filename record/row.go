@@ -0,0 +1,524 @@
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// Row is a record of any schema, built from generic Go values:
+//
+//	group              Row, keyed by field name
+//	repeated field     []interface{}
+//	LIST group         []interface{} of the elements
+//	MAP group          Row, keyed by the map key printed as a string
+//	null               nil, or a missing key
+//
+// Leaf values read back are bool, int32, int64, ptype.Int96, float32,
+// float64, string (UTF8, ENUM and JSON columns) or []byte. Writing also
+// accepts any Go integer or float, json.Number, time.Time for DATE,
+// TIMESTAMP and INT96 columns, and strings for binary columns.
+type Row map[string]interface{}
+
+const (
+	rowLeaf = iota
+	rowGroup
+	// LIST and MAP wrappers and the repeated group of a three level list
+	// hold the value of their single child
+	rowPassThrough
+	// The repeated key_value group of a MAP
+	rowMapEntries
+)
+
+type rowNode struct {
+	node     *schema.Node
+	kind     int
+	maxRep   int16
+	maxDef   int16
+	column   int
+	descr    *schema.ColumnDescriptor
+	children []*rowNode
+	leaves   []int
+}
+
+func newRowPlan(descr *schema.SchemaDescriptor) *rowNode {
+	root := &rowNode{node: descr.SchemaRoot(), kind: rowGroup, column: -1}
+	group := descr.GroupNode()
+	for i := 0; i < group.FieldCount(); i++ {
+		root.addChild(buildRowNode(descr, group.Field(i), 0, 0, false))
+	}
+	return root
+}
+
+func buildRowNode(descr *schema.SchemaDescriptor, node *schema.Node, maxRep int16,
+	maxDef int16, inList bool) *rowNode {
+	n := &rowNode{node: node, kind: rowGroup, column: -1, maxRep: maxRep, maxDef: maxDef}
+	switch node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		n.maxDef++
+	case ptype.Repetition_REPEATED:
+		n.maxDef++
+		n.maxRep++
+	}
+
+	if node.IsPrimitive() {
+		n.kind = rowLeaf
+		n.column = descr.ColumnIndex(schema.ColumnPathFromNode(node).ToDotString())
+		n.descr = descr.Column(n.column)
+		n.leaves = []int{n.column}
+		return n
+	}
+
+	group := (*schema.GroupNode)(unsafe.Pointer(node))
+	childInList := false
+	switch {
+	case node.LogicalType() == ptype.LogicalType_LIST,
+		node.LogicalType() == ptype.LogicalType_MAP:
+		if group.FieldCount() != 1 || !group.Field(0).IsRepeated() {
+			panic(fmt.Errorf("%s column %s must have a single repeated child",
+				ptype.LogicalTypeToString(node.LogicalType()),
+				schema.ColumnPathFromNode(node).ToDotString()))
+		}
+		n.kind = rowPassThrough
+		childInList = node.LogicalType() == ptype.LogicalType_LIST
+	case inList && node.IsRepeated() && group.FieldCount() == 1:
+		n.kind = rowPassThrough
+	case node.IsRepeated() && group.FieldCount() == 2 &&
+		(node.LogicalType() == ptype.LogicalType_MAP_KEY_VALUE ||
+			node.Parent().LogicalType() == ptype.LogicalType_MAP):
+		if !group.Field(0).IsPrimitive() {
+			panic(fmt.Errorf("Map keys of %s must be primitive",
+				schema.ColumnPathFromNode(node).ToDotString()))
+		}
+		n.kind = rowMapEntries
+	}
+	for i := 0; i < group.FieldCount(); i++ {
+		n.addChild(buildRowNode(descr, group.Field(i), n.maxRep, n.maxDef, childInList))
+	}
+	return n
+}
+
+func (n *rowNode) addChild(child *rowNode) {
+	n.children = append(n.children, child)
+	n.leaves = append(n.leaves, child.leaves...)
+}
+
+func (n *rowNode) path() string {
+	return schema.ColumnPathFromNode(n.node).ToDotString()
+}
+
+func isStringColumn(descr *schema.ColumnDescriptor) bool {
+	switch descr.LogicalType() {
+	case ptype.LogicalType_UTF8, ptype.LogicalType_ENUM, ptype.LogicalType_JSON:
+		return true
+	}
+	return false
+}
+
+func rowInt(v interface{}) (int64, bool) {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i, true
+		}
+		if u, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return int64(u), true
+		}
+		return 0, false
+	case float32:
+		return int64(value), float32(int64(value)) == value
+	case float64:
+		return int64(value), float64(int64(value)) == value
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case isIntKind(rv.Kind()):
+		return rv.Int(), true
+	case isUintKind(rv.Kind()):
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+func rowFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case json.Number:
+		f, err := value.Float64()
+		return f, err == nil
+	case float32:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	if i, ok := rowInt(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
+// Convert a Row leaf value to the canonical type of the column
+func rowLeafValue(descr *schema.ColumnDescriptor, v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		if value, ok := ptype.TimeValue(t, descr.PhysicalType(), descr.LogicalType()); ok {
+			return value
+		}
+	}
+	switch descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	case ptype.Type_INT32:
+		if i, ok := rowInt(v); ok {
+			switch descr.LogicalType() {
+			case ptype.LogicalType_UINT_8, ptype.LogicalType_UINT_16,
+				ptype.LogicalType_UINT_32:
+				if i >= 0 && i <= math.MaxUint32 {
+					return int32(uint32(i))
+				}
+			default:
+				if i >= math.MinInt32 && i <= math.MaxInt32 {
+					return int32(i)
+				}
+			}
+		}
+	case ptype.Type_INT64:
+		if i, ok := rowInt(v); ok {
+			return i
+		}
+	case ptype.Type_INT96:
+		switch value := v.(type) {
+		case ptype.Int96:
+			return value
+		case []interface{}:
+			if len(value) == 3 {
+				var result ptype.Int96
+				for i := range result {
+					word, ok := rowInt(value[i])
+					if !ok || word < 0 || word > math.MaxUint32 {
+						break
+					}
+					result[i] = uint32(word)
+					if i == 2 {
+						return result
+					}
+				}
+			}
+		}
+	case ptype.Type_FLOAT:
+		if f, ok := rowFloat(v); ok {
+			return float32(f)
+		}
+	case ptype.Type_DOUBLE:
+		if f, ok := rowFloat(v); ok {
+			return f
+		}
+	case ptype.Type_BYTE_ARRAY:
+		switch value := v.(type) {
+		case string:
+			if isStringColumn(descr) {
+				return value
+			}
+			return []byte(value)
+		case []byte:
+			if isStringColumn(descr) {
+				return string(value)
+			}
+			return value
+		}
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		var value []byte
+		switch b := v.(type) {
+		case string:
+			value = []byte(b)
+		case []byte:
+			value = b
+		}
+		if value != nil && len(value) == int(descr.TypeLength()) {
+			return value
+		}
+	}
+	panic(fmt.Errorf("Cannot store %T value %v in column %s of type %s (%s)", v, v,
+		descr.Path().ToDotString(), ptype.TypeToString(descr.PhysicalType()),
+		ptype.LogicalTypeToString(descr.LogicalType())))
+}
+
+// Convert a map key to the canonical type of the key column
+func rowMapKey(descr *schema.ColumnDescriptor, key string) interface{} {
+	switch descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			panic(fmt.Errorf("Invalid key '%s' of map %s: %v", key,
+				descr.Path().ToDotString(), err))
+		}
+		return b
+	case ptype.Type_INT32, ptype.Type_INT64, ptype.Type_FLOAT, ptype.Type_DOUBLE:
+		return rowLeafValue(descr, json.Number(key))
+	}
+	return rowLeafValue(descr, key)
+}
+
+// The string a map key is stored under in a Row
+func rowMapKeyString(key interface{}) string {
+	switch value := key.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	}
+	return fmt.Sprint(key)
+}
+
+func appendRowValue(c *columnBuffer, v interface{}) {
+	switch value := v.(type) {
+	case bool:
+		c.boolValues = append(c.boolValues, value)
+	case int32:
+		c.int32Values = append(c.int32Values, value)
+	case int64:
+		c.int64Values = append(c.int64Values, value)
+	case ptype.Int96:
+		c.int96Values = append(c.int96Values, value)
+	case float32:
+		c.floatValues = append(c.floatValues, value)
+	case float64:
+		c.doubleValues = append(c.doubleValues, value)
+	case string:
+		c.byteValues = append(c.byteValues, ptype.ByteArray(value))
+	case []byte:
+		// Copy, the caller may reuse the slice once Write returns
+		if c.descr.PhysicalType() == ptype.Type_FIXED_LEN_BYTE_ARRAY {
+			c.flbaValues = append(c.flbaValues, append(ptype.FixedLenByteArray(nil), value...))
+		} else {
+			c.byteValues = append(c.byteValues, append(ptype.ByteArray(nil), value...))
+		}
+	}
+}
+
+// The i-th value of the column in its canonical Row type
+func rowValue(c *columnBuffer, i int) interface{} {
+	switch c.descr.PhysicalType() {
+	case ptype.Type_BOOLEAN:
+		return c.boolValues[i]
+	case ptype.Type_INT32:
+		return c.int32Values[i]
+	case ptype.Type_INT64:
+		return c.int64Values[i]
+	case ptype.Type_INT96:
+		return c.int96Values[i]
+	case ptype.Type_FLOAT:
+		return c.floatValues[i]
+	case ptype.Type_DOUBLE:
+		return c.doubleValues[i]
+	case ptype.Type_BYTE_ARRAY:
+		if isStringColumn(c.descr) {
+			return string(c.byteValues[i])
+		}
+		return append([]byte(nil), c.byteValues[i]...)
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		return append([]byte(nil), c.flbaValues[i]...)
+	}
+	panic(fmt.Errorf("Unknown physical type: %d", c.descr.PhysicalType()))
+}
+
+// The elements of a repeated field
+func rowElements(n *rowNode, v interface{}) []interface{} {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return value
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		panic(fmt.Errorf("Repeated field %s needs a slice, got %T", n.path(), v))
+	}
+	elements := make([]interface{}, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements
+}
+
+func rowFields(n *rowNode, v interface{}) map[string]interface{} {
+	switch value := v.(type) {
+	case Row:
+		return value
+	case map[string]interface{}:
+		return value
+	}
+	panic(fmt.Errorf("Group %s needs a Row, got %T", n.path(), v))
+}
+
+// Shred v, the value of this node, at the given levels of its parent
+func (n *rowNode) write(columns []*columnBuffer, v interface{}, rep int16, def int16) {
+	switch n.node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		if v == nil {
+			n.writeNull(columns, rep, def)
+			return
+		}
+		n.writeContent(columns, v, rep, def+1)
+	case ptype.Repetition_REPEATED:
+		if n.kind == rowMapEntries {
+			var entries map[string]interface{}
+			if v != nil {
+				entries = rowFields(n, v)
+			}
+			if len(entries) == 0 {
+				n.writeNull(columns, rep, def)
+				return
+			}
+			// Sort the keys to write deterministic files
+			keys := make([]string, 0, len(entries))
+			for key := range entries {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for i, key := range keys {
+				if i > 0 {
+					rep = n.maxRep
+				}
+				n.children[0].write(columns, rowMapKey(n.children[0].descr, key), rep, def+1)
+				n.children[1].write(columns, entries[key], rep, def+1)
+			}
+			return
+		}
+		elements := rowElements(n, v)
+		if len(elements) == 0 {
+			n.writeNull(columns, rep, def)
+			return
+		}
+		for i, element := range elements {
+			if i > 0 {
+				rep = n.maxRep
+			}
+			n.writeContent(columns, element, rep, def+1)
+		}
+	default:
+		if v == nil {
+			panic(fmt.Errorf("Required field %s is missing", n.path()))
+		}
+		n.writeContent(columns, v, rep, def)
+	}
+}
+
+func (n *rowNode) writeContent(columns []*columnBuffer, v interface{}, rep int16,
+	def int16) {
+	switch n.kind {
+	case rowLeaf:
+		c := columns[n.column]
+		appendRowValue(c, rowLeafValue(n.descr, v))
+		c.AddLevels(def, rep)
+	case rowPassThrough:
+		n.children[0].write(columns, v, rep, def)
+	default:
+		fields := rowFields(n, v)
+		for key := range fields {
+			if n.child(key) == nil {
+				panic(fmt.Errorf("Group %s has no field %s", n.path(), key))
+			}
+		}
+		for _, child := range n.children {
+			child.write(columns, fields[child.node.Name()], rep, def)
+		}
+	}
+}
+
+func (n *rowNode) child(name string) *rowNode {
+	for _, child := range n.children {
+		if child.node.Name() == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// A null or empty value: every leaf below records the levels only
+func (n *rowNode) writeNull(columns []*columnBuffer, rep int16, def int16) {
+	for _, leaf := range n.leaves {
+		columns[leaf].AddLevels(def, rep)
+	}
+}
+
+// Levels of the next entry of the first leaf below the node
+func (n *rowNode) peekDef(columns []*columnBuffer) int16 {
+	c := columns[n.leaves[0]]
+	if c.levelPos >= len(c.defLevels) {
+		panic(fmt.Errorf("Column %s ended before the row group",
+			c.descr.Path().ToDotString()))
+	}
+	return c.defLevels[c.levelPos]
+}
+
+func (n *rowNode) repeats(columns []*columnBuffer) bool {
+	c := columns[n.leaves[0]]
+	return c.levelPos < len(c.repLevels) && c.repLevels[c.levelPos] == n.maxRep
+}
+
+func (n *rowNode) skipNull(columns []*columnBuffer) {
+	for _, leaf := range n.leaves {
+		columns[leaf].levelPos++
+	}
+}
+
+// Assemble the value of this node
+func (n *rowNode) read(columns []*columnBuffer) interface{} {
+	switch n.node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		if n.peekDef(columns) < n.maxDef {
+			n.skipNull(columns)
+			return nil
+		}
+		return n.readContent(columns)
+	case ptype.Repetition_REPEATED:
+		empty := n.peekDef(columns) < n.maxDef
+		if empty {
+			n.skipNull(columns)
+		}
+		if n.kind == rowMapEntries {
+			entries := Row{}
+			for !empty {
+				key := n.children[0].read(columns)
+				entries[rowMapKeyString(key)] = n.children[1].read(columns)
+				empty = !n.repeats(columns)
+			}
+			return entries
+		}
+		elements := []interface{}{}
+		for !empty {
+			elements = append(elements, n.readContent(columns))
+			empty = !n.repeats(columns)
+		}
+		return elements
+	default:
+		return n.readContent(columns)
+	}
+}
+
+func (n *rowNode) readContent(columns []*columnBuffer) interface{} {
+	switch n.kind {
+	case rowLeaf:
+		c := columns[n.column]
+		value := rowValue(c, c.valuePos)
+		c.valuePos++
+		c.levelPos++
+		return value
+	case rowPassThrough:
+		return n.children[0].read(columns)
+	default:
+		row := make(Row, len(n.children))
+		for _, child := range n.children {
+			row[child.node.Name()] = child.read(columns)
+		}
+		return row
+	}
+}
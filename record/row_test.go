@@ -0,0 +1,175 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+const rowSchema = `message row {
+  required int64 id;
+  optional binary name (UTF8);
+  optional binary payload;
+  optional int32 small (UINT_8);
+  optional double score;
+  optional int96 legacy;
+  optional int64 created (TIMESTAMP_MILLIS);
+  optional int32 day (DATE);
+  optional fixed_len_byte_array(2) code;
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (UTF8);
+    }
+  }
+  repeated int32 numbers;
+  optional group counts (MAP) {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required int32 key;
+      optional int64 value;
+    }
+  }
+  optional group inner {
+    required boolean flag;
+    repeated group points {
+      required float x;
+      optional float y;
+    }
+  }
+}
+`
+
+func TestRowRoundTrip(t *testing.T) {
+	root := schema.MustParse(rowSchema)
+	created := time.Date(2020, 5, 6, 7, 8, 9, 10000000, time.UTC)
+	rows := []Row{
+		{
+			"id": 1, "name": "first", "payload": []byte{0, 1, 2}, "small": uint8(200),
+			"score": 2.5, "legacy": ptype.Int96{1, 2, 3}, "created": created,
+			"day": time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), "code": "ab",
+			"tags": []interface{}{"x", nil, "z"}, "numbers": []interface{}{int8(1), 2, int64(3)},
+			"counts": Row{"5": 50, "-1": nil},
+			"inner": Row{"flag": true, "points": []interface{}{
+				Row{"x": 1, "y": float32(2)}, Row{"x": json.Number("3.5")}}},
+		},
+		{"id": json.Number("9007199254740993"), "tags": []interface{}{}, "inner": Row{"flag": false}},
+	}
+	expected := []Row{
+		{
+			"id": int64(1), "name": "first", "payload": []byte{0, 1, 2}, "small": int32(200),
+			"score": 2.5, "legacy": ptype.Int96{1, 2, 3}, "created": created.UnixMilli(),
+			"day": int32(10956), "code": []byte("ab"),
+			"tags": []interface{}{"x", nil, "z"}, "numbers": []interface{}{int32(1), int32(2), int32(3)},
+			"counts": Row{"-1": nil, "5": int64(50)},
+			"inner": Row{"flag": true, "points": []interface{}{
+				Row{"x": float32(1), "y": float32(2)}, Row{"x": float32(3.5), "y": nil}}},
+		},
+		// Nulls are read back as nil and empty repeated fields as empty slices
+		{
+			"id": int64(9007199254740993), "name": nil, "payload": nil, "small": nil,
+			"score": nil, "legacy": nil, "created": nil, "day": nil, "code": nil,
+			"tags": []interface{}{}, "numbers": []interface{}{}, "counts": nil,
+			"inner": Row{"flag": false, "points": []interface{}{}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	writer := NewRowWriter(&buffer, root, nil)
+	writer.WriteRows(rows)
+	writer.Close()
+	read := NewRowReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())).ReadAll()
+	if !reflect.DeepEqual(read, expected) {
+		t.Errorf("read %v, want %v", read, expected)
+	}
+}
+
+func TestRowJSON(t *testing.T) {
+	descr := schema.NewSchemaDescriptor(&schema.MustParse(rowSchema).Node)
+	text := `{"id": 9007199254740993, "payload": "AAEC", "legacy": [1, 2, 3], "score": 1e3,
+		"counts": {"7": 70}, "tags": ["a", null], "inner": {"flag": true, "points": [{"x": 0.5}]}}`
+	row := RowFromJSON(descr, []byte(text))
+	expected := Row{
+		"id": int64(9007199254740993), "payload": []byte{0, 1, 2}, "legacy": ptype.Int96{1, 2, 3},
+		"score": 1000.0, "counts": Row{"7": int64(70)}, "tags": []interface{}{"a", nil},
+		"inner": Row{"flag": true, "points": []interface{}{Row{"x": float32(0.5)}}},
+	}
+	if !reflect.DeepEqual(row, expected) {
+		t.Errorf("decoded %v, want %v", row, expected)
+	}
+	if again := RowFromJSON(descr, RowToJSON(row)); !reflect.DeepEqual(again, expected) {
+		t.Errorf("%s decoded as %v", RowToJSON(row), again)
+	}
+
+	decoder := NewRowJSONDecoder(descr, strings.NewReader(`{"id": 1} {"id": 2}`+"\n"+`{"id": 3}`))
+	var ids []interface{}
+	for decoder.More() {
+		ids = append(ids, decoder.Decode()["id"])
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("decoded ids %v", ids)
+	}
+}
+
+func rowPanic(do func()) (message string) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			message = fmt.Sprint(failure)
+		}
+	}()
+	do()
+	return ""
+}
+
+func TestRowInvalidValues(t *testing.T) {
+	root := schema.MustParse(rowSchema)
+	descr := schema.NewSchemaDescriptor(&root.Node)
+	tests := []struct {
+		row     Row
+		message string
+	}{
+		{Row{"id": "one"}, "Cannot store string value one in column id"},
+		{Row{"id": 1, "small": -1}, "column small"},
+		{Row{"id": 1, "code": "abc"}, "column code"},
+		{Row{"id": 1, "counts": Row{"x": 1}}, "value x in column counts.key_value.key"},
+	}
+	for _, test := range tests {
+		message := rowPanic(func() {
+			NewRowWriter(&bytes.Buffer{}, root, nil).Write(test.row)
+		})
+		if !strings.Contains(message, test.message) {
+			t.Errorf("%v: %q, want %q", test.row, message, test.message)
+		}
+	}
+	if message := rowPanic(func() { RowFromJSON(descr, []byte(`[1]`)) }); !strings.Contains(message,
+		"JSON row must be an object") {
+		t.Errorf("decoding an array: %q", message)
+	}
+}
+
+func TestRowTimestampsOutsideNanosecondRange(t *testing.T) {
+	root := schema.MustParse(`message times {
+  optional int64 millis (TIMESTAMP_MILLIS);
+  optional int64 micros (TIMESTAMP_MICROS);
+}
+`)
+	var rows, expected []Row
+	for _, year := range []int{1500, 2500} {
+		at := time.Date(year, 1, 2, 3, 4, 5, 6000, time.UTC)
+		rows = append(rows, Row{"millis": at, "micros": at})
+		expected = append(expected, Row{"millis": at.UnixMilli(), "micros": at.UnixMicro()})
+	}
+	var buffer bytes.Buffer
+	writer := NewRowWriter(&buffer, root, nil)
+	writer.WriteRows(rows)
+	writer.Close()
+	read := NewRowReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())).ReadAll()
+	if !reflect.DeepEqual(read, expected) {
+		t.Errorf("read %v, want %v", read, expected)
+	}
+}
@@ -0,0 +1,245 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/schema"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// Record shredding as described in the Dremel paper: every record is
+// decomposed into the values of its leaf columns, each value annotated with
+// a repetition level (at which repeated field in the path the value repeats)
+// and a definition level (how many optional / repeated fields in the path
+// are defined).
+//
+// A shredNode mirrors one node of the schema and knows where the matching Go
+// value is found inside the value of its parent.
+
+const passThrough = -1
+
+type shredNode struct {
+	node *schema.Node
+	// Repetition level of the node, used for all but the first value of a
+	// repeated node
+	maxRep int16
+	// Leaf column index, or -1 for groups
+	column   int
+	appender valueAppender
+	children []*shredNode
+	// Struct field index of each child, passThrough hands the group's own
+	// value to the child (LIST and MAP wrappers)
+	fields []int
+	// A repeated key_value group iterating the entries of a Go map
+	isMap bool
+	// Leaf columns below this node
+	leaves []int
+}
+
+type shredBuilder struct {
+	schema     *schema.SchemaDescriptor
+	nextColumn int
+}
+
+// Build the shredding plan of a struct type for the schema, matching
+// fields by their column name
+func newShredPlan(descr *schema.SchemaDescriptor, t reflect.Type) *shredNode {
+	builder := &shredBuilder{schema: descr}
+	root := &shredNode{node: descr.SchemaRoot(), column: -1}
+	builder.buildStruct(root, descr.GroupNode(), t, 0)
+	return root
+}
+
+func (b *shredBuilder) build(node *schema.Node, t reflect.Type, maxRep int16,
+	inList bool) *shredNode {
+	n := &shredNode{node: node, column: -1, maxRep: maxRep}
+	path := schema.ColumnPathFromNode(node).ToDotString()
+
+	switch node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	case ptype.Repetition_REPEATED:
+		n.maxRep++
+		switch {
+		case t.Kind() == reflect.Map:
+			n.isMap = true
+		case t.Kind() == reflect.Slice && !(node.IsPrimitive() &&
+			t.Elem().Kind() == reflect.Uint8):
+			t = t.Elem()
+		default:
+			panic(fmt.Errorf("Repeated field %s needs a slice or map, got %s", path, t))
+		}
+	}
+
+	if node.IsPrimitive() {
+		n.column = b.nextColumn
+		b.nextColumn++
+		n.appender = newValueAppender(b.schema.Column(n.column), t)
+		n.leaves = []int{n.column}
+		return n
+	}
+
+	group := (*schema.GroupNode)(unsafe.Pointer(node))
+	switch {
+	case n.isMap:
+		// repeated group key_value { key; value; }
+		if group.FieldCount() != 2 {
+			panic(fmt.Errorf("Map field %s must have a key and a value", path))
+		}
+		n.addChild(b.build(group.Field(0), t.Key(), n.maxRep, false), passThrough)
+		n.addChild(b.build(group.Field(1), t.Elem(), n.maxRep, false), passThrough)
+	case node.LogicalType() == ptype.LogicalType_LIST,
+		node.LogicalType() == ptype.LogicalType_MAP:
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if group.FieldCount() != 1 || !group.Field(0).IsRepeated() {
+			panic(fmt.Errorf("%s field %s must have a single repeated child", path,
+				ptype.LogicalTypeToString(node.LogicalType())))
+		}
+		n.addChild(b.build(group.Field(0), t, n.maxRep,
+			node.LogicalType() == ptype.LogicalType_LIST), passThrough)
+	case inList && node.IsRepeated() && group.FieldCount() == 1:
+		// The repeated group of a three level list holds the element
+		n.addChild(b.build(group.Field(0), t, n.maxRep, false), passThrough)
+	default:
+		b.buildStruct(n, group, t, n.maxRep)
+	}
+	return n
+}
+
+func (b *shredBuilder) buildStruct(n *shredNode, group *schema.GroupNode,
+	t reflect.Type, maxRep int16) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("Group %s needs a struct, got %s", group.Name(), t))
+	}
+	fields := structFieldsByName(t)
+	for i := 0; i < group.FieldCount(); i++ {
+		child := group.Field(i)
+		index, ok := fields[child.Name()]
+		if !ok {
+			panic(fmt.Errorf("Struct %s has no field for column %s", t,
+				schema.ColumnPathFromNode(child).ToDotString()))
+		}
+		n.addChild(b.build(child, t.Field(index).Type, maxRep, false), index)
+	}
+}
+
+func (n *shredNode) addChild(child *shredNode, field int) {
+	n.children = append(n.children, child)
+	n.fields = append(n.fields, field)
+	n.leaves = append(n.leaves, child.leaves...)
+}
+
+// Maps column names to the index of the exported struct field storing them
+func structFieldsByName(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := schema.ParseFieldTag(field)
+		if !tag.Skip {
+			fields[tag.Name] = i
+		}
+	}
+	return fields
+}
+
+func isNull(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Shred v, the value of this node, at the given levels of its parent
+func (n *shredNode) write(columns []*columnBuffer, v reflect.Value, rep int16,
+	def int16) {
+	switch n.node.Repetition() {
+	case ptype.Repetition_OPTIONAL:
+		if isNull(v) {
+			n.writeNull(columns, rep, def)
+			return
+		}
+		n.writeContent(columns, reflect.Indirect(v), rep, def+1)
+	case ptype.Repetition_REPEATED:
+		if v.Len() == 0 {
+			n.writeNull(columns, rep, def)
+			return
+		}
+		if n.isMap {
+			for i, key := range sortedMapKeys(v) {
+				if i > 0 {
+					rep = n.maxRep
+				}
+				n.children[0].write(columns, key, rep, def+1)
+				n.children[1].write(columns, v.MapIndex(key), rep, def+1)
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				rep = n.maxRep
+			}
+			n.writeContent(columns, v.Index(i), rep, def+1)
+		}
+	default:
+		n.writeContent(columns, v, rep, def)
+	}
+}
+
+func (n *shredNode) writeContent(columns []*columnBuffer, v reflect.Value,
+	rep int16, def int16) {
+	if n.column >= 0 {
+		c := columns[n.column]
+		n.appender(c, v)
+		c.AddLevels(def, rep)
+		return
+	}
+	for i, child := range n.children {
+		if n.fields[i] == passThrough {
+			child.write(columns, v, rep, def)
+		} else {
+			child.write(columns, v.Field(n.fields[i]), rep, def)
+		}
+	}
+}
+
+// A null or empty value: every leaf below records the levels only
+func (n *shredNode) writeNull(columns []*columnBuffer, rep int16, def int16) {
+	for _, leaf := range n.leaves {
+		columns[leaf].AddLevels(def, rep)
+	}
+}
+
+// Map iteration order is random, sort the keys to write deterministic files
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.Kind() == reflect.String:
+			return a.String() < b.String()
+		case isIntKind(a.Kind()):
+			return a.Int() < b.Int()
+		case isUintKind(a.Kind()):
+			return a.Uint() < b.Uint()
+		case a.Kind() == reflect.Float32 || a.Kind() == reflect.Float64:
+			return a.Float() < b.Float()
+		case a.Kind() == reflect.Bool:
+			return !a.Bool() && b.Bool()
+		}
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	})
+	return keys
+}
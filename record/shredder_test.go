@@ -0,0 +1,203 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/schema"
+)
+
+// The sample records of the Dremel paper
+type dremelLanguage struct {
+	Code    string  `parquet:"Code"`
+	Country *string `parquet:"Country"`
+}
+
+type dremelName struct {
+	Language []dremelLanguage `parquet:"Language,repeated"`
+	Url      *string          `parquet:"Url"`
+}
+
+type dremelLinks struct {
+	Backward []int64 `parquet:"Backward,repeated"`
+	Forward  []int64 `parquet:"Forward,repeated"`
+}
+
+type dremelDocument struct {
+	DocId int64        `parquet:"DocId"`
+	Links *dremelLinks `parquet:"Links"`
+	Name  []dremelName `parquet:"Name,repeated"`
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func dremelDocuments() []dremelDocument {
+	return []dremelDocument{
+		{
+			DocId: 10,
+			Links: &dremelLinks{Forward: []int64{20, 40, 60}},
+			Name: []dremelName{
+				{Language: []dremelLanguage{{"en-us", stringPointer("us")}, {Code: "en"}},
+					Url: stringPointer("http://A")},
+				{Url: stringPointer("http://B")},
+				{Language: []dremelLanguage{{"en-gb", stringPointer("gb")}}},
+			},
+		},
+		{
+			DocId: 20,
+			Links: &dremelLinks{Backward: []int64{10, 30}, Forward: []int64{80}},
+			Name:  []dremelName{{Url: stringPointer("http://C")}},
+		},
+	}
+}
+
+func TestShredDremelDocuments(t *testing.T) {
+	descr := schema.NewSchemaDescriptor(&schema.MustFromStruct(reflect.TypeOf(dremelDocument{})).Node)
+	plan := newShredPlan(descr, reflect.TypeOf(dremelDocument{}))
+	var columns []*columnBuffer
+	for i := 0; i < descr.NumColumns(); i++ {
+		columns = append(columns, newColumnBuffer(descr.Column(i)))
+	}
+	for _, document := range dremelDocuments() {
+		plan.writeContent(columns, reflect.ValueOf(document), 0, 0)
+	}
+
+	tests := []struct {
+		path      string
+		values    interface{}
+		repLevels []int16
+		defLevels []int16
+	}{
+		{"DocId", []int64{10, 20}, []int16{0, 0}, []int16{0, 0}},
+		{"Links.Backward", []int64{10, 30}, []int16{0, 0, 1}, []int16{1, 2, 2}},
+		{"Links.Forward", []int64{20, 40, 60, 80}, []int16{0, 1, 1, 0}, []int16{2, 2, 2, 2}},
+		{"Name.Language.Code", [][]byte{[]byte("en-us"), []byte("en"), []byte("en-gb")},
+			[]int16{0, 2, 1, 1, 0}, []int16{2, 2, 1, 2, 1}},
+		{"Name.Language.Country", [][]byte{[]byte("us"), []byte("gb")},
+			[]int16{0, 2, 1, 1, 0}, []int16{3, 2, 1, 3, 1}},
+		{"Name.Url", [][]byte{[]byte("http://A"), []byte("http://B"), []byte("http://C")},
+			[]int16{0, 1, 1, 0}, []int16{2, 2, 1, 2}},
+	}
+	for i, test := range tests {
+		if path := descr.Column(i).Path().ToDotString(); path != test.path {
+			t.Fatalf("column %d is %s, want %s", i, path, test.path)
+		}
+		c := columns[i]
+		if !reflect.DeepEqual(c.repLevels, test.repLevels) ||
+			!reflect.DeepEqual(c.defLevels, test.defLevels) {
+			t.Errorf("%s: rep %v def %v, want rep %v def %v", test.path, c.repLevels,
+				c.defLevels, test.repLevels, test.defLevels)
+		}
+		values := reflect.ValueOf(c.Values())
+		expected := reflect.ValueOf(test.values)
+		if values.Len() != expected.Len() {
+			t.Errorf("%s: %d values, want %d", test.path, values.Len(), expected.Len())
+			continue
+		}
+		for j := 0; j < values.Len(); j++ {
+			value := values.Index(j)
+			if value.Kind() == reflect.Slice {
+				value = value.Convert(reflect.TypeOf([]byte(nil)))
+			}
+			if !reflect.DeepEqual(value.Interface(), expected.Index(j).Interface()) {
+				t.Errorf("%s: value %d is %v, want %v", test.path, j, value, expected.Index(j))
+			}
+		}
+	}
+}
+
+type shredMap struct {
+	Counts map[string]int32 `parquet:"counts"`
+	Tags   []*string        `parquet:"tags"`
+}
+
+func TestShredMapsAndLists(t *testing.T) {
+	descr := schema.NewSchemaDescriptor(&schema.MustFromStruct(reflect.TypeOf(shredMap{})).Node)
+	plan := newShredPlan(descr, reflect.TypeOf(shredMap{}))
+	var columns []*columnBuffer
+	for i := 0; i < descr.NumColumns(); i++ {
+		columns = append(columns, newColumnBuffer(descr.Column(i)))
+	}
+	records := []shredMap{
+		{Counts: map[string]int32{"b": 2, "a": 1, "c": 3}, Tags: []*string{stringPointer("x"), nil}},
+		{},
+	}
+	for _, record := range records {
+		plan.writeContent(columns, reflect.ValueOf(record), 0, 0)
+	}
+	// Map entries are written in key order
+	if !reflect.DeepEqual(columns[1].Values(), []int32{1, 2, 3}) {
+		t.Errorf("map values %v", columns[1].Values())
+	}
+	if !reflect.DeepEqual(columns[0].repLevels, []int16{0, 1, 1, 0}) ||
+		!reflect.DeepEqual(columns[0].defLevels, []int16{1, 1, 1, 0}) {
+		t.Errorf("map keys: rep %v def %v", columns[0].repLevels, columns[0].defLevels)
+	}
+	if !reflect.DeepEqual(columns[2].repLevels, []int16{0, 1, 0}) ||
+		!reflect.DeepEqual(columns[2].defLevels, []int16{2, 1, 0}) {
+		t.Errorf("list: rep %v def %v", columns[2].repLevels, columns[2].defLevels)
+	}
+}
+
+func TestWriterFlushesRowGroups(t *testing.T) {
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().MaxRowGroupLength(2).Build()
+	writer := NewWriter[*dremelDocument](&buffer, properties)
+	documents := dremelDocuments()
+	writer.Write(&documents[0])
+	if writer.NumBufferedRows() != 1 {
+		t.Errorf("%d buffered rows, want 1", writer.NumBufferedRows())
+	}
+	writer.WriteRows([]*dremelDocument{&documents[1], &documents[0]})
+	if writer.NumBufferedRows() != 1 {
+		t.Errorf("%d buffered rows after a row group, want 1", writer.NumBufferedRows())
+	}
+	writer.Close()
+	if data := buffer.Bytes(); len(data) < 8 || string(data[:4]) != "PAR1" ||
+		string(data[len(data)-4:]) != "PAR1" {
+		t.Errorf("not a parquet file: %q", data)
+	}
+}
+
+type eventRecord struct {
+	Id   int64     `parquet:"id"`
+	Tags []string  `parquet:"tags"`
+	When time.Time `parquet:"when"`
+}
+
+func TestWriteSkipsFailedRecords(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewWriter[*eventRecord](&buffer, nil)
+	when := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	records := []*eventRecord{
+		{Id: 1, Tags: []string{"a", "b"}, When: when},
+		// Out of the range of TIMESTAMP_MICROS, after the id and tags are
+		// shredded
+		{Id: 2, Tags: []string{"c"}, When: time.Date(300000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Id: 3, When: when},
+	}
+	expected := "Time 300000-01-01 00:00:00 +0000 UTC is out of the range of column when"
+	if err := writer.WriteRows(records); err == nil || err.Error() != expected {
+		t.Errorf("writing records: %v, want %q", err, expected)
+	}
+	if err := writer.Write(nil); err == nil || err.Error() != "Cannot write a nil record" {
+		t.Errorf("writing a nil record: %v", err)
+	}
+	if err := writer.Write(records[2]); err != nil {
+		t.Errorf("writing a record: %v", err)
+	}
+	writer.Close()
+
+	reader := NewReader[eventRecord](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	read := reader.ReadAll()
+	if len(read) != 2 || fmt.Sprint(read[0].Id, read[0].Tags, read[1].Id, len(read[1].Tags)) != "1 [a b] 3 0" ||
+		!read[0].When.Equal(when) || !read[1].When.Equal(when) {
+		t.Errorf("read %v, want records 1 and 3", read)
+	}
+}
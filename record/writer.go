@@ -0,0 +1,224 @@
+package record
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/encoding"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/schema"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// The column buffers of the row group being written, shared by Writer and
+// RowWriter
+type bufferedWriter struct {
+	fileWriter *file.ParquetFileWriter
+	columns    []*columnBuffer
+	numRows    int64
+	properties *column.WriterProperties
+}
+
+func newBufferedWriter(sink io.Writer, root *schema.GroupNode,
+	properties *column.WriterProperties) *bufferedWriter {
+	if properties == nil {
+		properties = column.DefaultWriterProperties()
+	}
+	fileWriter := file.NewParquetFileWriterOpen(sink, root, properties)
+	w := &bufferedWriter{
+		fileWriter: fileWriter,
+		properties: properties,
+	}
+	descr := fileWriter.Schema()
+	for i := 0; i < descr.NumColumns(); i++ {
+		w.columns = append(w.columns, newColumnBuffer(descr.Column(i)))
+	}
+	return w
+}
+
+func (w *bufferedWriter) Schema() *schema.SchemaDescriptor {
+	return w.fileWriter.Schema()
+}
+
+// Shred a record with write, dropping what it buffered if it fails
+func (w *bufferedWriter) shred(write func()) (err error) {
+	numLevels := make([]int, len(w.columns))
+	numValues := make([]int, len(w.columns))
+	for i, buffer := range w.columns {
+		numLevels[i] = len(buffer.defLevels)
+		numValues[i] = encoding.ValuesLen(buffer.Values())
+	}
+	defer func() {
+		if failure := recover(); failure != nil {
+			failed, ok := failure.(error)
+			if !ok {
+				panic(failure)
+			}
+			for i, buffer := range w.columns {
+				buffer.truncate(numLevels[i], numValues[i])
+			}
+			err = failed
+		}
+	}()
+	write()
+	return nil
+}
+
+// Count a shredded record, writing the row group once it is full
+func (w *bufferedWriter) endRecord() {
+	w.numRows++
+	if w.numRows >= w.properties.MaxRowGroupLength() {
+		w.Flush()
+	}
+}
+
+// Number of records buffered for the current row group
+func (w *bufferedWriter) NumBufferedRows() int64 {
+	return w.numRows
+}
+
+// Write the buffered records as a row group
+func (w *bufferedWriter) Flush() {
+	if w.numRows == 0 {
+		return
+	}
+	if w.properties.SortOnWrite() {
+		w.sortRows()
+	}
+	if concurrency := w.properties.WriteConcurrency(); concurrency > 1 {
+		rowGroup := w.fileWriter.AppendBufferedRowGroup(w.numRows)
+		rowGroup.WriteColumns(concurrency, func(i int, writer *column.ColumnWriter) {
+			w.columns[i].WriteBatch(writer)
+			w.columns[i].Reset()
+		})
+		rowGroup.Close()
+		w.numRows = 0
+		return
+	}
+	rowGroup := w.fileWriter.AppendRowGroup(w.numRows)
+	for _, buffer := range w.columns {
+		buffer.WriteBatch(rowGroup.NextColumn())
+		buffer.Reset()
+	}
+	rowGroup.Close()
+	w.numRows = 0
+}
+
+// Sort the buffered records by the sorting columns, records with equal
+// keys keep their order
+func (w *bufferedWriter) sortRows() {
+	sortingColumns := w.properties.SortingColumns()
+	if len(sortingColumns) == 0 {
+		return
+	}
+	comparators := make([]*column.SortComparator, len(sortingColumns))
+	keys := make([][]interface{}, len(sortingColumns))
+	for k, sorting := range sortingColumns {
+		buffer := w.columns[sorting.ColumnIdx]
+		comparators[k] = column.NewSortComparator(buffer.descr, sorting)
+		keys[k] = buffer.RowValues()
+	}
+	order := make([]int, w.numRows)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		for k, comparator := range comparators {
+			if c := comparator.Compare(keys[k][order[i]], keys[k][order[j]]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	for _, buffer := range w.columns {
+		buffer.PermuteRows(order)
+	}
+}
+
+// Add a key / value pair to the file footer, at any time before Close
+func (w *bufferedWriter) AddKeyValueMetadata(key string, value string) {
+	w.fileWriter.AddKeyValueMetadata(key, value)
+}
+
+// Flush the buffered records and write the file footer
+func (w *bufferedWriter) Close() {
+	w.Flush()
+	w.fileWriter.Close()
+}
+
+// Writer writes Go structs of type T (or pointers to them) to a parquet file.
+// The schema is derived from T with schema.FromStruct. Records are shredded
+// into column buffers and written as a row group once
+// WriterProperties.MaxRowGroupLength rows are buffered, or on Flush / Close.
+// With WriterProperties.SortOnWrite the records of a row group are first
+// sorted by the sorting columns.
+type Writer[T any] struct {
+	*bufferedWriter
+	plan *shredNode
+}
+
+// Panics if T cannot be mapped to a schema
+func NewWriter[T any](sink io.Writer, properties *column.WriterProperties) *Writer[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	w := &Writer[T]{bufferedWriter: newBufferedWriter(sink, schema.MustFromStruct(t), properties)}
+	w.plan = newShredPlan(w.Schema(), t)
+	return w
+}
+
+// Shred a record into the column buffers. A record that cannot be stored
+// is not written, and its error returned.
+func (w *Writer[T]) Write(row T) error {
+	v := reflect.ValueOf(&row).Elem()
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fmt.Errorf("Cannot write a nil record")
+		}
+		v = v.Elem()
+	}
+	if err := w.shred(func() { w.plan.writeContent(w.columns, v, 0, 0) }); err != nil {
+		return err
+	}
+	w.endRecord()
+	return nil
+}
+
+// Write the records up to the first one that cannot be stored, returning its
+// error
+func (w *Writer[T]) WriteRows(rows []T) error {
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RowWriter writes Rows of any schema to a parquet file, see Row for the
+// values accepted.
+type RowWriter struct {
+	*bufferedWriter
+	plan *rowNode
+}
+
+func NewRowWriter(sink io.Writer, root *schema.GroupNode,
+	properties *column.WriterProperties) *RowWriter {
+	w := &RowWriter{bufferedWriter: newBufferedWriter(sink, root, properties)}
+	w.plan = newRowPlan(w.Schema())
+	return w
+}
+
+// Shred a record into the column buffers
+func (w *RowWriter) Write(row Row) {
+	if row == nil {
+		panic(fmt.Errorf("Cannot write a nil record"))
+	}
+	w.plan.writeContent(w.columns, row, 0, 0)
+	w.endRecord()
+}
+
+func (w *RowWriter) WriteRows(rows []Row) {
+	for _, row := range rows {
+		w.Write(row)
+	}
+}
@@ -0,0 +1,102 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/zenixls2/goparquet/column"
+	"github.com/zenixls2/goparquet/file"
+	"github.com/zenixls2/goparquet/ptype"
+)
+
+type concurrentRow struct {
+	Id     int32            `parquet:"id"`
+	Name   *string          `parquet:"name"`
+	Counts map[string]int64 `parquet:"counts"`
+	Nested struct {
+		X int32  `parquet:"x"`
+		Y string `parquet:"y"`
+	} `parquet:"nested"`
+}
+
+func writeConcurrentRows(concurrency int) []byte {
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().WriteConcurrency(concurrency).
+		MaxRowGroupLength(700).DataPagesize(256).EnableBloomFilter().
+		Compression(ptype.Compression_SNAPPY).DisableDictionaryFor("id").Build()
+	writer := NewWriter[concurrentRow](&buffer, properties)
+	for i := 0; i < 2000; i++ {
+		row := concurrentRow{Id: int32(i), Counts: map[string]int64{"k": int64(i % 7)}}
+		if i%3 == 0 {
+			name := fmt.Sprint("name ", i)
+			row.Name = &name
+		}
+		row.Nested.X = int32(i * 10)
+		row.Nested.Y = fmt.Sprint("y", i%50)
+		writer.Write(row)
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func TestConcurrentWriteMatchesSequential(t *testing.T) {
+	sequential := writeConcurrentRows(1)
+	for _, concurrency := range []int{2, 4, 16} {
+		if concurrent := writeConcurrentRows(concurrency); !bytes.Equal(concurrent, sequential) {
+			t.Errorf("concurrency %d: %d bytes differ from the %d sequential ones",
+				concurrency, len(concurrent), len(sequential))
+		}
+	}
+	rows := NewReader[concurrentRow](bytes.NewReader(sequential), int64(len(sequential))).ReadAll()
+	if len(rows) != 2000 || rows[1999].Nested.Y != "y49" || rows[999].Name == nil ||
+		*rows[999].Name != "name 999" {
+		t.Errorf("read %d rows", len(rows))
+	}
+}
+
+type sortedRow struct {
+	Key  *string `parquet:"key"`
+	Seq  int32   `parquet:"seq"`
+	Tags []int32 `parquet:"tags"`
+}
+
+func TestSortOnWrite(t *testing.T) {
+	var buffer bytes.Buffer
+	properties := column.NewWriterPropertiesBuilder().
+		SortingColumns(column.SortingColumn{ColumnIdx: 0, NullsFirst: true},
+			column.SortingColumn{ColumnIdx: 1, Descending: true}).
+		EnableSortingValidation().
+		EnableSortOnWrite().
+		MaxRowGroupLength(5).
+		Build()
+	writer := NewWriter[sortedRow](&buffer, properties)
+	keys := []string{"b", "a", "", "b", "a", "c", "", "a", "c", "b"}
+	for i, key := range keys {
+		row := sortedRow{Seq: int32(i % 3), Tags: []int32{int32(i), int32(i)}}
+		if key != "" {
+			row.Key = stringPointer(key)
+		}
+		writer.Write(row)
+	}
+	writer.Close()
+
+	var actual []string
+	for _, row := range NewReader[sortedRow](bytes.NewReader(buffer.Bytes()), int64(buffer.Len())).ReadAll() {
+		key := "-"
+		if row.Key != nil {
+			key = *row.Key
+		}
+		actual = append(actual, fmt.Sprintf("%s%d:%v", key, row.Seq, row.Tags))
+	}
+	// Each row group of 5 rows is sorted on its own
+	expected := "[-2:[2 2] a1:[1 1] a1:[4 4] b0:[0 0] b0:[3 3] " +
+		"-0:[6 6] a1:[7 7] b0:[9 9] c2:[5 5] c2:[8 8]]"
+	if fmt.Sprint(actual) != expected {
+		t.Errorf("rows %v, want %s", actual, expected)
+	}
+	reader := file.NewParquetFileReaderOpen(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if sorting := reader.RowGroup(1).SortingColumns(); len(sorting) != 2 || !sorting[1].Descending {
+		t.Errorf("sorting columns %v", sorting)
+	}
+}
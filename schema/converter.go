@@ -2,113 +2,111 @@ package schema
 
 import (
 	"fmt"
+	"github.com/zenixls2/goparquet/thrift"
 	"unsafe"
 )
 
-type SchemaElement struct {
-}
-
-type SchemaDescriptor struct {
+type FlatSchemaConverter struct {
+	Elements []*thrift.SchemaElement
+	Length   int
+	Pos      int
 }
 
-type FlatSchemaConverter struct {
-	Elements  []SchemaElement
-	Length    int
-	Pos       int
-	CurrentId int
+func NewFlatSchemaConverter(elements []*thrift.SchemaElement, length int) *FlatSchemaConverter {
+	return &FlatSchemaConverter{
+		Elements: elements,
+		Length:   length,
+		Pos:      0,
+	}
 }
 
 func (f *FlatSchemaConverter) Convert() *Node {
-	root := &f.Elements[0]
+	if f.Length == 0 {
+		panic(fmt.Errorf("Empty schema"))
+	}
+	root := f.Elements[0]
 
 	// Validate the root node
-	if root.NumChildren == 0 {
+	if root.GetNumChildren() == 0 {
 		panic(fmt.Errorf("Root node did not have children"))
 	}
 
 	return f.NextNode()
 }
 
-func (f *FlatSchemaConverter) NextId() int {
-	tmp := f.CurrentId
-	f.CurrentId++
-	return tmp
-}
-
 func (f *FlatSchemaConverter) NextNode() *Node {
 	element := f.Next()
-	nodeId := f.NextId()
+	// Nodes carry the field_id of the element, if any
+	nodeId := -1
+	if element.IsSetFieldID() {
+		nodeId = int(element.GetFieldID())
+	}
 	opaqueElement := element
-	if element.NumChildren == 0 {
+	if element.GetNumChildren() == 0 {
 		// Leaf (primitive node)
 		return PrimitiveNodeFromParquet(opaqueElement, nodeId)
 	} else {
 		// Group
-		var fields []Node
-		for i := 0; i < element.NumChildren; i++ {
+		var fields []*Node
+		for i := 0; i < int(element.GetNumChildren()); i++ {
 			field := f.NextNode()
-			fields = append(fields, *field)
+			fields = append(fields, field)
 		}
 		return GroupNodeFromParquet(opaqueElement, nodeId, fields)
 	}
 }
 
-func (f *FlatSchemaConverter) Next() *SchemaElement {
+func (f *FlatSchemaConverter) Next() *thrift.SchemaElement {
 	if f.Pos == f.Length {
 		panic(fmt.Errorf("Malformed schema: not enough SchemaElement values"))
 	}
 	pos := f.Pos
 	f.Pos++
-	return &f.Elements[pos]
+	return f.Elements[pos]
 }
 
-func FromParquet(schema []SchemaElement) *SchemaDescriptor {
-	converter := NewFlatSchemaConverter(&schema[0], len(schema))
+func FromParquet(schema []*thrift.SchemaElement) *SchemaDescriptor {
+	converter := NewFlatSchemaConverter(schema, len(schema))
 	root := converter.Convert()
-	descr := &SchemaDescriptor{}
-	descr.Init(root.Release())
-
-	return descr
+	if converter.Pos != converter.Length {
+		panic(fmt.Errorf("Malformed schema: %d SchemaElement values left over",
+			converter.Length-converter.Pos))
+	}
+	return NewSchemaDescriptor(root)
 }
 
-func ToParquet(schema *GroupNode, out []SchemaElement) {
-	flattener := NewSchemaFlattener(schema, out)
+func ToParquet(schema *GroupNode) []*thrift.SchemaElement {
+	flattener := NewSchemaFlattener(schema)
 	flattener.Flatten()
+	return flattener.Elements
+}
+
+type SchemaFlattener struct {
+	Root     *GroupNode
+	Elements []*thrift.SchemaElement
+}
+
+func NewSchemaFlattener(schema *GroupNode) *SchemaFlattener {
+	sf := SchemaFlattener{}
+	sf.Root = schema
+	return &sf
 }
 
-type SchemaVisitor struct {
-	NodeConstVisitor
-	Elements []SchemaElement
+func (sf *SchemaFlattener) Flatten() {
+	sf.Elements = nil
+	sf.Visit(&sf.Root.Node)
 }
 
-func (sv *SchemaVisitor) Visit(node *Node) {
-	element := SchemaElement{}
-	node.ToParquet(&element)
-	// Override fieldId here as we can get user-generated Nodes without a valid id
-	element.SetFieldId(len(sv.Elements))
-	sv.Elements = append(sv.Elements, element)
+// Schema elements are stored depth first, each group followed by its children
+func (sf *SchemaFlattener) Visit(node *Node) {
+	element := thrift.NewSchemaElement()
+	node.ToParquet(element)
+	sf.Elements = append(sf.Elements, element)
 
 	if node.IsGroup() {
 		groupNode := (*GroupNode)(unsafe.Pointer(node))
 		for i := 0; i < groupNode.FieldCount(); i++ {
-			groupNode.Field(i).VisitConst((*NodeConstVisitor)(unsafe.Pointer(sv)))
+			sf.Visit(groupNode.Field(i))
 		}
 	}
 }
-
-func NewSchemaVisitor(elements []SchemaElement) *SchemaVisitor {
-	return &SchemaVisitor{
-		Elements: elements,
-	}
-}
-
-type SchemaFlattener struct {
-	Elements []SchemaElement
-}
-
-func NewSchemaFlattener(schema *GroupNode, out []SchemaElement) *SchemaFlattener {
-	sf := SchemaFlattener{}
-	sf.Root = schema
-	sf.Elements = out
-	return &sf
-}
@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"unsafe"
+)
+
+// The ColumnDescriptor encapsulates information necessary to interpret
+// primitive column data in the context of a particular schema. We have to
+// examine the node structure of a column's path to the root in the schema tree
+// to be able to reassemble the nested structure from the repetition and
+// definition levels.
+type ColumnDescriptor struct {
+	node               *Node
+	primitiveNode      *PrimitiveNode
+	maxDefinitionLevel int16
+	maxRepetitionLevel int16
+	schemaDescr        *SchemaDescriptor
+}
+
+func NewColumnDescriptor(node *Node, maxDefinitionLevel int16,
+	maxRepetitionLevel int16, schemaDescr *SchemaDescriptor) *ColumnDescriptor {
+	if !node.IsPrimitive() {
+		panic(fmt.Errorf("Must be a primitive type"))
+	}
+	return &ColumnDescriptor{
+		node:               node,
+		primitiveNode:      (*PrimitiveNode)(unsafe.Pointer(node)),
+		maxDefinitionLevel: maxDefinitionLevel,
+		maxRepetitionLevel: maxRepetitionLevel,
+		schemaDescr:        schemaDescr,
+	}
+}
+
+func (c *ColumnDescriptor) MaxDefinitionLevel() int16 {
+	return c.maxDefinitionLevel
+}
+
+func (c *ColumnDescriptor) MaxRepetitionLevel() int16 {
+	return c.maxRepetitionLevel
+}
+
+func (c *ColumnDescriptor) PhysicalType() ptype.Type {
+	return c.primitiveNode.PhysicalType()
+}
+
+func (c *ColumnDescriptor) LogicalType() ptype.LogicalType {
+	return c.primitiveNode.LogicalType()
+}
+
+func (c *ColumnDescriptor) Name() string {
+	return c.primitiveNode.Name()
+}
+
+func (c *ColumnDescriptor) Path() *ColumnPath {
+	return ColumnPathFromNode(c.node)
+}
+
+func (c *ColumnDescriptor) SchemaNode() *Node {
+	return c.node
+}
+
+func (c *ColumnDescriptor) TypeLength() int32 {
+	return c.primitiveNode.TypeLength()
+}
+
+func (c *ColumnDescriptor) TypePrecision() int32 {
+	return c.primitiveNode.DecimalMetadata().Precision
+}
+
+func (c *ColumnDescriptor) TypeScale() int32 {
+	return c.primitiveNode.DecimalMetadata().Scale
+}
+
+// Container for the converted Parquet schema with a computed information from
+// the schema analysis needed for file reading
+//
+// * Column index to Node
+// * Max repetition / definition levels for each primitive node
+//
+// The ColumnDescriptor objects produced by this class can be used to assist in
+// the reconstruction of fully materialized data structures from the
+// repetition-definition level encoding of nested data
+type SchemaDescriptor struct {
+	schema    *Node
+	groupNode *GroupNode
+	// Result of leaf node / tree analysis
+	leaves []*ColumnDescriptor
+	// Mapping between leaf nodes and root group of leaf (first node
+	// below the schema's root group)
+	//
+	// For example, the leaf `a.b.c.d` would have a link back to `a`
+	//
+	// -- a  <------
+	// -- -- b     |
+	// -- -- -- c  |
+	// -- -- -- -- d
+	leafToBase map[int]*Node
+	// Mapping between ColumnPath DotString to the leaf index
+	leafToIdx map[string]int
+}
+
+func NewSchemaDescriptor(schema *Node) *SchemaDescriptor {
+	descr := &SchemaDescriptor{}
+	descr.Init(schema)
+	return descr
+}
+
+func (s *SchemaDescriptor) Init(schema *Node) {
+	s.schema = schema
+	if !schema.IsGroup() {
+		panic(fmt.Errorf("Must initialize with a schema group"))
+	}
+	s.groupNode = (*GroupNode)(unsafe.Pointer(schema))
+	s.leaves = nil
+	s.leafToBase = make(map[int]*Node)
+	s.leafToIdx = make(map[string]int)
+
+	for i := 0; i < s.groupNode.FieldCount(); i++ {
+		s.BuildTree(s.groupNode.Field(i), 0, 0, s.groupNode.Field(i))
+	}
+}
+
+func (s *SchemaDescriptor) BuildTree(node *Node, maxDefLevel int16,
+	maxRepLevel int16, base *Node) {
+	if node.IsOptional() {
+		maxDefLevel++
+	} else if node.IsRepeated() {
+		// Repeated fields add a definition level. This is used to distinguish
+		// between an empty list and a list with an item in it.
+		maxRepLevel++
+		maxDefLevel++
+	}
+
+	// Now, walk the schema and create a ColumnDescriptor for each leaf node
+	if node.IsGroup() {
+		group := (*GroupNode)(unsafe.Pointer(node))
+		for i := 0; i < group.FieldCount(); i++ {
+			s.BuildTree(group.Field(i), maxDefLevel, maxRepLevel, base)
+		}
+	} else {
+		// Primitive node, append to leaves
+		s.leafToIdx[ColumnPathFromNode(node).ToDotString()] = len(s.leaves)
+		s.leafToBase[len(s.leaves)] = base
+		s.leaves = append(s.leaves,
+			NewColumnDescriptor(node, maxDefLevel, maxRepLevel, s))
+	}
+}
+
+func (s *SchemaDescriptor) Column(i int) *ColumnDescriptor {
+	if i < 0 || i >= len(s.leaves) {
+		panic(fmt.Errorf("Column index %d out of range [0, %d)", i, len(s.leaves)))
+	}
+	return s.leaves[i]
+}
+
+// Get the index of a column by its dotstring path, or -1 if not found
+func (s *SchemaDescriptor) ColumnIndex(path string) int {
+	if i, ok := s.leafToIdx[path]; ok {
+		return i
+	}
+	return -1
+}
+
+// The number of physical columns appearing in the file
+func (s *SchemaDescriptor) NumColumns() int {
+	return len(s.leaves)
+}
+
+func (s *SchemaDescriptor) SchemaRoot() *Node {
+	return s.schema
+}
+
+func (s *SchemaDescriptor) GroupNode() *GroupNode {
+	return s.groupNode
+}
+
+// Returns the root (child of the schema root) node of the leaf(column) node
+func (s *SchemaDescriptor) GetColumnRoot(i int) *Node {
+	return s.leafToBase[i]
+}
+
+func (s *SchemaDescriptor) Name() string {
+	return s.groupNode.Name()
+}
+
+// Whether the schemas have the same fields, whatever the name of their root
+func (s *SchemaDescriptor) Equals(other *SchemaDescriptor) bool {
+	return s.NumColumns() == other.NumColumns() && s.groupNode.EqualsInternal(other.groupNode)
+}
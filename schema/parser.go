@@ -0,0 +1,246 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Parser for the textual schema format used by parquet-mr's
+// MessageTypeParser and parquet-tools, e.g.
+//
+//   message m {
+//     required int64 id = 1;
+//     optional binary name (UTF8);
+//     optional fixed_len_byte_array(16) price (DECIMAL(38,9));
+//     optional group tags (LIST) {
+//       repeated binary element (UTF8);
+//     }
+//   }
+
+const schemaDelimiters = "{}();,="
+
+type schemaTokenizer struct {
+	tokens []string
+	pos    int
+	line   []int
+}
+
+func newSchemaTokenizer(text string) *schemaTokenizer {
+	t := &schemaTokenizer{}
+	lineNo := 1
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			t.tokens = append(t.tokens, text[start:end])
+			t.line = append(t.line, lineNo)
+			start = -1
+		}
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			flush(i)
+			if c == '\n' {
+				lineNo++
+			}
+		case strings.IndexByte(schemaDelimiters, c) >= 0:
+			flush(i)
+			t.tokens = append(t.tokens, text[i:i+1])
+			t.line = append(t.line, lineNo)
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	flush(len(text))
+	return t
+}
+
+func (t *schemaTokenizer) HasMore() bool {
+	return t.pos < len(t.tokens)
+}
+
+func (t *schemaTokenizer) Peek() string {
+	if !t.HasMore() {
+		return ""
+	}
+	return t.tokens[t.pos]
+}
+
+func (t *schemaTokenizer) Next() string {
+	if !t.HasMore() {
+		panic(fmt.Errorf("Malformed schema: unexpected end of input"))
+	}
+	token := t.tokens[t.pos]
+	t.pos++
+	return token
+}
+
+func (t *schemaTokenizer) Expect(expected string) {
+	token := t.Next()
+	if token != expected {
+		t.Fail(fmt.Sprintf("expected '%s' but got '%s'", expected, token))
+	}
+}
+
+func (t *schemaTokenizer) Fail(msg string) {
+	lineNo := 0
+	if t.pos > 0 {
+		lineNo = t.line[t.pos-1]
+	}
+	panic(fmt.Errorf("Malformed schema at line %d: %s", lineNo, msg))
+}
+
+func (t *schemaTokenizer) NextInt() int {
+	token := t.Next()
+	value, err := strconv.Atoi(token)
+	if err != nil {
+		t.Fail(fmt.Sprintf("expected a number but got '%s'", token))
+	}
+	return value
+}
+
+var (
+	physicalTypeNames = map[string]ptype.Type{
+		"boolean":              ptype.Type_BOOLEAN,
+		"int32":                ptype.Type_INT32,
+		"int64":                ptype.Type_INT64,
+		"int96":                ptype.Type_INT96,
+		"float":                ptype.Type_FLOAT,
+		"double":               ptype.Type_DOUBLE,
+		"binary":               ptype.Type_BYTE_ARRAY,
+		"fixed_len_byte_array": ptype.Type_FIXED_LEN_BYTE_ARRAY,
+	}
+	repetitionNames = map[string]ptype.Repetition{
+		"required": ptype.Repetition_REQUIRED,
+		"optional": ptype.Repetition_OPTIONAL,
+		"repeated": ptype.Repetition_REPEATED,
+	}
+	logicalTypeNames = map[string]ptype.LogicalType{}
+)
+
+func init() {
+	for t := ptype.LogicalType_UTF8; t <= ptype.LogicalType_INTERVAL; t++ {
+		logicalTypeNames[ptype.LogicalTypeToString(t)] = t
+	}
+}
+
+// Parse reads a schema in the message syntax and returns the root group.
+// Malformed input returns an error describing the offending line.
+func Parse(text string) (root *GroupNode, err error) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			failed, ok := failure.(error)
+			if !ok {
+				panic(failure)
+			}
+			root, err = nil, failed
+		}
+	}()
+	return parseMessage(text), nil
+}
+
+// Parse for schemas known to be valid, panics on malformed input
+func MustParse(text string) *GroupNode {
+	root, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return root
+}
+
+func parseMessage(text string) *GroupNode {
+	tokenizer := newSchemaTokenizer(text)
+	if token := tokenizer.Next(); strings.ToLower(token) != "message" {
+		tokenizer.Fail(fmt.Sprintf("expected 'message' but got '%s'", token))
+	}
+	name := tokenizer.Next()
+	fields := parseGroupFields(tokenizer)
+	if tokenizer.HasMore() {
+		tokenizer.Fail(fmt.Sprintf("unexpected '%s' after message", tokenizer.Peek()))
+	}
+	return NewGroupNode(name, ptype.Repetition_REQUIRED, fields)
+}
+
+func parseGroupFields(tokenizer *schemaTokenizer) []*Node {
+	var fields []*Node
+	tokenizer.Expect("{")
+	for tokenizer.Peek() != "}" {
+		fields = append(fields, parseField(tokenizer))
+	}
+	tokenizer.Expect("}")
+	return fields
+}
+
+func parseField(tokenizer *schemaTokenizer) *Node {
+	token := tokenizer.Next()
+	repetition, ok := repetitionNames[strings.ToLower(token)]
+	if !ok {
+		tokenizer.Fail(fmt.Sprintf("unknown repetition '%s'", token))
+	}
+
+	token = tokenizer.Next()
+	if strings.ToLower(token) == "group" {
+		name := tokenizer.Next()
+		logicalType, _, _ := parseLogicalType(tokenizer)
+		id := parseFieldId(tokenizer)
+		fields := parseGroupFields(tokenizer)
+		return GroupNodeMake(name, repetition, fields, int(logicalType), id)
+	}
+
+	physicalType, ok := physicalTypeNames[strings.ToLower(token)]
+	if !ok {
+		tokenizer.Fail(fmt.Sprintf("unknown type '%s'", token))
+	}
+	length := -1
+	if physicalType == ptype.Type_FIXED_LEN_BYTE_ARRAY {
+		tokenizer.Expect("(")
+		length = tokenizer.NextInt()
+		tokenizer.Expect(")")
+	}
+	name := tokenizer.Next()
+	logicalType, precision, scale := parseLogicalType(tokenizer)
+	id := parseFieldId(tokenizer)
+	tokenizer.Expect(";")
+	return (*Node)(unsafe.Pointer(NewPrimitiveNode(name, repetition, physicalType,
+		int(logicalType), length, precision, scale, id)))
+}
+
+// Parses an optional "(LOGICAL)" or "(DECIMAL(precision,scale))" annotation
+func parseLogicalType(tokenizer *schemaTokenizer) (ptype.LogicalType, int, int) {
+	if tokenizer.Peek() != "(" {
+		return ptype.LogicalType_NONE, -1, -1
+	}
+	tokenizer.Next()
+	token := tokenizer.Next()
+	logicalType, ok := logicalTypeNames[strings.ToUpper(token)]
+	if !ok {
+		tokenizer.Fail(fmt.Sprintf("unknown logical type '%s'", token))
+	}
+	precision, scale := -1, -1
+	if logicalType == ptype.LogicalType_DECIMAL {
+		tokenizer.Expect("(")
+		precision = tokenizer.NextInt()
+		scale = 0
+		if tokenizer.Peek() == "," {
+			tokenizer.Next()
+			scale = tokenizer.NextInt()
+		}
+		tokenizer.Expect(")")
+	}
+	tokenizer.Expect(")")
+	return logicalType, precision, scale
+}
+
+func parseFieldId(tokenizer *schemaTokenizer) int {
+	if tokenizer.Peek() != "=" {
+		return -1
+	}
+	tokenizer.Next()
+	return tokenizer.NextInt()
+}
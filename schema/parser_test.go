@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/zenixls2/goparquet/ptype"
+)
+
+const printedSchema = `message document {
+  required int64 id = 1;
+  optional binary name (UTF8);
+  optional fixed_len_byte_array(16) price (DECIMAL(38,9)) = 7;
+  optional group links = 2 {
+    repeated int64 backward;
+    repeated int64 forward;
+  }
+  repeated group names {
+    repeated group languages (LIST) {
+      repeated group list {
+        required binary code (UTF8);
+        optional binary country (ENUM);
+      }
+    }
+    optional binary url (UTF8) = 12;
+  }
+  optional int32 day (DATE);
+  required int64 time (TIMESTAMP_MICROS);
+}
+`
+
+func TestParsePrintRoundTrip(t *testing.T) {
+	root := MustParse(printedSchema)
+	if printed := Print(&root.Node); printed != printedSchema {
+		t.Errorf("printed schema:\n%s\nwant:\n%s", printed, printedSchema)
+	}
+	if !MustParse(Print(&root.Node)).Equals(&root.Node) {
+		t.Errorf("reparsed schema differs")
+	}
+
+	descr := NewSchemaDescriptor(&root.Node)
+	if descr.NumColumns() != 10 {
+		t.Fatalf("%d columns, want 10", descr.NumColumns())
+	}
+	code := descr.Column(5)
+	if path := code.Path().ToDotString(); path != "names.languages.list.code" {
+		t.Errorf("column 5 is %s", path)
+	}
+	if code.MaxDefinitionLevel() != 3 || code.MaxRepetitionLevel() != 3 {
+		t.Errorf("names.languages.list.code levels: def %d, rep %d",
+			code.MaxDefinitionLevel(), code.MaxRepetitionLevel())
+	}
+
+	price := (*PrimitiveNode)(unsafe.Pointer(root.Field(2)))
+	if price.TypeLength() != 16 || price.LogicalType() != ptype.LogicalType_DECIMAL ||
+		price.DecimalMetadata().Precision != 38 || price.DecimalMetadata().Scale != 9 {
+		t.Errorf("price: length %d, %s(%d,%d)", price.TypeLength(),
+			ptype.LogicalTypeToString(price.LogicalType()),
+			price.DecimalMetadata().Precision, price.DecimalMetadata().Scale)
+	}
+	names := (*GroupNode)(unsafe.Pointer(root.Field(4)))
+	for _, test := range []struct {
+		node *Node
+		id   int
+	}{{root.Field(0), 1}, {root.Field(1), -1}, {root.Field(2), 7}, {root.Field(3), 2},
+		{names.Field(1), 12}} {
+		if test.node.Id() != test.id {
+			t.Errorf("%s: field_id %d, want %d", test.node.Name(), test.node.Id(), test.id)
+		}
+	}
+	if languages := names.Field(0); !languages.IsRepeated() ||
+		languages.LogicalType() != ptype.LogicalType_LIST {
+		t.Errorf("languages: %s (%s)", ptype.RepetitionToString(languages.Repetition()),
+			ptype.LogicalTypeToString(languages.LogicalType()))
+	}
+}
+
+func TestParsePrintRoundTrips(t *testing.T) {
+	tests := []string{
+		// Repeated groups, with and without a logical type and a field_id
+		`message m {
+  repeated group a = 3 {
+    repeated group b (LIST) = 4 {
+      required int32 c = 5;
+    }
+  }
+}
+`,
+		`message m {
+  optional group counts (MAP) = 1 {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (UTF8) = 2;
+      optional int64 value (UINT_64) = 3;
+    }
+  }
+}
+`,
+		// Logical types with parameters
+		`message m {
+  required int32 small (DECIMAL(9,2));
+  required int64 large (DECIMAL(18,0)) = 7;
+  optional binary any (DECIMAL(40,10));
+  optional fixed_len_byte_array(12) interval (INTERVAL);
+  optional int32 time (TIME_MILLIS) = 0;
+}
+`,
+	}
+	for _, text := range tests {
+		root, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", text, err)
+		}
+		if printed := Print(&root.Node); printed != text {
+			t.Errorf("printed schema:\n%s\nwant:\n%s", printed, text)
+		}
+		if again := MustParse(Print(&root.Node)); !again.Equals(&root.Node) {
+			t.Errorf("reparsed schema differs:\n%s", Print(&again.Node))
+		}
+	}
+}
+
+func TestParseIsCaseInsensitive(t *testing.T) {
+	root := MustParse("MESSAGE m { REQUIRED INT32 a (date); Optional Binary b (Decimal(9)); }")
+	expected := "message m {\n  required int32 a (DATE);\n  optional binary b (DECIMAL(9,0));\n}\n"
+	if printed := Print(&root.Node); printed != expected {
+		t.Errorf("printed %q, want %q", printed, expected)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		text    string
+		message string
+	}{
+		{"schema m {}", "expected 'message' but got 'schema'"},
+		{"message m {\n  required int32 a;\n  often int32 b;\n}", "line 3: unknown repetition 'often'"},
+		{"message m {\n  required integer a;\n}", "line 2: unknown type 'integer'"},
+		{"message m {\n  required int32 a (DAY);\n}", "line 2: unknown logical type 'DAY'"},
+		{"message m { required fixed_len_byte_array(x) a; }", "expected a number but got 'x'"},
+		{"message m { required int32 a }", "expected ';' but got '}'"},
+		{"message m { required int32 a;", "unexpected end of input"},
+		{"message m {} }", "unexpected '}' after message"},
+		{"message m { required int64 a (UTF8); }", "UTF8"},
+		{"message m { optional binary a (DECIMAL(4,5)); }", "DECIMAL"},
+	}
+	for _, test := range tests {
+		root, err := Parse(test.text)
+		if root != nil || err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Parse(%q): %v, want %q", test.text, err, test.message)
+		}
+	}
+}
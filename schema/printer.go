@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+type SchemaPrinter struct {
+	Stream      io.Writer
+	IndentWidth int
+	Indent      int
+}
+
+func (sp *SchemaPrinter) Visit(node *Node) {
+	sp.writeIndent()
+	if node.IsGroup() {
+		sp.visitGroup((*GroupNode)(unsafe.Pointer(node)))
+	} else {
+		sp.visitPrimitive((*PrimitiveNode)(unsafe.Pointer(node)))
+	}
+}
+
+func (sp *SchemaPrinter) visitPrimitive(node *PrimitiveNode) {
+	fmt.Fprintf(sp.Stream, "%s %s",
+		strings.ToLower(ptype.RepetitionToString(node.Repetition())),
+		printPhysicalType(node))
+	fmt.Fprintf(sp.Stream, " %s", node.Name())
+	if node.LogicalType() != ptype.LogicalType_NONE {
+		fmt.Fprintf(sp.Stream, " (%s", ptype.LogicalTypeToString(node.LogicalType()))
+		if node.LogicalType() == ptype.LogicalType_DECIMAL {
+			fmt.Fprintf(sp.Stream, "(%d,%d)", node.DecimalMetadata().Precision,
+				node.DecimalMetadata().Scale)
+		}
+		fmt.Fprintf(sp.Stream, ")")
+	}
+	sp.printFieldId(&node.Node)
+	fmt.Fprintf(sp.Stream, ";\n")
+}
+
+func (sp *SchemaPrinter) visitGroup(node *GroupNode) {
+	if node.Parent() == nil {
+		fmt.Fprintf(sp.Stream, "message %s", node.Name())
+	} else {
+		fmt.Fprintf(sp.Stream, "%s group %s",
+			strings.ToLower(ptype.RepetitionToString(node.Repetition())),
+			node.Name())
+		if node.LogicalType() != ptype.LogicalType_NONE {
+			fmt.Fprintf(sp.Stream, " (%s)", ptype.LogicalTypeToString(node.LogicalType()))
+		}
+		sp.printFieldId(&node.Node)
+	}
+	fmt.Fprintf(sp.Stream, " {\n")
+
+	sp.Indent += sp.IndentWidth
+	for i := 0; i < node.FieldCount(); i++ {
+		sp.Visit(node.Field(i))
+	}
+	sp.Indent -= sp.IndentWidth
+	sp.writeIndent()
+	fmt.Fprintf(sp.Stream, "}\n")
+}
+
+func (sp *SchemaPrinter) printFieldId(node *Node) {
+	if node.Id() >= 0 {
+		fmt.Fprintf(sp.Stream, " = %d", node.Id())
+	}
+}
+
+func (sp *SchemaPrinter) writeIndent() {
+	io.WriteString(sp.Stream, strings.Repeat(" ", sp.Indent))
+}
+
+func printPhysicalType(node *PrimitiveNode) string {
+	switch node.PhysicalType() {
+	case ptype.Type_BYTE_ARRAY:
+		return "binary"
+	case ptype.Type_FIXED_LEN_BYTE_ARRAY:
+		return fmt.Sprintf("fixed_len_byte_array(%d)", node.TypeLength())
+	default:
+		return strings.ToLower(ptype.TypeToString(node.PhysicalType()))
+	}
+}
+
+func NewSchemaPrinter(stream io.Writer, indentWidth int) *SchemaPrinter {
+	return &SchemaPrinter{
+		Stream:      stream,
+		IndentWidth: indentWidth,
+		Indent:      0,
+	}
+}
+
+// PrintSchema writes node in the message syntax accepted by Parse. A node
+// without a parent is rendered as the enclosing "message".
+func PrintSchema(node *Node, stream io.Writer, indentWidth int) {
+	printer := NewSchemaPrinter(stream, indentWidth)
+	printer.Visit(node)
+}
+
+func Print(node *Node) string {
+	var buffer bytes.Buffer
+	PrintSchema(node, &buffer, 2)
+	return buffer.String()
+}
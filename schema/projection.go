@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"strings"
+	"unsafe"
+)
+
+// A Projection is the subset of the columns of a schema that is read. Its
+// schema keeps the nodes of the original schema leading to the selected
+// leaves, with the same repetition so the leaves keep their levels.
+type Projection struct {
+	descr *SchemaDescriptor
+	// The column of the original schema of each leaf of the projection
+	columns []int
+}
+
+// Project the columns at the paths, or under them for the paths of groups.
+// Maps are kept whole as their entries need both keys and values.
+func ProjectColumns(descr *SchemaDescriptor, paths []*ColumnPath) *Projection {
+	selected := make([]bool, descr.NumColumns())
+	for _, path := range paths {
+		dotString := path.ToDotString()
+		found := false
+		for i := 0; i < descr.NumColumns(); i++ {
+			leaf := descr.Column(i).Path().ToDotString()
+			if leaf == dotString || strings.HasPrefix(leaf, dotString+".") {
+				selected[i] = true
+				found = true
+			}
+		}
+		if !found {
+			panic(fmt.Errorf("Projected column %s is not in the schema", dotString))
+		}
+	}
+	root := descr.GroupNode()
+	var fields []*Node
+	for i := 0; i < root.FieldCount(); i++ {
+		if field := pruneNode(descr, root.Field(i), selected, false); field != nil {
+			fields = append(fields, field)
+		}
+	}
+	return ProjectSchema(descr, NewGroupNode(root.Name(), root.Repetition(), fields,
+		int(root.LogicalType()), root.Id()))
+}
+
+// A copy of node with only the selected leaves, nil without any
+func pruneNode(descr *SchemaDescriptor, node *Node, selected []bool, whole bool) *Node {
+	if node.IsPrimitive() {
+		if !whole && !selected[descr.ColumnIndex(ColumnPathFromNode(node).ToDotString())] {
+			return nil
+		}
+		primitive := *(*PrimitiveNode)(unsafe.Pointer(node))
+		return (*Node)(unsafe.Pointer(&primitive))
+	}
+	group := (*GroupNode)(unsafe.Pointer(node))
+	if !whole && node.LogicalType() == ptype.LogicalType_MAP {
+		for i := 0; i < group.FieldCount(); i++ {
+			if pruneNode(descr, group.Field(i), selected, false) != nil {
+				whole = true
+			}
+		}
+	}
+	var fields []*Node
+	for i := 0; i < group.FieldCount(); i++ {
+		if field := pruneNode(descr, group.Field(i), selected, whole); field != nil {
+			fields = append(fields, field)
+		}
+	}
+	if fields == nil {
+		return nil
+	}
+	return GroupNodeMake(node.Name(), node.Repetition(), fields, int(node.LogicalType()),
+		node.Id())
+}
+
+// Project the columns of a schema holding a subset of the nodes of descr,
+// e.g. a pruned copy of its GroupNode. Panics if a leaf is not in descr or
+// differs in type or levels.
+func ProjectSchema(descr *SchemaDescriptor, node *GroupNode) *Projection {
+	projection := &Projection{descr: NewSchemaDescriptor((*Node)(unsafe.Pointer(node)))}
+	for i := 0; i < projection.descr.NumColumns(); i++ {
+		leaf := projection.descr.Column(i)
+		path := leaf.Path().ToDotString()
+		column := descr.ColumnIndex(path)
+		if column < 0 {
+			panic(fmt.Errorf("Projected column %s is not in the schema", path))
+		}
+		original := descr.Column(column)
+		if leaf.PhysicalType() != original.PhysicalType() ||
+			leaf.MaxDefinitionLevel() != original.MaxDefinitionLevel() ||
+			leaf.MaxRepetitionLevel() != original.MaxRepetitionLevel() {
+			panic(fmt.Errorf("Projected column %s does not match the schema", path))
+		}
+		projection.columns = append(projection.columns, column)
+	}
+	return projection
+}
+
+// The schema of the projected columns
+func (p *Projection) Schema() *SchemaDescriptor {
+	return p.descr
+}
+
+func (p *Projection) NumColumns() int {
+	return len(p.columns)
+}
+
+// The column of the original schema of column i of the projection
+func (p *Projection) Column(i int) int {
+	return p.columns[i]
+}
@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const projectedSchema = `message projected {
+  required int64 id;
+  optional group name {
+    optional binary first (UTF8);
+    optional binary last (UTF8);
+  }
+  optional group counts (MAP) {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (UTF8);
+      optional int32 value;
+    }
+  }
+  repeated group links {
+    required int64 target;
+    optional binary label (UTF8);
+  }
+}
+`
+
+func TestProjectColumns(t *testing.T) {
+	descr := NewSchemaDescriptor(&MustParse(projectedSchema).Node)
+	tests := []struct {
+		paths    []string
+		columns  []int
+		expected string
+	}{
+		{[]string{"id"}, []int{0}, "message projected {\n  required int64 id;\n}\n"},
+		// Groups select the columns under them, in schema order
+		{[]string{"links", "name.last"}, []int{2, 5, 6}, `message projected {
+  optional group name {
+    optional binary last (UTF8);
+  }
+  repeated group links {
+    required int64 target;
+    optional binary label (UTF8);
+  }
+}
+`},
+		// Maps are kept whole
+		{[]string{"counts.key_value.value"}, []int{3, 4}, `message projected {
+  optional group counts (MAP) {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (UTF8);
+      optional int32 value;
+    }
+  }
+}
+`},
+	}
+	for _, test := range tests {
+		var paths []*ColumnPath
+		for _, path := range test.paths {
+			paths = append(paths, ColumnPathFromDotString(path))
+		}
+		projection := ProjectColumns(descr, paths)
+		if printed := Print(projection.Schema().SchemaRoot()); printed != test.expected {
+			t.Errorf("%v: projected\n%s\nwant\n%s", test.paths, printed, test.expected)
+		}
+		var columns []int
+		for i := 0; i < projection.NumColumns(); i++ {
+			columns = append(columns, projection.Column(i))
+			leaf, original := projection.Schema().Column(i), descr.Column(projection.Column(i))
+			if leaf.MaxDefinitionLevel() != original.MaxDefinitionLevel() ||
+				leaf.MaxRepetitionLevel() != original.MaxRepetitionLevel() {
+				t.Errorf("%v: the levels of %s changed", test.paths, leaf.Path().ToDotString())
+			}
+		}
+		if fmt.Sprint(columns) != fmt.Sprint(test.columns) {
+			t.Errorf("%v: columns %v, want %v", test.paths, columns, test.columns)
+		}
+	}
+}
+
+func projectPanic(project func()) (message string) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			message = fmt.Sprint(failure)
+		}
+	}()
+	project()
+	return ""
+}
+
+func TestProjectSchema(t *testing.T) {
+	descr := NewSchemaDescriptor(&MustParse(projectedSchema).Node)
+	projection := ProjectSchema(descr, MustParse(`message m {
+  repeated group links {
+    optional binary label (UTF8);
+  }
+  required int64 id;
+}`))
+	if projection.NumColumns() != 2 || projection.Column(0) != 6 || projection.Column(1) != 0 {
+		t.Errorf("projected columns %d and %d", projection.Column(0), projection.Column(1))
+	}
+
+	tests := []struct {
+		project func()
+		message string
+	}{
+		{func() { ProjectColumns(descr, []*ColumnPath{ColumnPathFromDotString("nam")}) },
+			"Projected column nam is not in the schema"},
+		{func() { ProjectSchema(descr, MustParse("message m { required int64 other; }")) },
+			"Projected column other is not in the schema"},
+		{func() { ProjectSchema(descr, MustParse("message m { required int32 id; }")) },
+			"Projected column id does not match the schema"},
+		{func() {
+			ProjectSchema(descr, MustParse("message m { required group name { optional binary first (UTF8); } }"))
+		},
+			"Projected column name.first does not match the schema"},
+	}
+	for _, test := range tests {
+		if message := projectPanic(test.project); !strings.Contains(message, test.message) {
+			t.Errorf("%q, want %q", message, test.message)
+		}
+	}
+}
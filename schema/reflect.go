@@ -0,0 +1,325 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// Struct tags understood by FromStruct, e.g.
+//
+//   type Event struct {
+//     Id      int64             `parquet:"id,id=1"`
+//     Name    *string           `parquet:"name"`
+//     Payload []byte            `parquet:"payload,optional,logical=json"`
+//     Price   int64             `parquet:"price,logical=decimal,precision=18,scale=2"`
+//     Tags    []string          `parquet:"tags"`
+//     Attrs   map[string]string `parquet:"attrs,optional"`
+//     Secret  string            `parquet:"-"`
+//   }
+//
+// The first element is the column name (defaults to the Go field name).
+// Pointers are optional; slices become LIST groups (or a bare repeated
+// field with the "repeated" option); maps become MAP groups; nested structs
+// become groups; time.Time is a TIMESTAMP_MICROS unless another logical
+// type is given.
+
+type FieldTag struct {
+	Name       string
+	Skip       bool
+	Optional   bool
+	Required   bool
+	Repeated   bool
+	Logical    ptype.LogicalType
+	HasLogical bool
+	Precision  int
+	Scale      int
+	Id         int
+}
+
+func ParseFieldTag(field reflect.StructField) FieldTag {
+	tag := FieldTag{
+		Name:      field.Name,
+		Precision: -1,
+		Scale:     -1,
+		Id:        -1,
+	}
+	value, ok := field.Tag.Lookup("parquet")
+	if !ok {
+		return tag
+	}
+	if value == "-" {
+		tag.Skip = true
+		return tag
+	}
+	options := strings.Split(value, ",")
+	if options[0] != "" {
+		tag.Name = options[0]
+	}
+	for _, option := range options[1:] {
+		key, arg := option, ""
+		if i := strings.IndexByte(option, '='); i >= 0 {
+			key, arg = option[:i], option[i+1:]
+		}
+		switch strings.TrimSpace(key) {
+		case "optional":
+			tag.Optional = true
+		case "required":
+			tag.Required = true
+		case "repeated":
+			tag.Repeated = true
+		case "logical":
+			tag.Logical = parseTagLogicalType(field, arg)
+			tag.HasLogical = true
+		case "precision":
+			tag.Precision = parseTagInt(field, key, arg)
+		case "scale":
+			tag.Scale = parseTagInt(field, key, arg)
+		case "id":
+			tag.Id = parseTagInt(field, key, arg)
+		case "":
+		default:
+			panic(fmt.Errorf("Unknown parquet tag option '%s' on field %s", key, field.Name))
+		}
+	}
+	if tag.Optional && tag.Required {
+		panic(fmt.Errorf("Field %s cannot be both optional and required", field.Name))
+	}
+	return tag
+}
+
+func parseTagLogicalType(field reflect.StructField, name string) ptype.LogicalType {
+	if strings.EqualFold(name, "none") {
+		return ptype.LogicalType_NONE
+	}
+	for t := ptype.LogicalType_UTF8; t <= ptype.LogicalType_INTERVAL; t++ {
+		if strings.EqualFold(name, ptype.LogicalTypeToString(t)) {
+			return t
+		}
+	}
+	panic(fmt.Errorf("Unknown logical type '%s' on field %s", name, field.Name))
+}
+
+func parseTagInt(field reflect.StructField, key string, arg string) int {
+	value, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Errorf("Invalid %s '%s' on field %s", key, arg, field.Name))
+	}
+	return value
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	byteSliceType = reflect.TypeOf([]byte(nil))
+)
+
+// FromStruct derives a schema from a struct type (or a pointer to one).
+// Fields that cannot be represented return an error with the offending
+// field path.
+func FromStruct(t reflect.Type) (root *GroupNode, err error) {
+	defer func() {
+		if failure := recover(); failure != nil {
+			failed, ok := failure.(error)
+			if !ok {
+				panic(failure)
+			}
+			root, err = nil, failed
+		}
+	}()
+	return structSchema(t), nil
+}
+
+// FromStruct for types known to be valid, panics if t cannot be mapped
+func MustFromStruct(t reflect.Type) *GroupNode {
+	root, err := FromStruct(t)
+	if err != nil {
+		panic(err)
+	}
+	return root
+}
+
+func structSchema(t reflect.Type) *GroupNode {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("Cannot derive a schema from non-struct type %s", t))
+	}
+	name := t.Name()
+	if name == "" {
+		name = "schema"
+	}
+	return NewGroupNode(name, ptype.Repetition_REQUIRED, structFields(t, t.Name(), nil))
+}
+
+// parents are the struct types enclosing t, which cannot hold t again
+func structFields(t reflect.Type, path string, parents []reflect.Type) []*Node {
+	for _, parent := range parents {
+		if parent == t {
+			panic(fmt.Errorf("Recursive struct type %s at field %s is not supported", t, path))
+		}
+	}
+	parents = append(parents[:len(parents):len(parents)], t)
+	var fields []*Node
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		tag := ParseFieldTag(field)
+		if tag.Skip {
+			continue
+		}
+		fields = append(fields, fieldNode(field.Type, tag, path+"."+field.Name, parents))
+	}
+	if len(fields) == 0 {
+		panic(fmt.Errorf("Struct %s has no exported fields to map", path))
+	}
+	return fields
+}
+
+func fieldNode(t reflect.Type, tag FieldTag, path string, parents []reflect.Type) *Node {
+	repetition := ptype.Repetition_REQUIRED
+	if tag.Optional {
+		repetition = ptype.Repetition_OPTIONAL
+	}
+	if t.Kind() == reflect.Ptr {
+		if tag.Required {
+			panic(fmt.Errorf("Field %s is a pointer and cannot be required", path))
+		}
+		repetition = ptype.Repetition_OPTIONAL
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			panic(fmt.Errorf("Field %s: pointers to pointers are not supported", path))
+		}
+	}
+	if tag.Repeated && (t.Kind() != reflect.Slice || t == byteSliceType) {
+		panic(fmt.Errorf("Field %s: only slices other than []byte can be repeated, got %s",
+			path, t))
+	}
+
+	switch {
+	case t == timeType || t == byteSliceType:
+		return primitiveFieldNode(t, repetition, tag, path)
+	case t.Kind() == reflect.Slice:
+		elemTag := FieldTag{Precision: tag.Precision, Scale: tag.Scale, Id: -1,
+			Logical: tag.Logical, HasLogical: tag.HasLogical}
+		if tag.Repeated {
+			if repetition == ptype.Repetition_OPTIONAL {
+				panic(fmt.Errorf("Field %s cannot be both optional and repeated", path))
+			}
+			if t.Elem().Kind() == reflect.Ptr {
+				panic(fmt.Errorf("Field %s: repeated fields cannot hold pointers", path))
+			}
+			elemTag.Name = tag.Name
+			elemTag.Id = tag.Id
+			elemTag.Required = true
+			node := fieldNode(t.Elem(), elemTag, path+"[]", parents)
+			node.repetition = ptype.Repetition_REPEATED
+			return node
+		}
+		elemTag.Name = "element"
+		element := fieldNode(t.Elem(), elemTag, path+"[]", parents)
+		list := GroupNodeMake("list", ptype.Repetition_REPEATED, []*Node{element})
+		return GroupNodeMake(tag.Name, repetition, []*Node{list},
+			int(ptype.LogicalType_LIST), tag.Id)
+	case t.Kind() == reflect.Map:
+		keyTag := FieldTag{Name: "key", Required: true, Precision: -1, Scale: -1, Id: -1}
+		key := fieldNode(t.Key(), keyTag, path+".key", parents)
+		if !key.IsPrimitive() {
+			panic(fmt.Errorf("Field %s: map keys must be primitive, got %s", path, t.Key()))
+		}
+		valueTag := FieldTag{Name: "value", Precision: tag.Precision, Scale: tag.Scale,
+			Id: -1, Logical: tag.Logical, HasLogical: tag.HasLogical}
+		value := fieldNode(t.Elem(), valueTag, path+".value", parents)
+		keyValue := GroupNodeMake("key_value", ptype.Repetition_REPEATED,
+			[]*Node{key, value}, int(ptype.LogicalType_MAP_KEY_VALUE))
+		return GroupNodeMake(tag.Name, repetition, []*Node{keyValue},
+			int(ptype.LogicalType_MAP), tag.Id)
+	case t.Kind() == reflect.Struct:
+		return GroupNodeMake(tag.Name, repetition, structFields(t, path, parents),
+			int(ptype.LogicalType_NONE), tag.Id)
+	default:
+		return primitiveFieldNode(t, repetition, tag, path)
+	}
+}
+
+func primitiveFieldNode(t reflect.Type, repetition ptype.Repetition, tag FieldTag,
+	path string) *Node {
+	var physicalType ptype.Type
+	logicalType := ptype.LogicalType_NONE
+	length := -1
+
+	switch t.Kind() {
+	case reflect.Bool:
+		physicalType = ptype.Type_BOOLEAN
+	case reflect.Int8:
+		physicalType, logicalType = ptype.Type_INT32, ptype.LogicalType_INT_8
+	case reflect.Int16:
+		physicalType, logicalType = ptype.Type_INT32, ptype.LogicalType_INT_16
+	case reflect.Int32:
+		physicalType = ptype.Type_INT32
+	case reflect.Int, reflect.Int64:
+		physicalType = ptype.Type_INT64
+	case reflect.Uint8:
+		physicalType, logicalType = ptype.Type_INT32, ptype.LogicalType_UINT_8
+	case reflect.Uint16:
+		physicalType, logicalType = ptype.Type_INT32, ptype.LogicalType_UINT_16
+	case reflect.Uint32:
+		physicalType, logicalType = ptype.Type_INT32, ptype.LogicalType_UINT_32
+	case reflect.Uint, reflect.Uint64:
+		physicalType, logicalType = ptype.Type_INT64, ptype.LogicalType_UINT_64
+	case reflect.Float32:
+		physicalType = ptype.Type_FLOAT
+	case reflect.Float64:
+		physicalType = ptype.Type_DOUBLE
+	case reflect.String:
+		physicalType, logicalType = ptype.Type_BYTE_ARRAY, ptype.LogicalType_UTF8
+	case reflect.Slice:
+		// only []byte gets here
+		physicalType = ptype.Type_BYTE_ARRAY
+	case reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			panic(fmt.Errorf("Field %s: unsupported array type %s, only byte arrays "+
+				"can be mapped", path, t))
+		}
+		physicalType = ptype.Type_FIXED_LEN_BYTE_ARRAY
+		length = t.Len()
+	case reflect.Struct:
+		// only time.Time gets here
+		physicalType, logicalType = ptype.Type_INT64, ptype.LogicalType_TIMESTAMP_MICROS
+		if tag.HasLogical && tag.Logical == ptype.LogicalType_DATE {
+			physicalType = ptype.Type_INT32
+		}
+	default:
+		panic(fmt.Errorf("Field %s: unsupported type %s", path, t))
+	}
+	if tag.HasLogical {
+		logicalType = tag.Logical
+	}
+	if t == timeType {
+		switch logicalType {
+		case ptype.LogicalType_DATE, ptype.LogicalType_TIMESTAMP_MILLIS,
+			ptype.LogicalType_TIMESTAMP_MICROS:
+		default:
+			panic(fmt.Errorf("Field %s: time.Time cannot be stored as %s", path,
+				ptype.LogicalTypeToString(logicalType)))
+		}
+	}
+	if logicalType == ptype.LogicalType_INTERVAL && length != 12 {
+		panic(fmt.Errorf("Field %s: INTERVAL requires a [12]byte field", path))
+	}
+	// Report type / annotation mismatches with the offending field path
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Errorf("Field %s: %v", path, r))
+		}
+	}()
+	return (*Node)(unsafe.Pointer(NewPrimitiveNode(tag.Name, repetition, physicalType,
+		int(logicalType), length, tag.Precision, tag.Scale, tag.Id)))
+}
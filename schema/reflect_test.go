@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenixls2/goparquet/ptype"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	type tagged struct {
+		Plain   int32
+		Named   int32 `parquet:"named"`
+		Default int32 `parquet:",optional"`
+		Skipped int32 `parquet:"-"`
+		Decimal int64 `parquet:"price,required,logical=decimal,precision=18,scale=2,id=4"`
+		List    []int `parquet:"list, repeated"`
+		None    int32 `parquet:",logical=none,id=0"`
+		Time    int64 `parquet:"time,logical=Timestamp_Millis,"`
+	}
+	tests := []FieldTag{
+		{Name: "Plain", Precision: -1, Scale: -1, Id: -1},
+		{Name: "named", Precision: -1, Scale: -1, Id: -1},
+		{Name: "Default", Optional: true, Precision: -1, Scale: -1, Id: -1},
+		{Name: "Skipped", Skip: true, Precision: -1, Scale: -1, Id: -1},
+		{Name: "price", Required: true, Logical: ptype.LogicalType_DECIMAL, HasLogical: true,
+			Precision: 18, Scale: 2, Id: 4},
+		{Name: "list", Repeated: true, Precision: -1, Scale: -1, Id: -1},
+		{Name: "None", HasLogical: true, Precision: -1, Scale: -1, Id: 0},
+		{Name: "time", Logical: ptype.LogicalType_TIMESTAMP_MILLIS, HasLogical: true,
+			Precision: -1, Scale: -1, Id: -1},
+	}
+	structType := reflect.TypeOf(tagged{})
+	for i, expected := range tests {
+		if tag := ParseFieldTag(structType.Field(i)); tag != expected {
+			t.Errorf("%s: %+v, want %+v", structType.Field(i).Name, tag, expected)
+		}
+	}
+}
+
+type reflectName struct {
+	Language string  `parquet:"language"`
+	Country  *string `parquet:"country,logical=enum"`
+}
+
+type reflectDocument struct {
+	Id       int64            `parquet:"id,id=1"`
+	Names    []reflectName    `parquet:"names"`
+	Links    []int64          `parquet:"links,repeated"`
+	Counts   map[string]int32 `parquet:"counts,optional"`
+	Payload  []byte           `parquet:"payload,logical=json"`
+	Hash     [16]byte         `parquet:"hash"`
+	Price    int64            `parquet:"price,logical=decimal,precision=18,scale=2"`
+	Created  time.Time        `parquet:"created"`
+	Day      *time.Time       `parquet:"day,logical=date"`
+	Small    int8
+	Unsigned uint64
+	Ratio    float32
+	Internal string `parquet:"-"`
+	hidden   bool
+}
+
+const reflectDocumentSchema = `message reflectDocument {
+  required int64 id = 1;
+  required group names (LIST) {
+    repeated group list {
+      required group element {
+        required binary language (UTF8);
+        optional binary country (ENUM);
+      }
+    }
+  }
+  repeated int64 links;
+  optional group counts (MAP) {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (UTF8);
+      required int32 value;
+    }
+  }
+  required binary payload (JSON);
+  required fixed_len_byte_array(16) hash;
+  required int64 price (DECIMAL(18,2));
+  required int64 created (TIMESTAMP_MICROS);
+  optional int32 day (DATE);
+  required int32 Small (INT_8);
+  required int64 Unsigned (UINT_64);
+  required float Ratio;
+}
+`
+
+func TestFromStruct(t *testing.T) {
+	root, err := FromStruct(reflect.TypeOf(&reflectDocument{}))
+	if err != nil {
+		t.Fatalf("FromStruct: %v", err)
+	}
+	if printed := Print(&root.Node); printed != reflectDocumentSchema {
+		t.Errorf("derived schema:\n%s\nwant:\n%s", printed, reflectDocumentSchema)
+	}
+}
+
+func fromStructError(t reflect.Type) string {
+	root, err := FromStruct(t)
+	if err == nil {
+		return fmt.Sprint("derived ", Print(&root.Node))
+	}
+	if root != nil {
+		return fmt.Sprint("derived a schema and ", err)
+	}
+	return err.Error()
+}
+
+func TestFromStructInvalid(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		message string
+	}{
+		{42, "non-struct type int"},
+		{struct{ a int32 }{}, "has no exported fields"},
+		{struct {
+			A int32 `parquet:"a,often"`
+		}{}, "Unknown parquet tag option 'often' on field A"},
+		{struct {
+			A int32 `parquet:"a,optional,required"`
+		}{}, "cannot be both optional and required"},
+		{struct {
+			A int32 `parquet:"a,logical=day"`
+		}{}, "Unknown logical type 'day'"},
+		{struct {
+			A int32 `parquet:"a,id=x"`
+		}{}, "Invalid id 'x' on field A"},
+		{struct {
+			A *int32 `parquet:"a,required"`
+		}{}, "is a pointer and cannot be required"},
+		{struct{ A **int32 }{}, "pointers to pointers"},
+		{struct {
+			A []int32 `parquet:"a,optional,repeated"`
+		}{}, "cannot be both optional and repeated"},
+		{struct {
+			A []*int32 `parquet:"a,repeated"`
+		}{}, "repeated fields cannot hold pointers"},
+		{struct{ A map[[2]int32]int32 }{}, "unsupported array type"},
+		{struct{ A map[struct{ K int32 }]int32 }{}, "map keys must be primitive"},
+		{struct{ A chan int32 }{}, "unsupported type chan int32"},
+		{struct {
+			A time.Time `parquet:"a,logical=utf8"`
+		}{}, "time.Time cannot be stored as UTF8"},
+		{struct {
+			A [8]byte `parquet:"a,logical=interval"`
+		}{}, "INTERVAL requires a [12]byte field"},
+		{struct {
+			A string `parquet:"a,logical=date"`
+		}{}, "Field .A"},
+		{struct {
+			A []byte `parquet:"a,repeated"`
+		}{}, "Field .A: only slices other than []byte can be repeated, got []uint8"},
+		{struct {
+			A int32 `parquet:"a,repeated"`
+		}{}, "only slices other than []byte can be repeated, got int32"},
+		{struct {
+			A map[string]int32 `parquet:"a,repeated"`
+		}{}, "can be repeated"},
+	}
+	for _, test := range tests {
+		structType := reflect.TypeOf(test.value)
+		if message := fromStructError(structType); !strings.Contains(message, test.message) {
+			t.Errorf("%s: %q, want %q", structType, message, test.message)
+		}
+	}
+}
+
+type recursiveNode struct {
+	Value    int32
+	Children []recursiveNode
+}
+
+type recursiveA struct{ B *recursiveB }
+type recursiveB struct{ A []recursiveA }
+
+type repeatedStruct struct {
+	First  reflectName
+	Second reflectName
+	Nested struct{ Third reflectName }
+}
+
+func TestFromStructRecursive(t *testing.T) {
+	for _, structType := range []reflect.Type{reflect.TypeOf(recursiveNode{}),
+		reflect.TypeOf(recursiveA{})} {
+		if message := fromStructError(structType); !strings.Contains(message, "Recursive struct type") {
+			t.Errorf("%s: %q, want a recursive type error", structType, message)
+		}
+	}
+	// The same struct type in sibling fields is not recursive
+	root := MustFromStruct(reflect.TypeOf(repeatedStruct{}))
+	if descr := NewSchemaDescriptor(&root.Node); descr.NumColumns() != 6 {
+		t.Errorf("%d columns, want 6", descr.NumColumns())
+	}
+}
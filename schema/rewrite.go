@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/zenixls2/goparquet/ptype"
+	"unsafe"
+)
+
+// How RewriteSchema changes a leaf
+type LeafRewrite struct {
+	Drop bool
+	// The new name of the leaf, empty keeps it
+	Name string
+	// Make the leaf optional if it is required
+	Optional bool
+}
+
+// A copy of the schema with its leaves changed as in rewrites, by column.
+// Groups left without fields are dropped, and maps can only be dropped
+// whole. Panics if no leaf is left.
+func RewriteSchema(descr *SchemaDescriptor, rewrites map[int]LeafRewrite) *GroupNode {
+	root := descr.GroupNode()
+	var fields []*Node
+	for i := 0; i < root.FieldCount(); i++ {
+		if field := rewriteNode(descr, root.Field(i), rewrites); field != nil {
+			fields = append(fields, field)
+		}
+	}
+	if fields == nil {
+		panic(fmt.Errorf("Cannot drop all the columns of the schema"))
+	}
+	return NewGroupNode(root.Name(), root.Repetition(), fields, int(root.LogicalType()),
+		root.Id())
+}
+
+// A copy of node with its leaves rewritten, nil if all of them are dropped
+func rewriteNode(descr *SchemaDescriptor, node *Node, rewrites map[int]LeafRewrite) *Node {
+	if node.IsPrimitive() {
+		rewrite := rewrites[descr.ColumnIndex(ColumnPathFromNode(node).ToDotString())]
+		if rewrite.Drop {
+			return nil
+		}
+		primitive := *(*PrimitiveNode)(unsafe.Pointer(node))
+		if rewrite.Name != "" {
+			primitive.name = rewrite.Name
+		}
+		if rewrite.Optional && primitive.repetition == ptype.Repetition_REQUIRED {
+			primitive.repetition = ptype.Repetition_OPTIONAL
+		}
+		return (*Node)(unsafe.Pointer(&primitive))
+	}
+	group := (*GroupNode)(unsafe.Pointer(node))
+	var fields []*Node
+	leaves := 0
+	for i := 0; i < group.FieldCount(); i++ {
+		if field := rewriteNode(descr, group.Field(i), rewrites); field != nil {
+			fields = append(fields, field)
+			leaves += countLeaves(field)
+		}
+	}
+	if fields == nil {
+		return nil
+	}
+	if node.LogicalType() == ptype.LogicalType_MAP && leaves != countLeaves(node) {
+		panic(fmt.Errorf("Cannot drop a part of map %s",
+			ColumnPathFromNode(node).ToDotString()))
+	}
+	return GroupNodeMake(node.Name(), node.Repetition(), fields, int(node.LogicalType()),
+		node.Id())
+}
+
+func countLeaves(node *Node) int {
+	if node.IsPrimitive() {
+		return 1
+	}
+	group := (*GroupNode)(unsafe.Pointer(node))
+	count := 0
+	for i := 0; i < group.FieldCount(); i++ {
+		count += countLeaves(group.Field(i))
+	}
+	return count
+}
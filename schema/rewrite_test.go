@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestRewriteSchema(t *testing.T) {
+	descr := NewSchemaDescriptor(&MustParse(projectedSchema).Node)
+	tests := []struct {
+		rewrites map[int]LeafRewrite
+		expected string
+	}{
+		{map[int]LeafRewrite{0: {Name: "key", Optional: true}, 1: {Drop: true}, 2: {Optional: true}}, `message projected {
+  optional int64 key;
+  optional group name {
+    optional binary last (UTF8);
+  }
+  optional group counts (MAP) {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (UTF8);
+      optional int32 value;
+    }
+  }
+  repeated group links {
+    required int64 target;
+    optional binary label (UTF8);
+  }
+}
+`},
+		// Groups left empty and whole maps are dropped
+		{map[int]LeafRewrite{1: {Drop: true}, 2: {Drop: true}, 3: {Drop: true}, 4: {Drop: true},
+			5: {Optional: true, Name: "to"}}, `message projected {
+  required int64 id;
+  repeated group links {
+    optional int64 to;
+    optional binary label (UTF8);
+  }
+}
+`},
+	}
+	for _, test := range tests {
+		if printed := Print(&RewriteSchema(descr, test.rewrites).Node); printed != test.expected {
+			t.Errorf("%v: rewritten\n%s\nwant\n%s", test.rewrites, printed, test.expected)
+		}
+	}
+	if printed := Print(descr.SchemaRoot()); printed != Print(&MustParse(projectedSchema).Node) {
+		t.Errorf("the rewrite changed the schema:\n%s", printed)
+	}
+
+	all := make(map[int]LeafRewrite)
+	for i := 0; i < descr.NumColumns(); i++ {
+		all[i] = LeafRewrite{Drop: true}
+	}
+	if message := projectPanic(func() { RewriteSchema(descr, all) }); message !=
+		"Cannot drop all the columns of the schema" {
+		t.Errorf("dropping all the columns: %q", message)
+	}
+	if message := projectPanic(func() { RewriteSchema(descr, map[int]LeafRewrite{4: {Drop: true}}) }); message !=
+		"Cannot drop a part of map counts" {
+		t.Errorf("dropping a map value: %q", message)
+	}
+}
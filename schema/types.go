@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"fmt"
 	"github.com/zenixls2/goparquet/ptype"
+	"github.com/zenixls2/goparquet/thrift"
+	"strings"
 	"unsafe"
 )
 
@@ -15,6 +18,42 @@ type ColumnPath struct {
 	Path []string
 }
 
+func NewColumnPath(path []string) *ColumnPath {
+	return &ColumnPath{Path: path}
+}
+
+func ColumnPathFromDotString(dotString string) *ColumnPath {
+	return NewColumnPath(strings.Split(dotString, "."))
+}
+
+func ColumnPathFromNode(node *Node) *ColumnPath {
+	// Build the path in reverse order as we traverse the nodes to the top
+	var rpath []string
+	cursor := node
+	// The schema node is not part of the ColumnPath
+	for cursor.Parent() != nil {
+		rpath = append(rpath, cursor.Name())
+		cursor = cursor.Parent()
+	}
+
+	// Build ColumnPath in correct order
+	path := make([]string, len(rpath))
+	for i := range rpath {
+		path[i] = rpath[len(rpath)-1-i]
+	}
+	return NewColumnPath(path)
+}
+
+func (c *ColumnPath) Extend(nodeName string) *ColumnPath {
+	path := make([]string, len(c.Path), len(c.Path)+1)
+	copy(path, c.Path)
+	return NewColumnPath(append(path, nodeName))
+}
+
+func (c *ColumnPath) ToDotString() string {
+	return strings.Join(c.Path, ".")
+}
+
 type NodeType int
 
 const (
@@ -72,7 +111,10 @@ func (n *Node) IsRequired() bool {
 }
 
 func (n *Node) Equals(other *Node) bool {
-	return false
+	if n.IsPrimitive() {
+		return (*PrimitiveNode)(unsafe.Pointer(n)).Equals(other)
+	}
+	return (*GroupNode)(unsafe.Pointer(n)).Equals(other)
 }
 
 func (n *Node) Name() string {
@@ -100,6 +142,19 @@ func (n *Node) Parent() *Node {
 }
 
 func (n *Node) ToParquet(opaqueElement interface{}) {
+	if n.IsPrimitive() {
+		(*PrimitiveNode)(unsafe.Pointer(n)).ToParquet(opaqueElement)
+	} else {
+		(*GroupNode)(unsafe.Pointer(n)).ToParquet(opaqueElement)
+	}
+}
+
+// Logical type of a schema element, item 0 of LogicalType is NONE
+func logicalTypeFromParquet(element *thrift.SchemaElement) int {
+	if !element.IsSetConvertedType() {
+		return int(ptype.LogicalType_NONE)
+	}
+	return int(element.GetConvertedType()) + 1
 }
 
 type NodeVisitor struct{}
@@ -115,10 +170,13 @@ func (n *Node) Visit(visitor *NodeVisitor) {}
 func (n *Node) VisitConst(visitor *NodeConstVisitor) {}
 
 func (n *Node) EqualsInternal(other *Node) bool {
-	return true
+	return n._type == other._type && n.name == other.name &&
+		n.repetition == other.repetition && n.logicalType == other.logicalType
 }
 
-func (n *Node) SetParent(pParent *Node) {}
+func (n *Node) SetParent(pParent *Node) {
+	n.parent = pParent
+}
 
 type PrimitiveNode struct {
 	Node
@@ -128,7 +186,20 @@ type PrimitiveNode struct {
 }
 
 func PrimitiveNodeFromParquet(opaqueElement interface{}, id int) *Node {
-	return &Node{}
+	element := opaqueElement.(*thrift.SchemaElement)
+	length, precision, scale := -1, -1, -1
+	if element.IsSetTypeLength() {
+		length = int(element.GetTypeLength())
+	}
+	if element.IsSetPrecision() {
+		precision = int(element.GetPrecision())
+	}
+	if element.IsSetScale() {
+		scale = int(element.GetScale())
+	}
+	return (*Node)(unsafe.Pointer(NewPrimitiveNode(element.GetName(),
+		ptype.Repetition(element.GetRepetitionType()), ptype.Type(element.GetType()),
+		logicalTypeFromParquet(element), length, precision, scale, id)))
 }
 
 func PrimitiveNodeMake(name string, repetition ptype.Repetition, _type ptype.Type,
@@ -149,7 +220,12 @@ func PrimitiveNodeMake(name string, repetition ptype.Repetition, _type ptype.Typ
 	if len(params) > 3 {
 		scale = params[3]
 	}
-	return (*Node)(unsafe.Pointer(NewPrimitiveNode(name, repetition, _type, int(logicalType), length, precision, scale)))
+	id := -1
+	if len(params) > 4 {
+		id = params[4]
+	}
+	return (*Node)(unsafe.Pointer(NewPrimitiveNode(name, repetition, _type,
+		int(logicalType), length, precision, scale, id)))
 }
 
 func Boolean(name string, repetition ptype.Repetition) *Node {
@@ -180,8 +256,16 @@ func ByteArray(name string, repetition ptype.Repetition) *Node {
 	return PrimitiveNodeMake(name, repetition, ptype.Type_BYTE_ARRAY)
 }
 
+func FixedLenByteArray(name string, repetition ptype.Repetition, length int) *Node {
+	return PrimitiveNodeMake(name, repetition, ptype.Type_FIXED_LEN_BYTE_ARRAY,
+		int(ptype.LogicalType_NONE), length)
+}
+
 func (pn *PrimitiveNode) Equals(other *Node) bool {
-	return true
+	if !pn.Node.EqualsInternal(other) {
+		return false
+	}
+	return pn.EqualsInternal((*PrimitiveNode)(unsafe.Pointer(other)))
 }
 
 func (pn *PrimitiveNode) PhysicalType() ptype.Type {
@@ -197,6 +281,29 @@ func (pn *PrimitiveNode) DecimalMetadata() DecimalMetadata {
 }
 
 func (pn *PrimitiveNode) ToParquet(opaqueElement interface{}) {
+	element := opaqueElement.(*thrift.SchemaElement)
+	element.Name = pn.name
+	repetition := pn.repetition.ToThrift()
+	element.RepetitionType = &repetition
+	if pn.logicalType != ptype.LogicalType_NONE {
+		convertedType := pn.logicalType.ToThrift()
+		element.ConvertedType = &convertedType
+	}
+	physicalType := pn.physicalType.ToThrift()
+	element.Type = &physicalType
+	if pn.physicalType == ptype.Type_FIXED_LEN_BYTE_ARRAY {
+		typeLength := pn.typeLength
+		element.TypeLength = &typeLength
+	}
+	if pn.decimalMetadata.Isset {
+		precision, scale := pn.decimalMetadata.Precision, pn.decimalMetadata.Scale
+		element.Precision = &precision
+		element.Scale = &scale
+	}
+	if pn.id >= 0 {
+		fieldId := int32(pn.id)
+		element.FieldID = &fieldId
+	}
 }
 
 func (pn *PrimitiveNode) Visit(visitor *NodeVisitor) {
@@ -227,7 +334,81 @@ func NewPrimitiveNode(name string, repetition ptype.Repetition, _type ptype.Type
 	if len(params) > 4 {
 		id = params[4]
 	}
-	return &PrimitiveNode{}
+	result := &PrimitiveNode{
+		Node: *NewNode(
+			Node_PRIMITIVE,
+			name,
+			repetition,
+			int(logicalType),
+			id,
+		),
+		physicalType: _type,
+		typeLength:   -1,
+	}
+
+	// Check if the physical and logical types match
+	// Mapping referred from Apache parquet-mr as on 2016-02-22
+	switch logicalType {
+	case ptype.LogicalType_NONE:
+		// Logical type not set
+	case ptype.LogicalType_UTF8, ptype.LogicalType_JSON, ptype.LogicalType_BSON:
+		if _type != ptype.Type_BYTE_ARRAY {
+			panic(fmt.Errorf("%s can only annotate BYTE_ARRAY fields",
+				ptype.LogicalTypeToString(logicalType)))
+		}
+	case ptype.LogicalType_DECIMAL:
+		if _type != ptype.Type_INT32 && _type != ptype.Type_INT64 &&
+			_type != ptype.Type_BYTE_ARRAY &&
+			_type != ptype.Type_FIXED_LEN_BYTE_ARRAY {
+			panic(fmt.Errorf("DECIMAL can only annotate INT32, INT64, BYTE_ARRAY, and FIXED"))
+		}
+		if precision <= 0 {
+			panic(fmt.Errorf("Invalid DECIMAL precision: %d", precision))
+		}
+		if scale < 0 {
+			panic(fmt.Errorf("Invalid DECIMAL scale: %d", scale))
+		}
+		if scale > precision {
+			panic(fmt.Errorf("Invalid DECIMAL scale %d cannot be greater than precision %d",
+				scale, precision))
+		}
+		result.decimalMetadata.Isset = true
+		result.decimalMetadata.Precision = int32(precision)
+		result.decimalMetadata.Scale = int32(scale)
+	case ptype.LogicalType_DATE, ptype.LogicalType_TIME_MILLIS,
+		ptype.LogicalType_UINT_8, ptype.LogicalType_UINT_16,
+		ptype.LogicalType_UINT_32, ptype.LogicalType_INT_8,
+		ptype.LogicalType_INT_16, ptype.LogicalType_INT_32:
+		if _type != ptype.Type_INT32 {
+			panic(fmt.Errorf("%s can only annotate INT32",
+				ptype.LogicalTypeToString(logicalType)))
+		}
+	case ptype.LogicalType_TIME_MICROS, ptype.LogicalType_TIMESTAMP_MILLIS,
+		ptype.LogicalType_TIMESTAMP_MICROS, ptype.LogicalType_UINT_64,
+		ptype.LogicalType_INT_64:
+		if _type != ptype.Type_INT64 {
+			panic(fmt.Errorf("%s can only annotate INT64",
+				ptype.LogicalTypeToString(logicalType)))
+		}
+	case ptype.LogicalType_INTERVAL:
+		if _type != ptype.Type_FIXED_LEN_BYTE_ARRAY || length != 12 {
+			panic(fmt.Errorf("INTERVAL can only annotate FIXED_LEN_BYTE_ARRAY(12)"))
+		}
+	case ptype.LogicalType_ENUM:
+		if _type != ptype.Type_BYTE_ARRAY {
+			panic(fmt.Errorf("ENUM can only annotate BYTE_ARRAY fields"))
+		}
+	default:
+		panic(fmt.Errorf("%s can not be applied to a primitive type",
+			ptype.LogicalTypeToString(logicalType)))
+	}
+	if _type == ptype.Type_FIXED_LEN_BYTE_ARRAY {
+		if length <= 0 {
+			panic(fmt.Errorf("Invalid FIXED_LEN_BYTE_ARRAY length: %d", length))
+		}
+		result.typeLength = int32(length)
+	}
+	return result
 }
 
 // For FIXED_LEN_BYTE_ARRAY
@@ -242,33 +423,53 @@ func (pn *PrimitiveNode) SetDecimalMetadata(scale, precision int32) {
 }
 
 func (pn *PrimitiveNode) EqualsInternal(other *PrimitiveNode) bool {
+	if pn.physicalType != other.physicalType || pn.logicalType != other.logicalType {
+		return false
+	}
+	if pn.logicalType == ptype.LogicalType_DECIMAL &&
+		(pn.decimalMetadata.Precision != other.decimalMetadata.Precision ||
+			pn.decimalMetadata.Scale != other.decimalMetadata.Scale) {
+		return false
+	}
+	if pn.physicalType == ptype.Type_FIXED_LEN_BYTE_ARRAY && pn.typeLength != other.typeLength {
+		return false
+	}
 	return true
 }
 
 type GroupNode struct {
 	Node
-	fields []Node
+	fields []*Node
 }
 
-func GroupNodeFromParquet(opaqueElement interface{}, id int, fields []Node) *Node {
-	return &Node{}
+func GroupNodeFromParquet(opaqueElement interface{}, id int, fields []*Node) *Node {
+	element := opaqueElement.(*thrift.SchemaElement)
+	return GroupNodeMake(element.GetName(), ptype.Repetition(element.GetRepetitionType()),
+		fields, logicalTypeFromParquet(element), id)
 }
 
-func GroupNodeMake(name string, repetition ptype.Repetition, fields []Node, params ...int) *Node {
+func GroupNodeMake(name string, repetition ptype.Repetition, fields []*Node, params ...int) *Node {
 	logicalType := ptype.LogicalType_NONE
 	if len(params) > 0 {
 		logicalType = ptype.LogicalType(params[0])
 	}
+	id := -1
+	if len(params) > 1 {
+		id = params[1]
+	}
 	return (*Node)(unsafe.Pointer(
-		NewGroupNode(name, repetition, fields, int(logicalType))))
+		NewGroupNode(name, repetition, fields, int(logicalType), id)))
 }
 
 func (gn *GroupNode) Equals(other *Node) bool {
-	return true
+	if !gn.Node.EqualsInternal(other) {
+		return false
+	}
+	return gn.EqualsInternal((*GroupNode)(unsafe.Pointer(other)))
 }
 
 func (gn *GroupNode) Field(i int) *Node {
-	return &gn.fields[i]
+	return gn.fields[i]
 }
 
 func (gn *GroupNode) FieldCount() int {
@@ -276,6 +477,20 @@ func (gn *GroupNode) FieldCount() int {
 }
 
 func (gn *GroupNode) ToParquet(opaqueElement interface{}) {
+	element := opaqueElement.(*thrift.SchemaElement)
+	element.Name = gn.name
+	numChildren := int32(len(gn.fields))
+	element.NumChildren = &numChildren
+	repetition := gn.repetition.ToThrift()
+	element.RepetitionType = &repetition
+	if gn.logicalType != ptype.LogicalType_NONE {
+		convertedType := gn.logicalType.ToThrift()
+		element.ConvertedType = &convertedType
+	}
+	if gn.id >= 0 {
+		fieldId := int32(gn.id)
+		element.FieldID = &fieldId
+	}
 }
 
 func (gn *GroupNode) Visit(visitor *NodeVisitor) {
@@ -284,7 +499,7 @@ func (gn *GroupNode) Visit(visitor *NodeVisitor) {
 func (gn *GroupNode) VisitConst(visitor *NodeConstVisitor) {
 }
 
-func NewGroupNode(name string, repetition ptype.Repetition, fields []Node,
+func NewGroupNode(name string, repetition ptype.Repetition, fields []*Node,
 	params ...int) *GroupNode {
 	logicalType := ptype.LogicalType_NONE
 	if len(params) > 0 {
@@ -311,5 +526,16 @@ func NewGroupNode(name string, repetition ptype.Repetition, fields []Node,
 }
 
 func (gn *GroupNode) EqualsInternal(other *GroupNode) bool {
+	if gn == other {
+		return true
+	}
+	if len(gn.fields) != len(other.fields) {
+		return false
+	}
+	for i := range gn.fields {
+		if !gn.fields[i].Equals(other.fields[i]) {
+			return false
+		}
+	}
 	return true
 }
@@ -470,19 +470,80 @@ func (p *PageType) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Enum to annotate whether lists of min/max elements inside ColumnIndex
+// are ordered and if so, in which direction.
+type BoundaryOrder int64
+
+const (
+	BoundaryOrder_UNORDERED  BoundaryOrder = 0
+	BoundaryOrder_ASCENDING  BoundaryOrder = 1
+	BoundaryOrder_DESCENDING BoundaryOrder = 2
+)
+
+func (p BoundaryOrder) String() string {
+	switch p {
+	case BoundaryOrder_UNORDERED:
+		return "UNORDERED"
+	case BoundaryOrder_ASCENDING:
+		return "ASCENDING"
+	case BoundaryOrder_DESCENDING:
+		return "DESCENDING"
+	}
+	return "<UNSET>"
+}
+
+func BoundaryOrderFromString(s string) (BoundaryOrder, error) {
+	switch s {
+	case "UNORDERED":
+		return BoundaryOrder_UNORDERED, nil
+	case "ASCENDING":
+		return BoundaryOrder_ASCENDING, nil
+	case "DESCENDING":
+		return BoundaryOrder_DESCENDING, nil
+	}
+	return BoundaryOrder(0), fmt.Errorf("not a valid BoundaryOrder string")
+}
+
+func BoundaryOrderPtr(v BoundaryOrder) *BoundaryOrder { return &v }
+
+func (p BoundaryOrder) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *BoundaryOrder) UnmarshalText(text []byte) error {
+	q, err := BoundaryOrderFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
 // Statistics per row group and per page
 // All fields are optional.
 //
 // Attributes:
-//  - Max: min and max value of the column, encoded in PLAIN encoding
+//  - Max: DEPRECATED: min and max value of the column, encoded in PLAIN encoding.
+// Only valid for the columns in signed order, use min_value / max_value.
 //  - Min
 //  - NullCount: count of null value in the column
 //  - DistinctCount: count of distinct values occurring
+//  - MaxValue: Min and max values for the column, determined by its ColumnOrder.
+// Values are encoded using PLAIN encoding, except that variable-length byte
+// arrays do not include a length prefix.
+//  - MinValue
+//  - IsMaxValueExact: If true, max / min are the actual max / min of the values. Otherwise
+// they are bounds, e.g. truncated prefixes of long byte arrays.
+//  - IsMinValueExact
 type Statistics struct {
 	Max           []byte `thrift:"max,1" json:"max,omitempty"`
 	Min           []byte `thrift:"min,2" json:"min,omitempty"`
 	NullCount     *int64 `thrift:"null_count,3" json:"null_count,omitempty"`
 	DistinctCount *int64 `thrift:"distinct_count,4" json:"distinct_count,omitempty"`
+	MaxValue        []byte `thrift:"max_value,5" json:"max_value,omitempty"`
+	MinValue        []byte `thrift:"min_value,6" json:"min_value,omitempty"`
+	IsMaxValueExact *bool `thrift:"is_max_value_exact,7" json:"is_max_value_exact,omitempty"`
+	IsMinValueExact *bool `thrift:"is_min_value_exact,8" json:"is_min_value_exact,omitempty"`
 }
 
 func NewStatistics() *Statistics {
@@ -518,6 +579,36 @@ func (p *Statistics) GetDistinctCount() int64 {
 	}
 	return *p.DistinctCount
 }
+
+var Statistics_MaxValue_DEFAULT []byte
+
+func (p *Statistics) GetMaxValue() []byte {
+	return p.MaxValue
+}
+
+var Statistics_MinValue_DEFAULT []byte
+
+func (p *Statistics) GetMinValue() []byte {
+	return p.MinValue
+}
+
+var Statistics_IsMaxValueExact_DEFAULT bool
+
+func (p *Statistics) GetIsMaxValueExact() bool {
+	if !p.IsSetIsMaxValueExact() {
+		return Statistics_IsMaxValueExact_DEFAULT
+	}
+	return *p.IsMaxValueExact
+}
+
+var Statistics_IsMinValueExact_DEFAULT bool
+
+func (p *Statistics) GetIsMinValueExact() bool {
+	if !p.IsSetIsMinValueExact() {
+		return Statistics_IsMinValueExact_DEFAULT
+	}
+	return *p.IsMinValueExact
+}
 func (p *Statistics) IsSetMax() bool {
 	return p.Max != nil
 }
@@ -534,6 +625,22 @@ func (p *Statistics) IsSetDistinctCount() bool {
 	return p.DistinctCount != nil
 }
 
+func (p *Statistics) IsSetMaxValue() bool {
+	return p.MaxValue != nil
+}
+
+func (p *Statistics) IsSetMinValue() bool {
+	return p.MinValue != nil
+}
+
+func (p *Statistics) IsSetIsMaxValueExact() bool {
+	return p.IsMaxValueExact != nil
+}
+
+func (p *Statistics) IsSetIsMinValueExact() bool {
+	return p.IsMinValueExact != nil
+}
+
 func (p *Statistics) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -564,6 +671,22 @@ func (p *Statistics) Read(iprot thrift.TProtocol) error {
 			if err := p.readField4(iprot); err != nil {
 				return err
 			}
+		case 5:
+			if err := p.readField5(iprot); err != nil {
+				return err
+			}
+		case 6:
+			if err := p.readField6(iprot); err != nil {
+				return err
+			}
+		case 7:
+			if err := p.readField7(iprot); err != nil {
+				return err
+			}
+		case 8:
+			if err := p.readField8(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -615,6 +738,42 @@ func (p *Statistics) readField4(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *Statistics) readField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.MaxValue = v
+	}
+	return nil
+}
+
+func (p *Statistics) readField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.MinValue = v
+	}
+	return nil
+}
+
+func (p *Statistics) readField7(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.IsMaxValueExact = &v
+	}
+	return nil
+}
+
+func (p *Statistics) readField8(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.IsMinValueExact = &v
+	}
+	return nil
+}
+
 func (p *Statistics) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("Statistics"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -631,6 +790,18 @@ func (p *Statistics) Write(oprot thrift.TProtocol) error {
 	if err := p.writeField4(oprot); err != nil {
 		return err
 	}
+	if err := p.writeField5(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField6(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField7(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField8(oprot); err != nil {
+		return err
+	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
 	}
@@ -700,6 +871,66 @@ func (p *Statistics) writeField4(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *Statistics) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetMaxValue() {
+		if err := oprot.WriteFieldBegin("max_value", thrift.STRING, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:max_value: ", p), err)
+		}
+		if err := oprot.WriteBinary(p.MaxValue); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.max_value (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:max_value: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *Statistics) writeField6(oprot thrift.TProtocol) (err error) {
+	if p.IsSetMinValue() {
+		if err := oprot.WriteFieldBegin("min_value", thrift.STRING, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:min_value: ", p), err)
+		}
+		if err := oprot.WriteBinary(p.MinValue); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.min_value (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:min_value: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *Statistics) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetIsMaxValueExact() {
+		if err := oprot.WriteFieldBegin("is_max_value_exact", thrift.BOOL, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:is_max_value_exact: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.IsMaxValueExact)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.is_max_value_exact (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:is_max_value_exact: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *Statistics) writeField8(oprot thrift.TProtocol) (err error) {
+	if p.IsSetIsMinValueExact() {
+		if err := oprot.WriteFieldBegin("is_min_value_exact", thrift.BOOL, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:is_min_value_exact: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.IsMinValueExact)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.is_min_value_exact (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:is_min_value_exact: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *Statistics) String() string {
 	if p == nil {
 		return "<nil>"
@@ -3027,6 +3258,12 @@ func (p *PageEncodingStats) String() string {
 //  - EncodingStats: Set of all encodings used for pages in this column chunk.
 // This information can be used to determine if all data pages are
 // dictionary encoded for example *
+//  - BloomFilterOffset: Byte offset from beginning of file to Bloom filter data. *
+//  - BloomFilterLength: Size of Bloom filter data including the serialized header, in bytes.
+// Added in 2.10 so readers may not read this field from old files and
+// it can be obtained after the BloomFilterHeader has been deserialized.
+// Writers should write this field so readers can read the bloom filter
+// in a single I/O.
 type ColumnMetaData struct {
 	Type                  Type                 `thrift:"type,1,required" json:"type"`
 	Encodings             []Encoding           `thrift:"encodings,2,required" json:"encodings"`
@@ -3041,6 +3278,8 @@ type ColumnMetaData struct {
 	DictionaryPageOffset  *int64               `thrift:"dictionary_page_offset,11" json:"dictionary_page_offset,omitempty"`
 	Statistics            *Statistics          `thrift:"statistics,12" json:"statistics,omitempty"`
 	EncodingStats         []*PageEncodingStats `thrift:"encoding_stats,13" json:"encoding_stats,omitempty"`
+	BloomFilterOffset     *int64               `thrift:"bloom_filter_offset,14" json:"bloom_filter_offset,omitempty"`
+	BloomFilterLength     *int32               `thrift:"bloom_filter_length,15" json:"bloom_filter_length,omitempty"`
 }
 
 func NewColumnMetaData() *ColumnMetaData {
@@ -3117,6 +3356,24 @@ var ColumnMetaData_EncodingStats_DEFAULT []*PageEncodingStats
 func (p *ColumnMetaData) GetEncodingStats() []*PageEncodingStats {
 	return p.EncodingStats
 }
+
+var ColumnMetaData_BloomFilterOffset_DEFAULT int64
+
+func (p *ColumnMetaData) GetBloomFilterOffset() int64 {
+	if !p.IsSetBloomFilterOffset() {
+		return ColumnMetaData_BloomFilterOffset_DEFAULT
+	}
+	return *p.BloomFilterOffset
+}
+
+var ColumnMetaData_BloomFilterLength_DEFAULT int32
+
+func (p *ColumnMetaData) GetBloomFilterLength() int32 {
+	if !p.IsSetBloomFilterLength() {
+		return ColumnMetaData_BloomFilterLength_DEFAULT
+	}
+	return *p.BloomFilterLength
+}
 func (p *ColumnMetaData) IsSetKeyValueMetadata() bool {
 	return p.KeyValueMetadata != nil
 }
@@ -3137,6 +3394,14 @@ func (p *ColumnMetaData) IsSetEncodingStats() bool {
 	return p.EncodingStats != nil
 }
 
+func (p *ColumnMetaData) IsSetBloomFilterOffset() bool {
+	return p.BloomFilterOffset != nil
+}
+
+func (p *ColumnMetaData) IsSetBloomFilterLength() bool {
+	return p.BloomFilterLength != nil
+}
+
 func (p *ColumnMetaData) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -3220,6 +3485,14 @@ func (p *ColumnMetaData) Read(iprot thrift.TProtocol) error {
 			if err := p.readField13(iprot); err != nil {
 				return err
 			}
+		case 14:
+			if err := p.readField14(iprot); err != nil {
+				return err
+			}
+		case 15:
+			if err := p.readField15(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -3277,14 +3550,14 @@ func (p *ColumnMetaData) readField2(iprot thrift.TProtocol) error {
 	tSlice := make([]Encoding, 0, size)
 	p.Encodings = tSlice
 	for i := 0; i < size; i++ {
-		var _elem0 Encoding
+		var _elem18 Encoding
 		if v, err := iprot.ReadI32(); err != nil {
 			return thrift.PrependError("error reading field 0: ", err)
 		} else {
 			temp := Encoding(v)
-			_elem0 = temp
+			_elem18 = temp
 		}
-		p.Encodings = append(p.Encodings, _elem0)
+		p.Encodings = append(p.Encodings, _elem18)
 	}
 	if err := iprot.ReadListEnd(); err != nil {
 		return thrift.PrependError("error reading list end: ", err)
@@ -3300,13 +3573,13 @@ func (p *ColumnMetaData) readField3(iprot thrift.TProtocol) error {
 	tSlice := make([]string, 0, size)
 	p.PathInSchema = tSlice
 	for i := 0; i < size; i++ {
-		var _elem1 string
+		var _elem19 string
 		if v, err := iprot.ReadString(); err != nil {
 			return thrift.PrependError("error reading field 0: ", err)
 		} else {
-			_elem1 = v
+			_elem19 = v
 		}
-		p.PathInSchema = append(p.PathInSchema, _elem1)
+		p.PathInSchema = append(p.PathInSchema, _elem19)
 	}
 	if err := iprot.ReadListEnd(); err != nil {
 		return thrift.PrependError("error reading list end: ", err)
@@ -3359,11 +3632,11 @@ func (p *ColumnMetaData) readField8(iprot thrift.TProtocol) error {
 	tSlice := make([]*KeyValue, 0, size)
 	p.KeyValueMetadata = tSlice
 	for i := 0; i < size; i++ {
-		_elem2 := &KeyValue{}
-		if err := _elem2.Read(iprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem2), err)
+		_elem20 := &KeyValue{}
+		if err := _elem20.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem20), err)
 		}
-		p.KeyValueMetadata = append(p.KeyValueMetadata, _elem2)
+		p.KeyValueMetadata = append(p.KeyValueMetadata, _elem20)
 	}
 	if err := iprot.ReadListEnd(); err != nil {
 		return thrift.PrependError("error reading list end: ", err)
@@ -3414,11 +3687,11 @@ func (p *ColumnMetaData) readField13(iprot thrift.TProtocol) error {
 	tSlice := make([]*PageEncodingStats, 0, size)
 	p.EncodingStats = tSlice
 	for i := 0; i < size; i++ {
-		_elem3 := &PageEncodingStats{}
-		if err := _elem3.Read(iprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem3), err)
+		_elem21 := &PageEncodingStats{}
+		if err := _elem21.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem21), err)
 		}
-		p.EncodingStats = append(p.EncodingStats, _elem3)
+		p.EncodingStats = append(p.EncodingStats, _elem21)
 	}
 	if err := iprot.ReadListEnd(); err != nil {
 		return thrift.PrependError("error reading list end: ", err)
@@ -3426,6 +3699,24 @@ func (p *ColumnMetaData) readField13(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *ColumnMetaData) readField14(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 14: ", err)
+	} else {
+		p.BloomFilterOffset = &v
+	}
+	return nil
+}
+
+func (p *ColumnMetaData) readField15(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 15: ", err)
+	} else {
+		p.BloomFilterLength = &v
+	}
+	return nil
+}
+
 func (p *ColumnMetaData) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("ColumnMetaData"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -3469,6 +3760,12 @@ func (p *ColumnMetaData) Write(oprot thrift.TProtocol) error {
 	if err := p.writeField13(oprot); err != nil {
 		return err
 	}
+	if err := p.writeField14(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField15(oprot); err != nil {
+		return err
+	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
 	}
@@ -3689,13 +3986,42 @@ func (p *ColumnMetaData) writeField13(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *ColumnMetaData) writeField14(oprot thrift.TProtocol) (err error) {
+	if p.IsSetBloomFilterOffset() {
+		if err := oprot.WriteFieldBegin("bloom_filter_offset", thrift.I64, 14); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 14:bloom_filter_offset: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.BloomFilterOffset)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.bloom_filter_offset (14) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 14:bloom_filter_offset: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnMetaData) writeField15(oprot thrift.TProtocol) (err error) {
+	if p.IsSetBloomFilterLength() {
+		if err := oprot.WriteFieldBegin("bloom_filter_length", thrift.I32, 15); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 15:bloom_filter_length: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(*p.BloomFilterLength)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.bloom_filter_length (15) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 15:bloom_filter_length: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *ColumnMetaData) String() string {
 	if p == nil {
 		return "<nil>"
 	}
 	return fmt.Sprintf("ColumnMetaData(%+v)", *p)
 }
-
 // Attributes:
 //  - FilePath: File where column data is stored.  If not set, assumed to be same file as
 // metadata.  This path is relative to the current file.
@@ -3705,10 +4031,18 @@ func (p *ColumnMetaData) String() string {
 // file_path/file_offset.  Having it here has it replicated in the file
 // metadata.
 //
+//  - OffsetIndexOffset: File offset of ColumnChunk's OffsetIndex *
+//  - OffsetIndexLength: Size of ColumnChunk's OffsetIndex, in bytes *
+//  - ColumnIndexOffset: File offset of ColumnChunk's ColumnIndex *
+//  - ColumnIndexLength: Size of ColumnChunk's ColumnIndex, in bytes *
 type ColumnChunk struct {
-	FilePath   *string         `thrift:"file_path,1" json:"file_path,omitempty"`
-	FileOffset int64           `thrift:"file_offset,2,required" json:"file_offset"`
-	MetaData   *ColumnMetaData `thrift:"meta_data,3" json:"meta_data,omitempty"`
+	FilePath          *string         `thrift:"file_path,1" json:"file_path,omitempty"`
+	FileOffset        int64           `thrift:"file_offset,2,required" json:"file_offset"`
+	MetaData          *ColumnMetaData `thrift:"meta_data,3" json:"meta_data,omitempty"`
+	OffsetIndexOffset *int64          `thrift:"offset_index_offset,4" json:"offset_index_offset,omitempty"`
+	OffsetIndexLength *int32          `thrift:"offset_index_length,5" json:"offset_index_length,omitempty"`
+	ColumnIndexOffset *int64          `thrift:"column_index_offset,6" json:"column_index_offset,omitempty"`
+	ColumnIndexLength *int32          `thrift:"column_index_length,7" json:"column_index_length,omitempty"`
 }
 
 func NewColumnChunk() *ColumnChunk {
@@ -3736,6 +4070,42 @@ func (p *ColumnChunk) GetMetaData() *ColumnMetaData {
 	}
 	return p.MetaData
 }
+
+var ColumnChunk_OffsetIndexOffset_DEFAULT int64
+
+func (p *ColumnChunk) GetOffsetIndexOffset() int64 {
+	if !p.IsSetOffsetIndexOffset() {
+		return ColumnChunk_OffsetIndexOffset_DEFAULT
+	}
+	return *p.OffsetIndexOffset
+}
+
+var ColumnChunk_OffsetIndexLength_DEFAULT int32
+
+func (p *ColumnChunk) GetOffsetIndexLength() int32 {
+	if !p.IsSetOffsetIndexLength() {
+		return ColumnChunk_OffsetIndexLength_DEFAULT
+	}
+	return *p.OffsetIndexLength
+}
+
+var ColumnChunk_ColumnIndexOffset_DEFAULT int64
+
+func (p *ColumnChunk) GetColumnIndexOffset() int64 {
+	if !p.IsSetColumnIndexOffset() {
+		return ColumnChunk_ColumnIndexOffset_DEFAULT
+	}
+	return *p.ColumnIndexOffset
+}
+
+var ColumnChunk_ColumnIndexLength_DEFAULT int32
+
+func (p *ColumnChunk) GetColumnIndexLength() int32 {
+	if !p.IsSetColumnIndexLength() {
+		return ColumnChunk_ColumnIndexLength_DEFAULT
+	}
+	return *p.ColumnIndexLength
+}
 func (p *ColumnChunk) IsSetFilePath() bool {
 	return p.FilePath != nil
 }
@@ -3744,6 +4114,22 @@ func (p *ColumnChunk) IsSetMetaData() bool {
 	return p.MetaData != nil
 }
 
+func (p *ColumnChunk) IsSetOffsetIndexOffset() bool {
+	return p.OffsetIndexOffset != nil
+}
+
+func (p *ColumnChunk) IsSetOffsetIndexLength() bool {
+	return p.OffsetIndexLength != nil
+}
+
+func (p *ColumnChunk) IsSetColumnIndexOffset() bool {
+	return p.ColumnIndexOffset != nil
+}
+
+func (p *ColumnChunk) IsSetColumnIndexLength() bool {
+	return p.ColumnIndexLength != nil
+}
+
 func (p *ColumnChunk) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -3773,6 +4159,22 @@ func (p *ColumnChunk) Read(iprot thrift.TProtocol) error {
 			if err := p.readField3(iprot); err != nil {
 				return err
 			}
+		case 4:
+			if err := p.readField4(iprot); err != nil {
+				return err
+			}
+		case 5:
+			if err := p.readField5(iprot); err != nil {
+				return err
+			}
+		case 6:
+			if err := p.readField6(iprot); err != nil {
+				return err
+			}
+		case 7:
+			if err := p.readField7(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -3817,6 +4219,42 @@ func (p *ColumnChunk) readField3(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *ColumnChunk) readField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.OffsetIndexOffset = &v
+	}
+	return nil
+}
+
+func (p *ColumnChunk) readField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.OffsetIndexLength = &v
+	}
+	return nil
+}
+
+func (p *ColumnChunk) readField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.ColumnIndexOffset = &v
+	}
+	return nil
+}
+
+func (p *ColumnChunk) readField7(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.ColumnIndexLength = &v
+	}
+	return nil
+}
+
 func (p *ColumnChunk) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("ColumnChunk"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -3830,10 +4268,22 @@ func (p *ColumnChunk) Write(oprot thrift.TProtocol) error {
 	if err := p.writeField3(oprot); err != nil {
 		return err
 	}
-	if err := oprot.WriteFieldStop(); err != nil {
-		return thrift.PrependError("write field stop error: ", err)
+	if err := p.writeField4(oprot); err != nil {
+		return err
 	}
-	if err := oprot.WriteStructEnd(); err != nil {
+	if err := p.writeField5(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField6(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField7(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
 		return thrift.PrependError("write struct stop error: ", err)
 	}
 	return nil
@@ -3882,6 +4332,66 @@ func (p *ColumnChunk) writeField3(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *ColumnChunk) writeField4(oprot thrift.TProtocol) (err error) {
+	if p.IsSetOffsetIndexOffset() {
+		if err := oprot.WriteFieldBegin("offset_index_offset", thrift.I64, 4); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:offset_index_offset: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.OffsetIndexOffset)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.offset_index_offset (4) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 4:offset_index_offset: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnChunk) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetOffsetIndexLength() {
+		if err := oprot.WriteFieldBegin("offset_index_length", thrift.I32, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:offset_index_length: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(*p.OffsetIndexLength)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.offset_index_length (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:offset_index_length: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnChunk) writeField6(oprot thrift.TProtocol) (err error) {
+	if p.IsSetColumnIndexOffset() {
+		if err := oprot.WriteFieldBegin("column_index_offset", thrift.I64, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:column_index_offset: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.ColumnIndexOffset)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.column_index_offset (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:column_index_offset: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnChunk) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetColumnIndexLength() {
+		if err := oprot.WriteFieldBegin("column_index_length", thrift.I32, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:column_index_length: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(*p.ColumnIndexLength)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.column_index_length (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:column_index_length: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *ColumnChunk) String() string {
 	if p == nil {
 		return "<nil>"
@@ -4150,6 +4660,190 @@ func (p *RowGroup) String() string {
 	return fmt.Sprintf("RowGroup(%+v)", *p)
 }
 
+// Empty struct to signal the order defined by the physical or logical type
+type TypeDefinedOrder struct {
+}
+
+func NewTypeDefinedOrder() *TypeDefinedOrder {
+	return &TypeDefinedOrder{}
+}
+
+func (p *TypeDefinedOrder) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *TypeDefinedOrder) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("TypeDefinedOrder"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *TypeDefinedOrder) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("TypeDefinedOrder(%+v)", *p)
+}
+
+// Union to specify the order used for the min_value and max_value fields for a
+// column.
+//
+// Possible values are:
+// * TypeDefinedOrder - the column uses the order defined by its logical or
+//                      physical type (if there is no logical type).
+//
+// If the reader does not support the value of this union, min and max stats
+// for this column should be ignored.
+//
+// Attributes:
+//  - TYPE_ORDER: The sort orders for logical types are: UTF8, ENUM, JSON, BSON (unsigned),
+// DECIMAL (signed, big endian two's complement for byte arrays), INT_* and
+// TIMESTAMP_* (signed), UINT_* (unsigned), DATE, TIME_* (signed), INTERVAL
+// (undefined). Primitive types without a logical type: BOOLEAN, INT32, INT64,
+// FLOAT, DOUBLE (signed), BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY (unsigned), INT96
+// (undefined).
+type ColumnOrder struct {
+	TYPE_ORDER *TypeDefinedOrder `thrift:"TYPE_ORDER,1" json:"TYPE_ORDER,omitempty"`
+}
+
+func NewColumnOrder() *ColumnOrder {
+	return &ColumnOrder{}
+}
+
+var ColumnOrder_TYPE_ORDER_DEFAULT *TypeDefinedOrder
+
+func (p *ColumnOrder) GetTYPE_ORDER() *TypeDefinedOrder {
+	if !p.IsSetTYPE_ORDER() {
+		return ColumnOrder_TYPE_ORDER_DEFAULT
+	}
+	return p.TYPE_ORDER
+}
+func (p *ColumnOrder) IsSetTYPE_ORDER() bool {
+	return p.TYPE_ORDER != nil
+}
+
+func (p *ColumnOrder) CountSetFieldsColumnOrder() int {
+	count := 0
+	if p.IsSetTYPE_ORDER() {
+		count++
+	}
+	return count
+}
+
+func (p *ColumnOrder) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *ColumnOrder) readField1(iprot thrift.TProtocol) error {
+	p.TYPE_ORDER = &TypeDefinedOrder{}
+	if err := p.TYPE_ORDER.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.TYPE_ORDER), err)
+	}
+	return nil
+}
+
+func (p *ColumnOrder) Write(oprot thrift.TProtocol) error {
+	if c := p.CountSetFieldsColumnOrder(); c != 1 {
+		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
+	}
+	if err := oprot.WriteStructBegin("ColumnOrder"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnOrder) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetTYPE_ORDER() {
+		if err := oprot.WriteFieldBegin("TYPE_ORDER", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:TYPE_ORDER: ", p), err)
+		}
+		if err := p.TYPE_ORDER.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.TYPE_ORDER), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:TYPE_ORDER: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnOrder) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("ColumnOrder(%+v)", *p)
+}
+
 // Description for file metadata
 //
 // Attributes:
@@ -4167,6 +4861,12 @@ func (p *RowGroup) String() string {
 // <Application> version <App Version> (build <App Build Hash>).
 // e.g. impala version 1.0 (build 6cf94d29b2b7115df4de2c06e2ab4326d721eb55)
 //
+//  - ColumnOrders: Sort order used for the min_value and max_value fields of each column in
+// this file. Each sort order corresponds to one column, determined by its
+// position in the list, matching the position of the column in the schema.
+//
+// Without column_orders, the min_value and max_value fields are undefined
+// and readers fall back to the deprecated min and max fields.
 type FileMetaData struct {
 	Version          int32            `thrift:"version,1,required" json:"version"`
 	Schema           []*SchemaElement `thrift:"schema,2,required" json:"schema"`
@@ -4174,6 +4874,7 @@ type FileMetaData struct {
 	RowGroups        []*RowGroup      `thrift:"row_groups,4,required" json:"row_groups"`
 	KeyValueMetadata []*KeyValue      `thrift:"key_value_metadata,5" json:"key_value_metadata,omitempty"`
 	CreatedBy        *string          `thrift:"created_by,6" json:"created_by,omitempty"`
+	ColumnOrders     []*ColumnOrder   `thrift:"column_orders,7" json:"column_orders,omitempty"`
 }
 
 func NewFileMetaData() *FileMetaData {
@@ -4210,6 +4911,12 @@ func (p *FileMetaData) GetCreatedBy() string {
 	}
 	return *p.CreatedBy
 }
+
+var FileMetaData_ColumnOrders_DEFAULT []*ColumnOrder
+
+func (p *FileMetaData) GetColumnOrders() []*ColumnOrder {
+	return p.ColumnOrders
+}
 func (p *FileMetaData) IsSetKeyValueMetadata() bool {
 	return p.KeyValueMetadata != nil
 }
@@ -4218,6 +4925,10 @@ func (p *FileMetaData) IsSetCreatedBy() bool {
 	return p.CreatedBy != nil
 }
 
+func (p *FileMetaData) IsSetColumnOrders() bool {
+	return p.ColumnOrders != nil
+}
+
 func (p *FileMetaData) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -4265,6 +4976,10 @@ func (p *FileMetaData) Read(iprot thrift.TProtocol) error {
 			if err := p.readField6(iprot); err != nil {
 				return err
 			}
+		case 7:
+			if err := p.readField7(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -4379,6 +5094,26 @@ func (p *FileMetaData) readField6(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FileMetaData) readField7(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*ColumnOrder, 0, size)
+	p.ColumnOrders = tSlice
+	for i := 0; i < size; i++ {
+		_elem9 := &ColumnOrder{}
+		if err := _elem9.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem9), err)
+		}
+		p.ColumnOrders = append(p.ColumnOrders, _elem9)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
 func (p *FileMetaData) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FileMetaData"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -4401,6 +5136,9 @@ func (p *FileMetaData) Write(oprot thrift.TProtocol) error {
 	if err := p.writeField6(oprot); err != nil {
 		return err
 	}
+	if err := p.writeField7(oprot); err != nil {
+		return err
+	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
 	}
@@ -4516,9 +5254,1424 @@ func (p *FileMetaData) writeField6(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FileMetaData) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetColumnOrders() {
+		if err := oprot.WriteFieldBegin("column_orders", thrift.LIST, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:column_orders: ", p), err)
+		}
+		if err := oprot.WriteListBegin(thrift.STRUCT, len(p.ColumnOrders)); err != nil {
+			return thrift.PrependError("error writing list begin: ", err)
+		}
+		for _, v := range p.ColumnOrders {
+			if err := v.Write(oprot); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+			}
+		}
+		if err := oprot.WriteListEnd(); err != nil {
+			return thrift.PrependError("error writing list end: ", err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:column_orders: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FileMetaData) String() string {
 	if p == nil {
 		return "<nil>"
 	}
 	return fmt.Sprintf("FileMetaData(%+v)", *p)
 }
+
+// Attributes:
+//  - Offset: Offset of the page in the file *
+//  - CompressedPageSize: Size of the page, including header. Sum of compressed_page_size and header
+// length
+//  - FirstRowIndex: Index within the RowGroup of the first row of the page; this means pages
+// change on record boundaries (r = 0).
+type PageLocation struct {
+	Offset             int64 `thrift:"offset,1,required" json:"offset"`
+	CompressedPageSize int32 `thrift:"compressed_page_size,2,required" json:"compressed_page_size"`
+	FirstRowIndex      int64 `thrift:"first_row_index,3,required" json:"first_row_index"`
+}
+
+func NewPageLocation() *PageLocation {
+	return &PageLocation{}
+}
+
+func (p *PageLocation) GetOffset() int64 {
+	return p.Offset
+}
+
+func (p *PageLocation) GetCompressedPageSize() int32 {
+	return p.CompressedPageSize
+}
+
+func (p *PageLocation) GetFirstRowIndex() int64 {
+	return p.FirstRowIndex
+}
+
+func (p *PageLocation) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetOffset bool = false
+	var issetCompressedPageSize bool = false
+	var issetFirstRowIndex bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+			issetOffset = true
+		case 2:
+			if err := p.readField2(iprot); err != nil {
+				return err
+			}
+			issetCompressedPageSize = true
+		case 3:
+			if err := p.readField3(iprot); err != nil {
+				return err
+			}
+			issetFirstRowIndex = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetOffset {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Offset is not set"))
+	}
+	if !issetCompressedPageSize {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field CompressedPageSize is not set"))
+	}
+	if !issetFirstRowIndex {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field FirstRowIndex is not set"))
+	}
+	return nil
+}
+
+func (p *PageLocation) readField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Offset = v
+	}
+	return nil
+}
+
+func (p *PageLocation) readField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.CompressedPageSize = v
+	}
+	return nil
+}
+
+func (p *PageLocation) readField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.FirstRowIndex = v
+	}
+	return nil
+}
+
+func (p *PageLocation) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("PageLocation"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField2(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField3(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *PageLocation) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("offset", thrift.I64, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:offset: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.Offset)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.offset (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:offset: ", p), err)
+	}
+	return err
+}
+
+func (p *PageLocation) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("compressed_page_size", thrift.I32, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:compressed_page_size: ", p), err)
+	}
+	if err := oprot.WriteI32(int32(p.CompressedPageSize)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.compressed_page_size (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:compressed_page_size: ", p), err)
+	}
+	return err
+}
+
+func (p *PageLocation) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("first_row_index", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:first_row_index: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.FirstRowIndex)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.first_row_index (3) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:first_row_index: ", p), err)
+	}
+	return err
+}
+
+func (p *PageLocation) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("PageLocation(%+v)", *p)
+}
+
+// Attributes:
+//  - PageLocations: PageLocations, ordered by increasing PageLocation.offset. It is required
+// that page_locations[i].first_row_index < page_locations[i+1].first_row_index.
+type OffsetIndex struct {
+	PageLocations []*PageLocation `thrift:"page_locations,1,required" json:"page_locations"`
+}
+
+func NewOffsetIndex() *OffsetIndex {
+	return &OffsetIndex{}
+}
+
+func (p *OffsetIndex) GetPageLocations() []*PageLocation {
+	return p.PageLocations
+}
+
+func (p *OffsetIndex) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetPageLocations bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+			issetPageLocations = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetPageLocations {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field PageLocations is not set"))
+	}
+	return nil
+}
+
+func (p *OffsetIndex) readField1(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*PageLocation, 0, size)
+	p.PageLocations = tSlice
+	for i := 0; i < size; i++ {
+		_elem9 := &PageLocation{}
+		if err := _elem9.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem9), err)
+		}
+		p.PageLocations = append(p.PageLocations, _elem9)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *OffsetIndex) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("OffsetIndex"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *OffsetIndex) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("page_locations", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:page_locations: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.PageLocations)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.PageLocations {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:page_locations: ", p), err)
+	}
+	return err
+}
+
+func (p *OffsetIndex) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("OffsetIndex(%+v)", *p)
+}
+
+// Description for ColumnIndex.
+// Each <array-field>[i] refers to the page at OffsetIndex.page_locations[i]
+//
+// Attributes:
+//  - NullPages: A list of Boolean values to determine the validity of the corresponding
+// min and max values. If true, a page contains only null values, and writers
+// have to set the corresponding entries in min_values and max_values to
+// byte[0], so that all lists have the same length. If false, the
+// corresponding entries in min_values and max_values must be valid.
+//  - MinValues: Two lists containing lower and upper bounds for the values of each page
+// determined by the ColumnOrder of the column. These may be the actual
+// minimum and maximum values found on a page, but can also be (more compact)
+// values that do not exist on a page. For example, instead of storing ""Blart
+// Versenwald III", a writer may set min_values[i]="B", max_values[i]="C".
+// Such more compact values must still be valid values within the column's
+// logical type. Readers must make sure that list entries are populated before
+// using them by inspecting null_pages.
+//  - MaxValues
+//  - BoundaryOrder: Stores whether both min_values and max_values are ordered and if so, in
+// which direction. This allows readers to perform binary searches in both
+// lists. Readers cannot assume that max_values[i] <= min_values[i+1], even
+// if the lists are ordered.
+//  - NullCounts: A list containing the number of null values for each page *
+type ColumnIndex struct {
+	NullPages     []bool        `thrift:"null_pages,1,required" json:"null_pages"`
+	MinValues     [][]byte      `thrift:"min_values,2,required" json:"min_values"`
+	MaxValues     [][]byte      `thrift:"max_values,3,required" json:"max_values"`
+	BoundaryOrder BoundaryOrder `thrift:"boundary_order,4,required" json:"boundary_order"`
+	NullCounts    []int64       `thrift:"null_counts,5" json:"null_counts,omitempty"`
+}
+
+func NewColumnIndex() *ColumnIndex {
+	return &ColumnIndex{}
+}
+
+func (p *ColumnIndex) GetNullPages() []bool {
+	return p.NullPages
+}
+
+func (p *ColumnIndex) GetMinValues() [][]byte {
+	return p.MinValues
+}
+
+func (p *ColumnIndex) GetMaxValues() [][]byte {
+	return p.MaxValues
+}
+
+func (p *ColumnIndex) GetBoundaryOrder() BoundaryOrder {
+	return p.BoundaryOrder
+}
+
+var ColumnIndex_NullCounts_DEFAULT []int64
+
+func (p *ColumnIndex) GetNullCounts() []int64 {
+	return p.NullCounts
+}
+func (p *ColumnIndex) IsSetNullCounts() bool {
+	return p.NullCounts != nil
+}
+
+func (p *ColumnIndex) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetNullPages bool = false
+	var issetMinValues bool = false
+	var issetMaxValues bool = false
+	var issetBoundaryOrder bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+			issetNullPages = true
+		case 2:
+			if err := p.readField2(iprot); err != nil {
+				return err
+			}
+			issetMinValues = true
+		case 3:
+			if err := p.readField3(iprot); err != nil {
+				return err
+			}
+			issetMaxValues = true
+		case 4:
+			if err := p.readField4(iprot); err != nil {
+				return err
+			}
+			issetBoundaryOrder = true
+		case 5:
+			if err := p.readField5(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetNullPages {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NullPages is not set"))
+	}
+	if !issetMinValues {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field MinValues is not set"))
+	}
+	if !issetMaxValues {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field MaxValues is not set"))
+	}
+	if !issetBoundaryOrder {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field BoundaryOrder is not set"))
+	}
+	return nil
+}
+
+func (p *ColumnIndex) readField1(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]bool, 0, size)
+	p.NullPages = tSlice
+	for i := 0; i < size; i++ {
+		var _elem10 bool
+		if v, err := iprot.ReadBool(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem10 = v
+		}
+		p.NullPages = append(p.NullPages, _elem10)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnIndex) readField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([][]byte, 0, size)
+	p.MinValues = tSlice
+	for i := 0; i < size; i++ {
+		var _elem11 []byte
+		if v, err := iprot.ReadBinary(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem11 = v
+		}
+		p.MinValues = append(p.MinValues, _elem11)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnIndex) readField3(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([][]byte, 0, size)
+	p.MaxValues = tSlice
+	for i := 0; i < size; i++ {
+		var _elem12 []byte
+		if v, err := iprot.ReadBinary(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem12 = v
+		}
+		p.MaxValues = append(p.MaxValues, _elem12)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnIndex) readField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		temp := BoundaryOrder(v)
+		p.BoundaryOrder = temp
+	}
+	return nil
+}
+
+func (p *ColumnIndex) readField5(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]int64, 0, size)
+	p.NullCounts = tSlice
+	for i := 0; i < size; i++ {
+		var _elem13 int64
+		if v, err := iprot.ReadI64(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem13 = v
+		}
+		p.NullCounts = append(p.NullCounts, _elem13)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnIndex) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("ColumnIndex"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField2(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField3(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField4(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField5(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *ColumnIndex) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("null_pages", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:null_pages: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.BOOL, len(p.NullPages)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.NullPages {
+		if err := oprot.WriteBool(bool(v)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:null_pages: ", p), err)
+	}
+	return err
+}
+
+func (p *ColumnIndex) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("min_values", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:min_values: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRING, len(p.MinValues)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.MinValues {
+		if err := oprot.WriteBinary(v); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:min_values: ", p), err)
+	}
+	return err
+}
+
+func (p *ColumnIndex) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("max_values", thrift.LIST, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:max_values: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRING, len(p.MaxValues)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.MaxValues {
+		if err := oprot.WriteBinary(v); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:max_values: ", p), err)
+	}
+	return err
+}
+
+func (p *ColumnIndex) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("boundary_order", thrift.I32, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:boundary_order: ", p), err)
+	}
+	if err := oprot.WriteI32(int32(p.BoundaryOrder)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.boundary_order (4) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:boundary_order: ", p), err)
+	}
+	return err
+}
+
+func (p *ColumnIndex) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetNullCounts() {
+		if err := oprot.WriteFieldBegin("null_counts", thrift.LIST, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:null_counts: ", p), err)
+		}
+		if err := oprot.WriteListBegin(thrift.I64, len(p.NullCounts)); err != nil {
+			return thrift.PrependError("error writing list begin: ", err)
+		}
+		for _, v := range p.NullCounts {
+			if err := oprot.WriteI64(int64(v)); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+			}
+		}
+		if err := oprot.WriteListEnd(); err != nil {
+			return thrift.PrependError("error writing list end: ", err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:null_counts: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *ColumnIndex) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("ColumnIndex(%+v)", *p)
+}
+
+// Block-based algorithm type annotation. *
+type SplitBlockAlgorithm struct {
+}
+
+func NewSplitBlockAlgorithm() *SplitBlockAlgorithm {
+	return &SplitBlockAlgorithm{}
+}
+
+func (p *SplitBlockAlgorithm) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *SplitBlockAlgorithm) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("SplitBlockAlgorithm"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *SplitBlockAlgorithm) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("SplitBlockAlgorithm(%+v)", *p)
+}
+
+// The algorithm used in Bloom filter. *
+//
+// Attributes:
+//  - BLOCK: Block-based Bloom filter. *
+type BloomFilterAlgorithm struct {
+	BLOCK *SplitBlockAlgorithm `thrift:"BLOCK,1" json:"BLOCK,omitempty"`
+}
+
+func NewBloomFilterAlgorithm() *BloomFilterAlgorithm {
+	return &BloomFilterAlgorithm{}
+}
+
+var BloomFilterAlgorithm_BLOCK_DEFAULT *SplitBlockAlgorithm
+
+func (p *BloomFilterAlgorithm) GetBLOCK() *SplitBlockAlgorithm {
+	if !p.IsSetBLOCK() {
+		return BloomFilterAlgorithm_BLOCK_DEFAULT
+	}
+	return p.BLOCK
+}
+func (p *BloomFilterAlgorithm) IsSetBLOCK() bool {
+	return p.BLOCK != nil
+}
+
+func (p *BloomFilterAlgorithm) CountSetFieldsBloomFilterAlgorithm() int {
+	count := 0
+	if p.IsSetBLOCK() {
+		count++
+	}
+	return count
+}
+
+func (p *BloomFilterAlgorithm) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterAlgorithm) readField1(iprot thrift.TProtocol) error {
+	p.BLOCK = &SplitBlockAlgorithm{}
+	if err := p.BLOCK.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.BLOCK), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterAlgorithm) Write(oprot thrift.TProtocol) error {
+	if c := p.CountSetFieldsBloomFilterAlgorithm(); c != 1 {
+		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
+	}
+	if err := oprot.WriteStructBegin("BloomFilterAlgorithm"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *BloomFilterAlgorithm) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetBLOCK() {
+		if err := oprot.WriteFieldBegin("BLOCK", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:BLOCK: ", p), err)
+		}
+		if err := p.BLOCK.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.BLOCK), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:BLOCK: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *BloomFilterAlgorithm) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("BloomFilterAlgorithm(%+v)", *p)
+}
+
+// Hash strategy type annotation. xxHash is an extremely fast non-cryptographic hash
+// algorithm. It uses 64 bits version of xxHash.
+type XxHash struct {
+}
+
+func NewXxHash() *XxHash {
+	return &XxHash{}
+}
+
+func (p *XxHash) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *XxHash) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("XxHash"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *XxHash) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("XxHash(%+v)", *p)
+}
+
+// The hash function used in Bloom filter. This function takes the hash of a column value
+// using plain encoding.
+//
+// Attributes:
+//  - XXHASH: xxHash Strategy. *
+type BloomFilterHash struct {
+	XXHASH *XxHash `thrift:"XXHASH,1" json:"XXHASH,omitempty"`
+}
+
+func NewBloomFilterHash() *BloomFilterHash {
+	return &BloomFilterHash{}
+}
+
+var BloomFilterHash_XXHASH_DEFAULT *XxHash
+
+func (p *BloomFilterHash) GetXXHASH() *XxHash {
+	if !p.IsSetXXHASH() {
+		return BloomFilterHash_XXHASH_DEFAULT
+	}
+	return p.XXHASH
+}
+func (p *BloomFilterHash) IsSetXXHASH() bool {
+	return p.XXHASH != nil
+}
+
+func (p *BloomFilterHash) CountSetFieldsBloomFilterHash() int {
+	count := 0
+	if p.IsSetXXHASH() {
+		count++
+	}
+	return count
+}
+
+func (p *BloomFilterHash) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHash) readField1(iprot thrift.TProtocol) error {
+	p.XXHASH = &XxHash{}
+	if err := p.XXHASH.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.XXHASH), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHash) Write(oprot thrift.TProtocol) error {
+	if c := p.CountSetFieldsBloomFilterHash(); c != 1 {
+		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
+	}
+	if err := oprot.WriteStructBegin("BloomFilterHash"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHash) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetXXHASH() {
+		if err := oprot.WriteFieldBegin("XXHASH", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:XXHASH: ", p), err)
+		}
+		if err := p.XXHASH.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.XXHASH), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:XXHASH: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *BloomFilterHash) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("BloomFilterHash(%+v)", *p)
+}
+
+// The compression used in the Bloom filter.
+type Uncompressed struct {
+}
+
+func NewUncompressed() *Uncompressed {
+	return &Uncompressed{}
+}
+
+func (p *Uncompressed) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *Uncompressed) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("Uncompressed"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *Uncompressed) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("Uncompressed(%+v)", *p)
+}
+
+// Attributes:
+//  - UNCOMPRESSED
+type BloomFilterCompression struct {
+	UNCOMPRESSED *Uncompressed `thrift:"UNCOMPRESSED,1" json:"UNCOMPRESSED,omitempty"`
+}
+
+func NewBloomFilterCompression() *BloomFilterCompression {
+	return &BloomFilterCompression{}
+}
+
+var BloomFilterCompression_UNCOMPRESSED_DEFAULT *Uncompressed
+
+func (p *BloomFilterCompression) GetUNCOMPRESSED() *Uncompressed {
+	if !p.IsSetUNCOMPRESSED() {
+		return BloomFilterCompression_UNCOMPRESSED_DEFAULT
+	}
+	return p.UNCOMPRESSED
+}
+func (p *BloomFilterCompression) IsSetUNCOMPRESSED() bool {
+	return p.UNCOMPRESSED != nil
+}
+
+func (p *BloomFilterCompression) CountSetFieldsBloomFilterCompression() int {
+	count := 0
+	if p.IsSetUNCOMPRESSED() {
+		count++
+	}
+	return count
+}
+
+func (p *BloomFilterCompression) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterCompression) readField1(iprot thrift.TProtocol) error {
+	p.UNCOMPRESSED = &Uncompressed{}
+	if err := p.UNCOMPRESSED.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.UNCOMPRESSED), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterCompression) Write(oprot thrift.TProtocol) error {
+	if c := p.CountSetFieldsBloomFilterCompression(); c != 1 {
+		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
+	}
+	if err := oprot.WriteStructBegin("BloomFilterCompression"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *BloomFilterCompression) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetUNCOMPRESSED() {
+		if err := oprot.WriteFieldBegin("UNCOMPRESSED", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:UNCOMPRESSED: ", p), err)
+		}
+		if err := p.UNCOMPRESSED.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.UNCOMPRESSED), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:UNCOMPRESSED: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *BloomFilterCompression) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("BloomFilterCompression(%+v)", *p)
+}
+
+// Bloom filter header is stored at beginning of Bloom filter data of each column
+// and followed by its bitset.
+//
+//
+// Attributes:
+//  - NumBytes: The size of bitset in bytes *
+//  - Algorithm: The algorithm for setting bits. *
+//  - Hash: The hash function used for Bloom filter. *
+//  - Compression: The compression used in the Bloom filter *
+type BloomFilterHeader struct {
+	NumBytes    int32                   `thrift:"numBytes,1,required" json:"numBytes"`
+	Algorithm   *BloomFilterAlgorithm   `thrift:"algorithm,2,required" json:"algorithm"`
+	Hash        *BloomFilterHash        `thrift:"hash,3,required" json:"hash"`
+	Compression *BloomFilterCompression `thrift:"compression,4,required" json:"compression"`
+}
+
+func NewBloomFilterHeader() *BloomFilterHeader {
+	return &BloomFilterHeader{}
+}
+
+func (p *BloomFilterHeader) GetNumBytes() int32 {
+	return p.NumBytes
+}
+
+func (p *BloomFilterHeader) GetAlgorithm() *BloomFilterAlgorithm {
+	return p.Algorithm
+}
+
+func (p *BloomFilterHeader) GetHash() *BloomFilterHash {
+	return p.Hash
+}
+
+func (p *BloomFilterHeader) GetCompression() *BloomFilterCompression {
+	return p.Compression
+}
+
+func (p *BloomFilterHeader) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetNumBytes bool = false
+	var issetAlgorithm bool = false
+	var issetHash bool = false
+	var issetCompression bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.readField1(iprot); err != nil {
+				return err
+			}
+			issetNumBytes = true
+		case 2:
+			if err := p.readField2(iprot); err != nil {
+				return err
+			}
+			issetAlgorithm = true
+		case 3:
+			if err := p.readField3(iprot); err != nil {
+				return err
+			}
+			issetHash = true
+		case 4:
+			if err := p.readField4(iprot); err != nil {
+				return err
+			}
+			issetCompression = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetNumBytes {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NumBytes is not set"))
+	}
+	if !issetAlgorithm {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Algorithm is not set"))
+	}
+	if !issetHash {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Hash is not set"))
+	}
+	if !issetCompression {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Compression is not set"))
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) readField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NumBytes = v
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) readField2(iprot thrift.TProtocol) error {
+	p.Algorithm = &BloomFilterAlgorithm{}
+	if err := p.Algorithm.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Algorithm), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) readField3(iprot thrift.TProtocol) error {
+	p.Hash = &BloomFilterHash{}
+	if err := p.Hash.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Hash), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) readField4(iprot thrift.TProtocol) error {
+	p.Compression = &BloomFilterCompression{}
+	if err := p.Compression.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Compression), err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("BloomFilterHeader"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if err := p.writeField1(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField2(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField3(oprot); err != nil {
+		return err
+	}
+	if err := p.writeField4(oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *BloomFilterHeader) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("numBytes", thrift.I32, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:numBytes: ", p), err)
+	}
+	if err := oprot.WriteI32(int32(p.NumBytes)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.numBytes (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:numBytes: ", p), err)
+	}
+	return err
+}
+
+func (p *BloomFilterHeader) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("algorithm", thrift.STRUCT, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:algorithm: ", p), err)
+	}
+	if err := p.Algorithm.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Algorithm), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:algorithm: ", p), err)
+	}
+	return err
+}
+
+func (p *BloomFilterHeader) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("hash", thrift.STRUCT, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:hash: ", p), err)
+	}
+	if err := p.Hash.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Hash), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:hash: ", p), err)
+	}
+	return err
+}
+
+func (p *BloomFilterHeader) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("compression", thrift.STRUCT, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:compression: ", p), err)
+	}
+	if err := p.Compression.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Compression), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:compression: ", p), err)
+	}
+	return err
+}
+
+func (p *BloomFilterHeader) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("BloomFilterHeader(%+v)", *p)
+}